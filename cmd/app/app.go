@@ -9,10 +9,12 @@ import (
 
 	"github.com/chat-socio/backend/configuration"
 	"github.com/chat-socio/backend/infrastructure/http"
+	"github.com/chat-socio/backend/infrastructure/mailer"
 	"github.com/chat-socio/backend/infrastructure/minio"
 	"github.com/chat-socio/backend/infrastructure/nats"
 	"github.com/chat-socio/backend/infrastructure/postgresql"
 	"github.com/chat-socio/backend/infrastructure/redis"
+	"github.com/chat-socio/backend/infrastructure/webhook"
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/internal/handler"
 	"github.com/chat-socio/backend/internal/middleware"
@@ -31,6 +33,18 @@ type Handler struct {
 	Middleware          *middleware.Middleware
 	WebSocketHandler    *handler.WebSocketHandler
 	UploadHandler       *handler.UploadHandler
+
+	ConversationInviteLinkHandler  *handler.ConversationInviteLinkHandler
+	ConversationFolderHandler      *handler.ConversationFolderHandler
+	ChannelHandler                 *handler.ChannelHandler
+	ConversationJoinRequestHandler *handler.ConversationJoinRequestHandler
+	SyncHandler                    *handler.SyncHandler
+	ContactHandler                 *handler.ContactHandler
+	UserBlockHandler               *handler.UserBlockHandler
+	AccountHandler                 *handler.AccountHandler
+	PresenceHandler                *handler.PresenceHandler
+	BotHandler                     *handler.BotHandler
+	AdminHandler                   *handler.AdminHandler
 }
 
 func CreateStream(js natsjs.JetStreamContext) error {
@@ -51,6 +65,14 @@ func CreateStream(js natsjs.JetStreamContext) error {
 		return err
 	}
 
+	_, err = js.AddStream(&natsjs.StreamConfig{
+		Name:     domain.STREAM_NAME_ACCOUNT,
+		Subjects: []string{domain.SUBJECT_WILDCARD_ACCOUNT},
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 
 }
@@ -93,6 +115,12 @@ func RunApp() {
 		panic(err)
 	}
 
+	// Initialize mailer
+	mailer, err := mailer.NewMailer(configuration.ConfigInstance.Mailer)
+	if err != nil {
+		panic(err)
+	}
+
 	// Initialize repositories
 	accountRepository := postgresql.NewAccountRepository(db)
 	userRepository := postgresql.NewUserRepository(db, observability)
@@ -103,14 +131,43 @@ func RunApp() {
 	messageRepository := postgresql.NewMessageRepository(db)
 	userOnlineRepository := postgresql.NewUserOnlineRepository(db)
 	seenMessageRepository := postgresql.NewSeenMessageRepository(db, observability)
+	conversationInviteLinkRepository := postgresql.NewConversationInviteLinkRepository(db)
+	conversationFolderRepository := postgresql.NewConversationFolderRepository(db)
+	messageReactionRepository := postgresql.NewMessageReactionRepository(db)
+	messageViewRepository := redis.NewMessageViewRepository(redisClient)
+	conversationJoinRequestRepository := postgresql.NewConversationJoinRequestRepository(db)
+	messageRateLimitRepository := redis.NewMessageRateLimitRepository(redisClient)
+	presenceRepository := redis.NewPresenceRepository(redisClient)
+	requestRateLimitRepository := redis.NewRequestRateLimitRepository(redisClient)
+	syncEventRepository := postgresql.NewSyncEventRepository(db)
+	contactRepository := postgresql.NewContactRepository(db)
+	userBlockRepository := postgresql.NewUserBlockRepository(db)
+	passwordResetTokenRepository := postgresql.NewPasswordResetTokenRepository(db)
+	emailVerificationTokenRepository := postgresql.NewEmailVerificationTokenRepository(db)
+	dataExportRepository := postgresql.NewDataExportRepository(db)
+	botRepository := postgresql.NewBotRepository(db)
+	adminRepository := postgresql.NewAdminRepository(db)
 
 	// Initialize publisher
 	messagePublisher := nats.NewPublisher(js)
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(accountRepository, userRepository, sessionRepository, sessionCacheRepository, userCacheRepository, observability)
-	conversationUseCase := usecase.NewConversationUseCase(conversationRepository, messageRepository, messagePublisher, userOnlineRepository, userRepository, seenMessageRepository, observability)
+	userUseCase := usecase.NewUserUseCase(accountRepository, userRepository, contactRepository, sessionRepository, sessionCacheRepository, userCacheRepository, passwordResetTokenRepository, emailVerificationTokenRepository, requestRateLimitRepository, storage, messagePublisher, mailer, observability)
+	conversationUseCase := usecase.NewConversationUseCase(conversationRepository, messageRepository, messagePublisher, userOnlineRepository, userRepository, seenMessageRepository, conversationFolderRepository, messageReactionRepository, messageViewRepository, messageRateLimitRepository, userBlockRepository, contactRepository, storage, observability)
 	userOnlineUseCase := usecase.NewUserOnlineUsecase(userOnlineRepository)
+	conversationInviteLinkUseCase := usecase.NewConversationInviteLinkUseCase(conversationRepository, conversationInviteLinkRepository, conversationJoinRequestRepository, messageRepository, userRepository, contactRepository, messagePublisher, observability)
+	conversationFolderUseCase := usecase.NewConversationFolderUseCase(conversationFolderRepository, messagePublisher, observability)
+	channelUseCase := usecase.NewChannelUseCase(conversationRepository, conversationJoinRequestRepository, userRepository, messagePublisher, observability)
+	conversationJoinRequestUseCase := usecase.NewConversationJoinRequestUseCase(conversationRepository, conversationJoinRequestRepository, messageRepository, userRepository, contactRepository, messagePublisher, observability)
+	syncUseCase := usecase.NewSyncUseCase(syncEventRepository, observability)
+	contactUseCase := usecase.NewContactUseCase(contactRepository, userRepository, userBlockRepository, messagePublisher, observability)
+	userBlockUseCase := usecase.NewUserBlockUseCase(userBlockRepository, userRepository, contactRepository, messagePublisher, observability)
+	presenceUseCase := usecase.NewPresenceUseCase(presenceRepository, userRepository, userBlockRepository, contactRepository, messagePublisher, observability)
+	// every instance takes a share of the presence checks, the offline events do not depend on the instance of the connection
+	go presenceUseCase.RunPresenceCheck(ctx)
+	botUseCase := usecase.NewBotUseCase(botRepository, userRepository, webhook.NewHTTPSender(domain.BotWebhookTimeout), storage, messagePublisher, observability)
+	adminUseCase := usecase.NewAdminUseCase(adminRepository, accountRepository, userRepository, sessionRepository, sessionCacheRepository, passwordResetTokenRepository, messagePublisher, mailer, observability)
+	accountUseCase := usecase.NewAccountUseCase(accountRepository, userRepository, sessionRepository, sessionCacheRepository, userCacheRepository, conversationRepository, messageRepository, dataExportRepository, storage, messagePublisher, observability)
 
 	// Initialize the handler
 	handler := &Handler{
@@ -119,12 +176,12 @@ func RunApp() {
 			Obs:         observability,
 		},
 
-		Middleware: middleware.NewMiddleware(sessionCacheRepository, sessionRepository),
+		Middleware: middleware.NewMiddleware(sessionCacheRepository, sessionRepository, accountRepository, botRepository),
 		WebSocketHandler: handler.NewWebSocketHandler(&websocket.HertzUpgrader{
 			CheckOrigin: func(c *app.RequestContext) bool {
 				return true
 			},
-		}, userOnlineUseCase, userUseCase, observability),
+		}, userOnlineUseCase, userUseCase, conversationUseCase, presenceUseCase, botUseCase, observability),
 		ConversationHandler: &handler.ConversationHandler{
 			ConversationUseCase: conversationUseCase,
 			UserUseCase:         userUseCase,
@@ -134,6 +191,59 @@ func RunApp() {
 			Storage: storage,
 			Obs:     observability,
 		},
+		ConversationInviteLinkHandler: &handler.ConversationInviteLinkHandler{
+			ConversationInviteLinkUseCase: conversationInviteLinkUseCase,
+			UserUseCase:                   userUseCase,
+			Obs:                           observability,
+		},
+		ConversationFolderHandler: &handler.ConversationFolderHandler{
+			ConversationFolderUseCase: conversationFolderUseCase,
+			UserUseCase:               userUseCase,
+			Obs:                       observability,
+		},
+		ChannelHandler: &handler.ChannelHandler{
+			ChannelUseCase: channelUseCase,
+			UserUseCase:    userUseCase,
+			Obs:            observability,
+		},
+		ConversationJoinRequestHandler: &handler.ConversationJoinRequestHandler{
+			ConversationJoinRequestUseCase: conversationJoinRequestUseCase,
+			UserUseCase:                    userUseCase,
+			Obs:                            observability,
+		},
+		SyncHandler: &handler.SyncHandler{
+			SyncUseCase: syncUseCase,
+			UserUseCase: userUseCase,
+			Obs:         observability,
+		},
+		ContactHandler: &handler.ContactHandler{
+			ContactUseCase: contactUseCase,
+			UserUseCase:    userUseCase,
+			Obs:            observability,
+		},
+		UserBlockHandler: &handler.UserBlockHandler{
+			UserBlockUseCase: userBlockUseCase,
+			UserUseCase:      userUseCase,
+			Obs:              observability,
+		},
+		AccountHandler: &handler.AccountHandler{
+			AccountUseCase: accountUseCase,
+			Obs:            observability,
+		},
+		PresenceHandler: &handler.PresenceHandler{
+			PresenceUseCase: presenceUseCase,
+			UserUseCase:     userUseCase,
+			Obs:             observability,
+		},
+		BotHandler: &handler.BotHandler{
+			BotUseCase:  botUseCase,
+			UserUseCase: userUseCase,
+			Obs:         observability,
+		},
+		AdminHandler: &handler.AdminHandler{
+			AdminUseCase: adminUseCase,
+			Obs:          observability,
+		},
 	}
 
 	// Init subscriber
@@ -155,6 +265,24 @@ func RunApp() {
 		panic(err)
 	}
 
+	SyncEventSubscriber := nats.NewQueueSubscriber(js, domain.QUEUE_NAME_SYNC_EVENT, domain.CONSUMER_NAME_SYNC_EVENT)
+	err = SyncEventSubscriber.Subscribe(ctx, domain.SUBJECT_NEW_MESSAGE, nats.WrapHandler(syncUseCase.HandleSyncEvent))
+	if err != nil {
+		panic(err)
+	}
+
+	BotWebhookSubscriber := nats.NewQueueSubscriber(js, domain.QUEUE_NAME_BOT_WEBHOOK, domain.CONSUMER_NAME_BOT_WEBHOOK)
+	err = BotWebhookSubscriber.Subscribe(ctx, domain.SUBJECT_NEW_MESSAGE, nats.WrapHandler(botUseCase.HandleBotWebhook))
+	if err != nil {
+		panic(err)
+	}
+
+	DataExportSubscriber := nats.NewQueueSubscriber(js, domain.QUEUE_NAME_DATA_EXPORT, domain.CONSUMER_NAME_DATA_EXPORT)
+	err = DataExportSubscriber.Subscribe(ctx, domain.SUBJECT_DATA_EXPORT, nats.WrapHandler(accountUseCase.HandleDataExport))
+	if err != nil {
+		panic(err)
+	}
+
 	// Initialize the server
 	s := http.NewServer(configuration.ConfigInstance.Server)
 	s.Use(cors.New(cors.Config{
@@ -189,19 +317,107 @@ func SetUpRoutes(s *server.Hertz, handler *Handler) {
 	// Route not use auth middleware
 	s.POST(("/user/register"), handler.UserHandler.Register)
 	s.POST(("/user/login"), handler.UserHandler.Login)
+	s.POST("/user/token/refresh", handler.UserHandler.RefreshToken)
+	s.POST("/user/password/forgot", handler.UserHandler.ForgotPassword)
+	s.POST("/user/password/reset", handler.UserHandler.ResetPassword)
+	s.POST("/user/email/verify", handler.UserHandler.VerifyEmail)
+	s.POST("/user/email/resend", handler.UserHandler.ResendVerificationEmail)
 	// Route use auth middleware
 	authGroup := s.Group("/auth")
 	authGroup.Use(handler.Middleware.AuthMiddleware())
+	// what the accounts whose email is not verified may do
+	emailVerification := configuration.EmailVerificationConfig{}
+	if configuration.ConfigInstance.EmailVerification != nil {
+		emailVerification = *configuration.ConfigInstance.EmailVerification
+	}
+	canCreateConversation := handler.Middleware.VerifiedEmailMiddleware(!emailVerification.Enabled || emailVerification.AllowCreateConversation)
+	canSendMessage := handler.Middleware.VerifiedEmailMiddleware(!emailVerification.Enabled || emailVerification.AllowSendMessage)
 	authGroup.GET("/user/info", handler.UserHandler.GetMyInfo)
 	authGroup.GET("/user/search", handler.UserHandler.GetListUser)
+	authGroup.PUT("/user/profile", handler.UserHandler.UpdateProfile)
+	authGroup.PUT("/user/password", handler.UserHandler.ChangePassword)
+	authGroup.GET("/user/handle/availability", handler.UserHandler.CheckHandleAvailability)
+	authGroup.GET("/user/privacy", handler.UserHandler.GetPrivacySetting)
+	authGroup.PUT("/user/privacy", handler.UserHandler.UpdatePrivacySetting)
+	authGroup.PUT("/user/status", handler.UserHandler.SetStatus)
+	authGroup.DELETE("/user/status", handler.UserHandler.ClearStatus)
+	authGroup.GET("/user/block", handler.UserBlockHandler.GetListBlockedUser)
+	authGroup.POST("/user/:user_id/block", handler.UserBlockHandler.BlockUser)
+	authGroup.DELETE("/user/:user_id/block", handler.UserBlockHandler.UnblockUser)
+	// Account
+	authGroup.POST("/account/data-export", handler.AccountHandler.RequestDataExport)
+	authGroup.GET("/account/data-export", handler.AccountHandler.GetListDataExport)
+	authGroup.POST("/account/deletion", handler.AccountHandler.ScheduleAccountDeletion)
+	authGroup.DELETE("/account/deletion", handler.AccountHandler.CancelAccountDeletion)
+	// Bot
+	authGroup.POST("/bot", handler.BotHandler.CreateBot)
+	authGroup.GET("/bot", handler.BotHandler.GetListBot)
+	authGroup.PUT("/bot/:bot_id", handler.BotHandler.UpdateBot)
+	authGroup.DELETE("/bot/:bot_id", handler.BotHandler.DeleteBot)
+	authGroup.POST("/bot/:bot_id/token", handler.BotHandler.CreateBotToken)
+	authGroup.GET("/bot/:bot_id/token", handler.BotHandler.GetListBotToken)
+	authGroup.DELETE("/bot/:bot_id/token/:token_id", handler.BotHandler.RevokeBotToken)
+	// Presence
+	authGroup.GET("/presence", handler.PresenceHandler.GetListPresence)
 	// Conversation
 	authGroup.GET("/conversation", handler.ConversationHandler.GetListConversation)
-	authGroup.POST("/conversation", handler.ConversationHandler.CreateConversation)
-	// authGroup.GET("/conversation/:conversation_id", handler.ConversationHandler.GetConversationByID)
+	authGroup.POST("/conversation", canCreateConversation, handler.ConversationHandler.CreateConversation)
+	authGroup.PUT("/conversation/:conversation_id", handler.ConversationHandler.UpdateConversation)
+	authGroup.DELETE("/conversation/:conversation_id", handler.ConversationHandler.DeleteConversation)
+	authGroup.POST("/conversation/:conversation_id/archive", handler.ConversationHandler.ArchiveConversation)
+	authGroup.DELETE("/conversation/:conversation_id/archive", handler.ConversationHandler.UnarchiveConversation)
+	authGroup.POST("/conversation/:conversation_id/pin", handler.ConversationHandler.PinConversation)
+	authGroup.DELETE("/conversation/:conversation_id/pin", handler.ConversationHandler.UnpinConversation)
+
+	// Conversation folder
+	authGroup.GET("/conversation-folder", handler.ConversationFolderHandler.GetListFolder)
+	authGroup.POST("/conversation-folder", handler.ConversationFolderHandler.CreateFolder)
+	authGroup.PUT("/conversation-folder/:folder_id", handler.ConversationFolderHandler.UpdateFolder)
+	authGroup.DELETE("/conversation-folder/:folder_id", handler.ConversationFolderHandler.DeleteFolder)
+	authGroup.GET("/conversation/:conversation_id", handler.ConversationHandler.GetConversationByID)
+	authGroup.GET("/conversation/:conversation_id/member", handler.ConversationHandler.GetListConversationMember)
+	authGroup.GET("/conversation/:conversation_id/media", handler.ConversationHandler.GetListMedia)
+	authGroup.GET("/conversation/:conversation_id/file", handler.ConversationHandler.GetListFile)
+	authGroup.GET("/conversation/:conversation_id/link", handler.ConversationHandler.GetListLink)
+	authGroup.GET("/conversation/unread-count", handler.ConversationHandler.GetUnreadCount)
+	authGroup.GET("/conversation/:conversation_id/notification-setting", handler.ConversationHandler.GetNotificationSetting)
+	authGroup.PUT("/conversation/:conversation_id/notification-setting", handler.ConversationHandler.UpdateNotificationSetting)
+
+	// Invite link
+	authGroup.POST("/conversation/:conversation_id/invite-link", handler.ConversationInviteLinkHandler.CreateInviteLink)
+	authGroup.GET("/conversation/:conversation_id/invite-link", handler.ConversationInviteLinkHandler.GetListInviteLink)
+	authGroup.DELETE("/conversation/:conversation_id/invite-link/:invite_link_id", handler.ConversationInviteLinkHandler.RevokeInviteLink)
+	authGroup.GET("/invite/:token", handler.ConversationInviteLinkHandler.PreviewInviteLink)
+	authGroup.POST("/invite/:token/join", handler.ConversationInviteLinkHandler.JoinByInviteLink)
+
+	// Join request
+	authGroup.GET("/conversation/:conversation_id/join-request", handler.ConversationJoinRequestHandler.GetListJoinRequest)
+	authGroup.POST("/conversation/:conversation_id/join-request/:join_request_id/approve", handler.ConversationJoinRequestHandler.ApproveJoinRequest)
+	authGroup.POST("/conversation/:conversation_id/join-request/:join_request_id/reject", handler.ConversationJoinRequestHandler.RejectJoinRequest)
+
+	// Channel
+	authGroup.GET("/channel/search", handler.ChannelHandler.SearchChannel)
+	authGroup.GET("/channel/:conversation_id", handler.ChannelHandler.GetChannel)
+	authGroup.POST("/channel/:conversation_id/join", handler.ChannelHandler.JoinChannel)
+	authGroup.POST("/channel/:conversation_id/leave", handler.ChannelHandler.LeaveChannel)
+
+	// Sync
+	authGroup.GET("/sync", handler.SyncHandler.Sync)
+
+	// Contact
+	authGroup.GET("/contact", handler.ContactHandler.GetListContact)
+	authGroup.DELETE("/contact/:user_id", handler.ContactHandler.RemoveContact)
+	authGroup.POST("/contact/request", handler.ContactHandler.SendContactRequest)
+	authGroup.GET("/contact/request", handler.ContactHandler.GetListContactRequest)
+	authGroup.POST("/contact/request/:contact_request_id/accept", handler.ContactHandler.AcceptContactRequest)
+	authGroup.POST("/contact/request/:contact_request_id/decline", handler.ContactHandler.DeclineContactRequest)
+	authGroup.DELETE("/contact/request/:contact_request_id", handler.ContactHandler.CancelContactRequest)
 
 	// Message
-	authGroup.POST("/message", handler.ConversationHandler.SendMessage)
+	authGroup.POST("/message", canSendMessage, handler.ConversationHandler.SendMessage)
 	authGroup.GET("/message", handler.ConversationHandler.GetListMessage)
+	authGroup.POST("/message/:message_id/reaction", handler.ConversationHandler.AddReaction)
+	authGroup.DELETE("/message/:message_id/reaction", handler.ConversationHandler.RemoveReaction)
 
 	// Upload
 	authGroup.POST("/upload", handler.UploadHandler.UploadFile)
@@ -209,5 +425,29 @@ func SetUpRoutes(s *server.Hertz, handler *Handler) {
 	// Seen message
 	authGroup.POST("/seen-message", handler.ConversationHandler.SeenMessage)
 
+	// Route for the platform admins, every action is written to the audit log
+	adminGroup := s.Group("/admin")
+	adminGroup.Use(handler.Middleware.AuthMiddleware(), handler.Middleware.AdminMiddleware())
+	adminGroup.GET("/user", handler.AdminHandler.GetListUser)
+	adminGroup.GET("/account/:account_id/session", handler.AdminHandler.GetListSession)
+	adminGroup.POST("/account/:account_id/suspend", handler.AdminHandler.SuspendAccount)
+	adminGroup.POST("/account/:account_id/ban", handler.AdminHandler.BanAccount)
+	adminGroup.POST("/account/:account_id/reinstate", handler.AdminHandler.ReinstateAccount)
+	adminGroup.POST("/account/:account_id/password-reset", handler.AdminHandler.ResetPassword)
+	adminGroup.DELETE("/conversation/:conversation_id", handler.AdminHandler.DeleteConversation)
+	adminGroup.GET("/audit-log", handler.AdminHandler.GetListAuditLog)
+
+	// Route use bot token, the bots reuse the handlers of the users
+	botGroup := s.Group("/bot")
+	botGroup.Use(handler.Middleware.BotAuthMiddleware())
+	botGroup.GET("/user/info", handler.UserHandler.GetMyInfo)
+	botGroup.GET("/conversation", handler.ConversationHandler.GetListConversation)
+	botGroup.GET("/conversation/:conversation_id", handler.ConversationHandler.GetConversationByID)
+	botGroup.GET("/conversation/:conversation_id/member", handler.ConversationHandler.GetListConversationMember)
+	botGroup.POST("/message", handler.ConversationHandler.SendMessage)
+	botGroup.GET("/message", handler.ConversationHandler.GetListMessage)
+	botGroup.POST("/message/:message_id/reaction", handler.ConversationHandler.AddReaction)
+	botGroup.DELETE("/message/:message_id/reaction", handler.ConversationHandler.RemoveReaction)
+
 	s.GET("/ws", handler.WebSocketHandler.HandleWebsocket)
 }
@@ -0,0 +1,82 @@
+package clearuserstatus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/infrastructure/mailer"
+	"github.com/chat-socio/backend/infrastructure/minio"
+	"github.com/chat-socio/backend/infrastructure/nats"
+	"github.com/chat-socio/backend/infrastructure/postgresql"
+	"github.com/chat-socio/backend/infrastructure/redis"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/pkg/observability"
+)
+
+// ClearUserStatus clears the custom statuses whose expiry is passed and notifies the related users,
+// it is meant to run periodically.
+func ClearUserStatus() {
+	ctx := context.Background()
+	db, err := postgresql.Connect(ctx, configuration.ConfigInstance.Postgres)
+	if err != nil {
+		log.Println("Error connecting to database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	redisClient := redis.Connect(configuration.ConfigInstance.Redis)
+	defer redisClient.Close()
+
+	natsClient := nats.Connect(configuration.ConfigInstance.Nats.Address)
+	defer natsClient.Drain()
+	js, err := natsClient.JetStream()
+	if err != nil {
+		log.Println("Error connecting to jetstream:", err)
+		os.Exit(1)
+	}
+
+	observability, err := observability.New(observability.Config{
+		ServiceName: configuration.ConfigInstance.Observability.JaegerService,
+	})
+	if err != nil {
+		log.Println("Error initializing observability:", err)
+		os.Exit(1)
+	}
+	storage, err := minio.NewMinioClient(configuration.ConfigInstance.Minio, observability)
+	if err != nil {
+		log.Println("Error connecting to storage:", err)
+		os.Exit(1)
+	}
+	mailer, err := mailer.NewMailer(configuration.ConfigInstance.Mailer)
+	if err != nil {
+		log.Println("Error initializing mailer:", err)
+		os.Exit(1)
+	}
+
+	userUseCase := usecase.NewUserUseCase(
+		postgresql.NewAccountRepository(db),
+		postgresql.NewUserRepository(db, observability),
+		postgresql.NewContactRepository(db),
+		postgresql.NewSessionRepository(db),
+		redis.NewSessionCacheRepository(redisClient),
+		redis.NewUserCacheRepository(redisClient),
+		postgresql.NewPasswordResetTokenRepository(db),
+		postgresql.NewEmailVerificationTokenRepository(db),
+		redis.NewRequestRateLimitRepository(redisClient),
+		storage,
+		nats.NewPublisher(js),
+		mailer,
+		observability,
+	)
+	cleared, err := userUseCase.ClearExpiredStatus(ctx, time.Now())
+	if err != nil {
+		log.Println("Error clearing expired statuses:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Cleared %d expired statuses\n", cleared)
+}
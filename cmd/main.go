@@ -5,7 +5,11 @@ import (
 	"os"
 
 	"github.com/chat-socio/backend/cmd/app"
+	"github.com/chat-socio/backend/cmd/clearuserstatus"
+	"github.com/chat-socio/backend/cmd/mergedm"
 	"github.com/chat-socio/backend/cmd/migrate"
+	"github.com/chat-socio/backend/cmd/purgeaccount"
+	"github.com/chat-socio/backend/cmd/purgesync"
 	"github.com/chat-socio/backend/configuration"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +43,18 @@ var rootCmd = &cobra.Command{
 		case "migrate":
 			// Run the migration service
 			migrate.Migrate()
+		case "merge-dm":
+			// Merge the duplicated DM conversations
+			mergedm.MergeDM()
+		case "purge-sync":
+			// Delete the sync events older than the retention
+			purgesync.PurgeSync()
+		case "purge-account":
+			// Anonymize the accounts whose deletion grace period is over
+			purgeaccount.PurgeAccount()
+		case "clear-user-status":
+			// Clear the custom statuses whose expiry is passed
+			clearuserstatus.ClearUserStatus()
 		default:
 			log.Printf("Unknown service: %s\n", svc)
 			os.Exit(1)
@@ -47,7 +63,7 @@ var rootCmd = &cobra.Command{
 }
 
 func main() {
-	rootCmd.Flags().StringVarP(&svc, "service", "s", "", "Service to run (app, migrate)")
+	rootCmd.Flags().StringVarP(&svc, "service", "s", "", "Service to run (app, migrate, merge-dm, purge-sync, purge-account, clear-user-status)")
 	rootCmd.Flags().StringVarP(&configPath, "config", "c", "./config.yaml", "Path to the config file")
 
 	if err := rootCmd.Execute(); err != nil {
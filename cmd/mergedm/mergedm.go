@@ -0,0 +1,32 @@
+package mergedm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/infrastructure/postgresql"
+)
+
+// MergeDM merges the DMs created more than once for the same pair of users
+// and fills dm_key of the remaining DMs, so it must run once after migration 018.
+func MergeDM() {
+	ctx := context.Background()
+	db, err := postgresql.Connect(ctx, configuration.ConfigInstance.Postgres)
+	if err != nil {
+		log.Println("Error connecting to database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	conversationRepository := postgresql.NewConversationRepository(db)
+	merged, err := conversationRepository.MergeDuplicateDMConversations(ctx)
+	if err != nil {
+		log.Println("Error merging duplicated DM conversations:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Merged %d duplicated DM conversations\n", merged)
+}
@@ -0,0 +1,74 @@
+package purgeaccount
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/infrastructure/minio"
+	"github.com/chat-socio/backend/infrastructure/nats"
+	"github.com/chat-socio/backend/infrastructure/postgresql"
+	"github.com/chat-socio/backend/infrastructure/redis"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/pkg/observability"
+)
+
+// PurgeAccount anonymizes the accounts whose deletion grace period is over, it is meant to run periodically.
+// The messages of the deleted users stay for the other participants.
+func PurgeAccount() {
+	ctx := context.Background()
+	db, err := postgresql.Connect(ctx, configuration.ConfigInstance.Postgres)
+	if err != nil {
+		log.Println("Error connecting to database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	redisClient := redis.Connect(configuration.ConfigInstance.Redis)
+	defer redisClient.Close()
+
+	natsClient := nats.Connect(configuration.ConfigInstance.Nats.Address)
+	defer natsClient.Drain()
+	js, err := natsClient.JetStream()
+	if err != nil {
+		log.Println("Error connecting to jetstream:", err)
+		os.Exit(1)
+	}
+
+	observability, err := observability.New(observability.Config{
+		ServiceName: configuration.ConfigInstance.Observability.JaegerService,
+	})
+	if err != nil {
+		log.Println("Error initializing observability:", err)
+		os.Exit(1)
+	}
+	storage, err := minio.NewMinioClient(configuration.ConfigInstance.Minio, observability)
+	if err != nil {
+		log.Println("Error connecting to storage:", err)
+		os.Exit(1)
+	}
+
+	accountUseCase := usecase.NewAccountUseCase(
+		postgresql.NewAccountRepository(db),
+		postgresql.NewUserRepository(db, observability),
+		postgresql.NewSessionRepository(db),
+		redis.NewSessionCacheRepository(redisClient),
+		redis.NewUserCacheRepository(redisClient),
+		postgresql.NewConversationRepository(db),
+		postgresql.NewMessageRepository(db),
+		postgresql.NewDataExportRepository(db),
+		storage,
+		nats.NewPublisher(js),
+		observability,
+	)
+	deleted, err := accountUseCase.DeleteScheduledAccount(ctx, time.Now())
+	if err != nil {
+		log.Println("Error purging deleted accounts:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Purged %d deleted accounts\n", deleted)
+}
@@ -0,0 +1,34 @@
+package purgesync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/infrastructure/postgresql"
+	"github.com/chat-socio/backend/internal/domain"
+)
+
+// PurgeSync deletes the sync events older than the retention, it is meant to run periodically.
+// The clients with a cursor before the remaining events are asked to do a full resync.
+func PurgeSync() {
+	ctx := context.Background()
+	db, err := postgresql.Connect(ctx, configuration.ConfigInstance.Postgres)
+	if err != nil {
+		log.Println("Error connecting to database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	syncEventRepository := postgresql.NewSyncEventRepository(db)
+	deleted, err := syncEventRepository.DeleteSyncEventBefore(ctx, time.Now().Add(-domain.SyncEventRetention))
+	if err != nil {
+		log.Println("Error purging sync events:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Purged %d sync events\n", deleted)
+}
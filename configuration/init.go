@@ -15,6 +15,9 @@ type Config struct {
 	Nats          *NatsConfig          `yaml:"nats,omitempty"`
 	Observability *ObservabilityConfig `yaml:"observability,omitempty"`
 	Minio         *MinioConfig         `yaml:"minio,omitempty"`
+	Mailer        *MailerConfig        `yaml:"mailer,omitempty"`
+	// EmailVerification is disabled when missing, the new accounts are verified right away
+	EmailVerification *EmailVerificationConfig `yaml:"email_verification,omitempty"`
 }
 
 type ServerConfig struct {
@@ -46,7 +49,7 @@ type WebSocketConfig struct {
 type JWTConfig struct {
 	SecretKey  string `yaml:"secret_key,omitempty"`
 	Issuer     string `yaml:"issuer,omitempty"`
-	Expiration int    `yaml:"expiration,omitempty"` // in seconds
+	Expiration int    `yaml:"expiration,omitempty"` // lifetime of the access tokens in seconds, 15 minutes when unset
 }
 
 type NatsConfig struct {
@@ -68,6 +71,28 @@ type MinioConfig struct {
 	PublicEndpoint string `yaml:"public_endpoint,omitempty"`
 }
 
+type MailerConfig struct {
+	Driver   string `yaml:"driver,omitempty"` // smtp or file
+	Host     string `yaml:"host,omitempty"`
+	Port     int    `yaml:"port,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	From     string `yaml:"from,omitempty"`
+	FilePath string `yaml:"file_path,omitempty"` // the file driver writes to stdout when empty
+	// ResetPasswordURL is the client page receiving the reset token as the token query parameter
+	ResetPasswordURL string `yaml:"reset_password_url,omitempty"`
+	// VerifyEmailURL is the client page receiving the verification token as the token query parameter
+	VerifyEmailURL string `yaml:"verify_email_url,omitempty"`
+}
+
+// EmailVerificationConfig is the policy of the accounts whose email is not verified yet.
+type EmailVerificationConfig struct {
+	Enabled                 bool `yaml:"enabled,omitempty"`
+	AllowLogin              bool `yaml:"allow_login,omitempty"`
+	AllowCreateConversation bool `yaml:"allow_create_conversation,omitempty"`
+	AllowSendMessage        bool `yaml:"allow_send_message,omitempty"`
+}
+
 var ConfigInstance *Config
 
 func LoadConfig(configFilePath string) error {
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/pkg/mailer"
+)
+
+// fileMailer writes the mails to a file, or to the standard output when no file is configured,
+// so the links they carry can be followed without a mail server.
+type fileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(cfg *configuration.MailerConfig) mailer.Mailer {
+	return &fileMailer{
+		path: cfg.FilePath,
+		from: cfg.From,
+	}
+}
+
+// Send implements mailer.Mailer.
+func (f *fileMailer) Send(ctx context.Context, message *mailer.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var w io.Writer = os.Stdout
+	if f.path != "" {
+		file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open mail file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	_, err := fmt.Fprintf(w, "Date: %s\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), f.from, strings.Join(message.To, ", "), message.Subject, message.Body)
+	return err
+}
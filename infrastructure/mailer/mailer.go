@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/pkg/mailer"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+// NewMailer returns the mailer of the configured driver, the file driver is the default for local development.
+func NewMailer(cfg *configuration.MailerConfig) (mailer.Mailer, error) {
+	if cfg == nil {
+		return NewFileMailer(&configuration.MailerConfig{}), nil
+	}
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg), nil
+	case DriverFile, "":
+		return NewFileMailer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver: %s", cfg.Driver)
+	}
+}
@@ -50,6 +50,17 @@ func (m *minioClient) DeleteObject(ctx context.Context, bucketName string, objec
 	return m.client.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
 }
 
+func (m *minioClient) ObjectExists(ctx context.Context, bucketName string, objectName string) (bool, error) {
+	_, err := m.client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (m *minioClient) ListObjects(ctx context.Context, bucketName string, prefix string) ([]storage.ObjectInfo, error) {
 	objectCh := m.client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
 		Prefix:    prefix,
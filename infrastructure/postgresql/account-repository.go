@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/jackc/pgx/v5"
@@ -31,8 +32,8 @@ func (a *accountRepository) CreateAccountUser(ctx context.Context, account *doma
 		return err
 	}
 	defer tx.Rollback(ctx)
-	query := fmt.Sprintf(`INSERT INTO %s (id, username, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`, account.TableName())
-	_, err = tx.Exec(ctx, query, account.ID, account.Username, account.Password, account.CreatedAt, account.UpdatedAt)
+	query := fmt.Sprintf(`INSERT INTO %s (id, username, password, created_at, updated_at, email_verified_at) VALUES ($1, $2, $3, $4, $5, $6)`, account.TableName())
+	_, err = tx.Exec(ctx, query, account.ID, account.Username, account.Password, account.CreatedAt, account.UpdatedAt, account.EmailVerifiedAt)
 	if err != nil {
 		return err
 	}
@@ -93,6 +94,121 @@ func (a *accountRepository) UpdatePassword(ctx context.Context, id string, passw
 	return nil
 }
 
+// ScheduleAccountDeletion implements domain.AccountRepository.
+func (a *accountRepository) ScheduleAccountDeletion(ctx context.Context, id string, deletionScheduledAt *time.Time) error {
+	query := `UPDATE account SET deletion_scheduled_at = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL`
+	_, err := a.db.Exec(ctx, query, deletionScheduledAt, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetListAccountIDToDelete implements domain.AccountRepository.
+func (a *accountRepository) GetListAccountIDToDelete(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM account
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at LIMIT $2`
+	rows, err := a.db.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AnonymizeAccount implements domain.AccountRepository.
+func (a *accountRepository) AnonymizeAccount(ctx context.Context, id string, now time.Time) error {
+	tx, err := a.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// the username and the email stay unique, and an empty password hash never matches
+	query := `
+		UPDATE account SET username = 'deleted:' || id, password = '', deleted_at = $2, updated_at = $2
+		WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := tx.Exec(ctx, query, id, now)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	var userID string
+	query = `
+		UPDATE user_info SET full_name = $2, avatar = '', handle = NULL, bio = '', email = 'deleted:' || id,
+			status_emoji = '', status_text = '', status_expires_at = NULL, deleted_at = $3, updated_at = $3
+		WHERE account_id = $1
+		RETURNING id`
+	err = tx.QueryRow(ctx, query, id, domain.DeletedUserFullName, now).Scan(&userID)
+	if err != nil {
+		return err
+	}
+
+	queries := []string{
+		`UPDATE session SET is_active = false WHERE account_id = $1`,
+		`DELETE FROM password_reset_token WHERE account_id = $1`,
+		`DELETE FROM email_verification_token WHERE account_id = $1`,
+		`DELETE FROM data_export WHERE account_id = $1`,
+	}
+	for _, query := range queries {
+		_, err = tx.Exec(ctx, query, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the groups of the user go to their earliest admin, or else to their earliest member still there
+	query = fmt.Sprintf(`
+		UPDATE conversation_member cm SET role = '%[1]s', updated_at = $2
+		FROM (
+			SELECT DISTINCT ON (heir.conversation_id) heir.id
+			FROM conversation_member owner
+			INNER JOIN conversation_member heir ON heir.conversation_id = owner.conversation_id AND heir.user_id <> owner.user_id
+			INNER JOIN user_info u ON u.id = heir.user_id AND u.deleted_at IS NULL
+			WHERE owner.user_id = $1 AND owner.role = '%[1]s'
+			ORDER BY heir.conversation_id, heir.role = '%[2]s' DESC, heir.created_at, heir.id
+		) heir
+		WHERE cm.id = heir.id`, domain.ConversationMemberRoleOwner, domain.ConversationMemberRoleAdmin)
+	_, err = tx.Exec(ctx, query, userID, now)
+	if err != nil {
+		return err
+	}
+	query = fmt.Sprintf(`UPDATE conversation_member SET role = '%[1]s', updated_at = $2 WHERE user_id = $1 AND role <> '%[1]s'`, domain.ConversationMemberRoleMember)
+	_, err = tx.Exec(ctx, query, userID, now)
+	if err != nil {
+		return err
+	}
+
+	queries = []string{
+		`DELETE FROM contact WHERE user_id = $1 OR friend_id = $1`,
+		`DELETE FROM user_block WHERE user_id = $1 OR blocked_user_id = $1`,
+		`DELETE FROM user_online WHERE user_id = $1`,
+		fmt.Sprintf(`DELETE FROM conversation_join_request WHERE user_id = $1 AND status = '%s'`, domain.JoinRequestStatusPending),
+	}
+	for _, query := range queries {
+		_, err = tx.Exec(ctx, query, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 var _ domain.AccountRepository = (*accountRepository)(nil)
 
 // NewAccountRepository creates a new instance of AccountRepository.
@@ -0,0 +1,211 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type adminRepository struct {
+	db *pgxpool.Pool
+}
+
+// createAdminAuditLog inserts the audit log within the transaction of the action.
+func createAdminAuditLog(ctx context.Context, tx pgx.Tx, auditLog *domain.AdminAuditLog) error {
+	fields, values := auditLog.MapFields()
+	_, err := tx.Exec(ctx, insertQuery(auditLog.TableName(), fields), values...)
+	return err
+}
+
+// GetListAccountWithUser implements domain.AdminRepository.
+func (a *adminRepository) GetListAccountWithUser(ctx context.Context, keyword string, lastID string, limit int) ([]*domain.AccountWithUser, error) {
+	var account domain.Account
+	var user domain.UserInfo
+	accountFields, _ := account.MapFields()
+	userFields, _ := user.MapFields()
+	fields := make([]string, 0, len(accountFields)+len(userFields))
+	for _, field := range accountFields {
+		fields = append(fields, "a."+field)
+	}
+	for _, field := range userFields {
+		fields = append(fields, "u."+field)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM account a INNER JOIN user_info u ON u.account_id = a.id`, strings.Join(fields, ","))
+
+	args := []any{}
+	conditions := []string{}
+	if keyword != "" {
+		conditions = append(conditions, fmt.Sprintf("(u.full_name ILIKE $%d OR u.email ILIKE $%d OR u.handle ILIKE $%d)", len(args)+1, len(args)+2, len(args)+3))
+		args = append(args, "%"+keyword+"%", "%"+keyword+"%", "%"+strings.TrimPrefix(keyword, "@")+"%")
+	}
+	if lastID != "" {
+		conditions = append(conditions, fmt.Sprintf("a.id < $%d", len(args)+1))
+		args = append(args, lastID)
+	}
+	if len(conditions) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
+	}
+	query = fmt.Sprintf("%s ORDER BY a.id DESC LIMIT $%d", query, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domain.AccountWithUser
+	for rows.Next() {
+		accountWithUser := &domain.AccountWithUser{
+			Account: &domain.Account{},
+			User:    &domain.UserInfo{},
+		}
+		_, accountValues := accountWithUser.Account.MapFields()
+		_, userValues := accountWithUser.User.MapFields()
+		if err := rows.Scan(append(accountValues, userValues...)...); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, accountWithUser)
+	}
+	return accounts, rows.Err()
+}
+
+// CreateAdminAuditLog implements domain.AdminRepository.
+func (a *adminRepository) CreateAdminAuditLog(ctx context.Context, auditLog *domain.AdminAuditLog) error {
+	fields, values := auditLog.MapFields()
+	_, err := a.db.Exec(ctx, insertQuery(auditLog.TableName(), fields), values...)
+	return err
+}
+
+// GetListAdminAuditLog implements domain.AdminRepository.
+func (a *adminRepository) GetListAdminAuditLog(ctx context.Context, targetType string, targetID string, lastID string, limit int) ([]*domain.AdminAuditLog, error) {
+	var auditLog domain.AdminAuditLog
+	fields, _ := auditLog.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(fields, ","), auditLog.TableName())
+
+	args := []any{}
+	conditions := []string{}
+	if targetType != "" {
+		conditions = append(conditions, fmt.Sprintf("target_type = $%d", len(args)+1))
+		args = append(args, targetType)
+	}
+	if targetID != "" {
+		conditions = append(conditions, fmt.Sprintf("target_id = $%d", len(args)+1))
+		args = append(args, targetID)
+	}
+	if lastID != "" {
+		conditions = append(conditions, fmt.Sprintf("id < $%d", len(args)+1))
+		args = append(args, lastID)
+	}
+	if len(conditions) > 0 {
+		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
+	}
+	// the ids are UUIDv7, they follow the creation order
+	query = fmt.Sprintf("%s ORDER BY id DESC LIMIT $%d", query, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := a.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []*domain.AdminAuditLog
+	for rows.Next() {
+		var auditLog domain.AdminAuditLog
+		_, values := auditLog.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		auditLogs = append(auditLogs, &auditLog)
+	}
+	return auditLogs, rows.Err()
+}
+
+// UpdateAccountRestriction implements domain.AdminRepository.
+func (a *adminRepository) UpdateAccountRestriction(ctx context.Context, account *domain.Account, auditLog *domain.AdminAuditLog) error {
+	tx, err := a.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE account SET suspended_until = $1, banned_at = $2, restriction_reason = $3, updated_at = $4 WHERE id = $5`
+	_, err = tx.Exec(ctx, query, account.SuspendedUntil, account.BannedAt, account.RestrictionReason, account.UpdatedAt, account.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE session SET is_active = false WHERE account_id = $1`, account.ID)
+	if err != nil {
+		return err
+	}
+	err = createAdminAuditLog(ctx, tx, auditLog)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ResetPassword implements domain.AdminRepository.
+func (a *adminRepository) ResetPassword(ctx context.Context, accountID string, auditLog *domain.AdminAuditLog) error {
+	tx, err := a.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// an empty password hash never matches
+	_, err = tx.Exec(ctx, `UPDATE account SET password = '', updated_at = NOW() WHERE id = $1`, accountID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE session SET is_active = false WHERE account_id = $1`, accountID)
+	if err != nil {
+		return err
+	}
+	err = createAdminAuditLog(ctx, tx, auditLog)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SoftDeleteConversation implements domain.AdminRepository.
+func (a *adminRepository) SoftDeleteConversation(ctx context.Context, conversationID string, now time.Time, auditLog *domain.AdminAuditLog) (bool, error) {
+	tx, err := a.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the members stay so that the conversation can be looked into, a new DM between them can be created
+	query := `UPDATE conversation SET dm_key = NULL, deleted_at = $2, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL`
+	tag, err := tx.Exec(ctx, query, conversationID, now)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	err = createAdminAuditLog(ctx, tx, auditLog)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func NewAdminRepository(db *pgxpool.Pool) domain.AdminRepository {
+	return &adminRepository{
+		db: db,
+	}
+}
+
+var _ domain.AdminRepository = &adminRepository{}
@@ -0,0 +1,232 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type botRepository struct {
+	db *pgxpool.Pool
+}
+
+// insertQuery builds the INSERT of every mapped field of a table.
+func insertQuery(tableName string, fields []string) string {
+	placeholders := make([]string, len(fields))
+	for i := range fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	return fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, tableName, strings.Join(fields, ","), strings.Join(placeholders, ","))
+}
+
+// CreateBot implements domain.BotRepository.
+func (b *botRepository) CreateBot(ctx context.Context, account *domain.Account, user *domain.UserInfo, bot *domain.Bot) error {
+	tx, err := b.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, username, password, created_at, updated_at, email_verified_at) VALUES ($1, $2, $3, $4, $5, $6)`, account.TableName())
+	_, err = tx.Exec(ctx, query, account.ID, account.Username, account.Password, account.CreatedAt, account.UpdatedAt, account.EmailVerifiedAt)
+	if err != nil {
+		return err
+	}
+
+	fields, values := user.MapFields()
+	_, err = tx.Exec(ctx, insertQuery(user.TableName(), fields), values...)
+	if err != nil {
+		return err
+	}
+
+	fields, values = bot.MapFields()
+	_, err = tx.Exec(ctx, insertQuery(bot.TableName(), fields), values...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetBotByID implements domain.BotRepository.
+func (b *botRepository) GetBotByID(ctx context.Context, id string) (*domain.Bot, error) {
+	var bot domain.Bot
+	fields, values := bot.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL`, strings.Join(fields, ","), bot.TableName())
+	err := b.db.QueryRow(ctx, query, id).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+// GetListBotByOwnerID implements domain.BotRepository.
+func (b *botRepository) GetListBotByOwnerID(ctx context.Context, ownerID string) ([]*domain.Bot, error) {
+	var bot domain.Bot
+	fields, _ := bot.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE owner_id = $1 AND deleted_at IS NULL ORDER BY created_at`, strings.Join(fields, ","), bot.TableName())
+	rows, err := b.db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return scanBots(rows)
+}
+
+// CountBotByOwnerID implements domain.BotRepository.
+func (b *botRepository) CountBotByOwnerID(ctx context.Context, ownerID string) (int, error) {
+	var count int
+	err := b.db.QueryRow(ctx, `SELECT COUNT(*) FROM bot WHERE owner_id = $1 AND deleted_at IS NULL`, ownerID).Scan(&count)
+	return count, err
+}
+
+// UpdateBot implements domain.BotRepository.
+func (b *botRepository) UpdateBot(ctx context.Context, bot *domain.Bot) error {
+	query := `UPDATE bot SET webhook_url = $1, webhook_secret = $2, updated_at = $3 WHERE id = $4`
+	_, err := b.db.Exec(ctx, query, bot.WebhookURL, bot.WebhookSecret, bot.UpdatedAt, bot.ID)
+	return err
+}
+
+// DeleteBot implements domain.BotRepository.
+func (b *botRepository) DeleteBot(ctx context.Context, bot *domain.Bot, now time.Time) error {
+	tx, err := b.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE bot SET deleted_at = $2, updated_at = $2 WHERE id = $1`, bot.ID, now)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE bot_token SET revoked_at = $2 WHERE bot_id = $1 AND revoked_at IS NULL`, bot.ID, now)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE user_info SET deleted_at = $2, updated_at = $2 WHERE id = $1`, bot.UserID, now)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateBotToken implements domain.BotRepository.
+func (b *botRepository) CreateBotToken(ctx context.Context, botToken *domain.BotToken) error {
+	fields, values := botToken.MapFields()
+	_, err := b.db.Exec(ctx, insertQuery(botToken.TableName(), fields), values...)
+	return err
+}
+
+// GetListBotTokenByBotID implements domain.BotRepository.
+func (b *botRepository) GetListBotTokenByBotID(ctx context.Context, botID string) ([]*domain.BotToken, error) {
+	var botToken domain.BotToken
+	fields, _ := botToken.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE bot_id = $1 ORDER BY created_at DESC`, strings.Join(fields, ","), botToken.TableName())
+	rows, err := b.db.Query(ctx, query, botID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var botTokens []*domain.BotToken
+	for rows.Next() {
+		var botToken domain.BotToken
+		_, values := botToken.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		botTokens = append(botTokens, &botToken)
+	}
+	return botTokens, rows.Err()
+}
+
+// CountActiveBotToken implements domain.BotRepository.
+func (b *botRepository) CountActiveBotToken(ctx context.Context, botID string) (int, error) {
+	var count int
+	err := b.db.QueryRow(ctx, `SELECT COUNT(*) FROM bot_token WHERE bot_id = $1 AND revoked_at IS NULL`, botID).Scan(&count)
+	return count, err
+}
+
+// RevokeBotToken implements domain.BotRepository.
+func (b *botRepository) RevokeBotToken(ctx context.Context, botID string, tokenID string, now time.Time) (bool, error) {
+	result, err := b.db.Exec(ctx, `UPDATE bot_token SET revoked_at = $3 WHERE id = $1 AND bot_id = $2 AND revoked_at IS NULL`, tokenID, botID, now)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// GetAccountIDByBotToken implements domain.BotRepository.
+func (b *botRepository) GetAccountIDByBotToken(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	query := `
+		WITH token AS (
+			UPDATE bot_token SET last_used_at = $2
+			WHERE token_hash = $1 AND revoked_at IS NULL
+			RETURNING bot_id
+		)
+		SELECT u.account_id FROM token t
+		INNER JOIN bot b ON b.id = t.bot_id
+		INNER JOIN user_info u ON u.id = b.user_id
+		INNER JOIN account a ON a.id = u.account_id
+		WHERE b.deleted_at IS NULL AND a.banned_at IS NULL AND (a.suspended_until IS NULL OR a.suspended_until <= $2)`
+	var accountID string
+	err := b.db.QueryRow(ctx, query, tokenHash, now).Scan(&accountID)
+	if err != nil {
+		return "", err
+	}
+	return accountID, nil
+}
+
+// GetListWebhookBotByUserIDs implements domain.BotRepository.
+func (b *botRepository) GetListWebhookBotByUserIDs(ctx context.Context, userIDs []string) ([]*domain.Bot, error) {
+	var bot domain.Bot
+	fields, _ := bot.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = ANY($1) AND webhook_url != '' AND deleted_at IS NULL`, strings.Join(fields, ","), bot.TableName())
+	rows, err := b.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return scanBots(rows)
+}
+
+// GetListWebhookBotByConversationID implements domain.BotRepository.
+func (b *botRepository) GetListWebhookBotByConversationID(ctx context.Context, conversationID string) ([]*domain.Bot, error) {
+	var bot domain.Bot
+	fields, _ := bot.MapFields()
+	query := fmt.Sprintf(`SELECT b.%s FROM %s b
+		INNER JOIN conversation_member cm ON cm.user_id = b.user_id
+		WHERE cm.conversation_id = $1 AND b.webhook_url != '' AND b.deleted_at IS NULL`, strings.Join(fields, ", b."), bot.TableName())
+	rows, err := b.db.Query(ctx, query, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return scanBots(rows)
+}
+
+func scanBots(rows pgx.Rows) ([]*domain.Bot, error) {
+	defer rows.Close()
+
+	var bots []*domain.Bot
+	for rows.Next() {
+		var bot domain.Bot
+		_, values := bot.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		bots = append(bots, &bot)
+	}
+	return bots, rows.Err()
+}
+
+func NewBotRepository(db *pgxpool.Pool) domain.BotRepository {
+	return &botRepository{
+		db: db,
+	}
+}
+
+var _ domain.BotRepository = &botRepository{}
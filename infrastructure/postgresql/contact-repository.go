@@ -0,0 +1,199 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type contactRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateContactRequest implements domain.ContactRepository.
+func (c *contactRepository) CreateContactRequest(ctx context.Context, contact *domain.Contact) error {
+	fields, values := contact.MapFields()
+	placeholders := make([]string, len(fields))
+	for i := range fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, contact.TableName(), strings.Join(fields, ", "), strings.Join(placeholders, ", "))
+	_, err := c.db.Exec(ctx, query, values...)
+	return err
+}
+
+// GetContactByID implements domain.ContactRepository.
+func (c *contactRepository) GetContactByID(ctx context.Context, id string) (*domain.Contact, error) {
+	var contact domain.Contact
+	fields, values := contact.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, strings.Join(fields, ", "), contact.TableName())
+	err := c.db.QueryRow(ctx, query, id).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// GetListContactBetween implements domain.ContactRepository.
+func (c *contactRepository) GetListContactBetween(ctx context.Context, userID string, otherUserID string) ([]*domain.Contact, error) {
+	var temp domain.Contact
+	fields, _ := temp.MapFields()
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)`, strings.Join(fields, ", "), temp.TableName())
+	rows, err := c.db.Query(ctx, query, userID, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []*domain.Contact
+	for rows.Next() {
+		var contact domain.Contact
+		_, values := contact.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, &contact)
+	}
+	return contacts, rows.Err()
+}
+
+// AcceptContactRequest implements domain.ContactRepository.
+func (c *contactRepository) AcceptContactRequest(ctx context.Context, contact *domain.Contact, reverseContact *domain.Contact) error {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE contact SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`
+	tag, err := tx.Exec(ctx, query, domain.ContactStatusAccepted, contact.UpdatedAt, contact.ID, domain.ContactStatusPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrContactRequestNotFound
+	}
+
+	// a request sent the other way in the meantime is accepted as well
+	query = `
+		INSERT INTO contact (id, user_id, friend_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, friend_id) DO UPDATE SET status = EXCLUDED.status, updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.Exec(ctx, query, reverseContact.ID, reverseContact.UserID, reverseContact.FriendID, domain.ContactStatusAccepted, reverseContact.CreatedAt, reverseContact.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteContactRequest implements domain.ContactRepository.
+func (c *contactRepository) DeleteContactRequest(ctx context.Context, id string) error {
+	tag, err := c.db.Exec(ctx, `DELETE FROM contact WHERE id = $1 AND status = $2`, id, domain.ContactStatusPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrContactRequestNotFound
+	}
+	return nil
+}
+
+// DeleteContact implements domain.ContactRepository.
+func (c *contactRepository) DeleteContact(ctx context.Context, userID string, friendID string) (bool, error) {
+	query := `
+		DELETE FROM contact
+		WHERE status = $3 AND ((user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1))`
+	tag, err := c.db.Exec(ctx, query, userID, friendID, domain.ContactStatusAccepted)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// scanContactsWithUser reads the contacts selected with the other user and whether they are online.
+func scanContactsWithUser(rows pgx.Rows) ([]*domain.ContactWithUser, error) {
+	defer rows.Close()
+
+	var contacts []*domain.ContactWithUser
+	for rows.Next() {
+		var contact domain.ContactWithUser
+		var user domain.UserInfo
+		_, values := contact.MapFields()
+		values = append(values, &user.ID, &user.FullName, &user.Avatar, &user.Type, &contact.IsOnline)
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		contact.User = &user
+		contacts = append(contacts, &contact)
+	}
+	return contacts, rows.Err()
+}
+
+// contactFields selects a contact, the other user and whether they have a connection open.
+func contactFields(otherUserColumn string) string {
+	var temp domain.Contact
+	fields, _ := temp.MapFields()
+	for i, field := range fields {
+		fields[i] = "ct." + field
+	}
+	fields = append(fields, "u.id", "u.full_name", "u.avatar", "u.type",
+		"EXISTS (SELECT 1 FROM user_online uo WHERE uo.user_id = u.id) AS is_online")
+	return fmt.Sprintf("%s FROM contact ct INNER JOIN user_info u ON u.id = ct.%s", strings.Join(fields, ", "), otherUserColumn)
+}
+
+// GetListContactWithUser implements domain.ContactRepository.
+func (c *contactRepository) GetListContactWithUser(ctx context.Context, userID string, keyword string, lastFriendID string, limit int) ([]*domain.ContactWithUser, error) {
+	params := []any{userID, domain.ContactStatusAccepted}
+	condition := "ct.user_id = $1 AND ct.status = $2"
+	if keyword != "" {
+		params = append(params, "%"+keyword+"%")
+		condition = fmt.Sprintf("%s AND u.full_name ILIKE $%d", condition, len(params))
+	}
+	if lastFriendID != "" {
+		params = append(params, lastFriendID)
+		condition = fmt.Sprintf("%s AND ct.friend_id > $%d", condition, len(params))
+	}
+	query := fmt.Sprintf(`SELECT %s WHERE %s ORDER BY ct.friend_id LIMIT %d`, contactFields("friend_id"), condition, limit)
+	rows, err := c.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	return scanContactsWithUser(rows)
+}
+
+// GetListContactRequestWithUser implements domain.ContactRepository.
+func (c *contactRepository) GetListContactRequestWithUser(ctx context.Context, userID string, outgoing bool) ([]*domain.ContactWithUser, error) {
+	userColumn, otherUserColumn := "friend_id", "user_id"
+	if outgoing {
+		userColumn, otherUserColumn = "user_id", "friend_id"
+	}
+	query := fmt.Sprintf(`SELECT %s WHERE ct.%s = $1 AND ct.status = $2 ORDER BY ct.created_at DESC`, contactFields(otherUserColumn), userColumn)
+	rows, err := c.db.Query(ctx, query, userID, domain.ContactStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	return scanContactsWithUser(rows)
+}
+
+// GetListFriendID implements domain.ContactRepository.
+func (c *contactRepository) GetListFriendID(ctx context.Context, userID string, friendIDs []string) ([]string, error) {
+	query := `SELECT friend_id FROM contact WHERE user_id = $1 AND status = $2 AND friend_id = ANY($3)`
+	rows, err := c.db.Query(ctx, query, userID, domain.ContactStatusAccepted, friendIDs)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
+var _ domain.ContactRepository = &contactRepository{}
+
+func NewContactRepository(db *pgxpool.Pool) *contactRepository {
+	return &contactRepository{db: db}
+}
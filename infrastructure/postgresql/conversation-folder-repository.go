@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type conversationFolderRepository struct {
+	db *pgxpool.Pool
+}
+
+// insertFolderItems adds the conversations of the folder, skipping those the owner is not a member of.
+func insertFolderItems(ctx context.Context, tx pgx.Tx, folder *domain.ConversationFolder) error {
+	if len(folder.ConversationIDs) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO conversation_folder_item (folder_id, conversation_id, created_at)
+		SELECT $1, conversation_id, $2 FROM conversation_member
+		WHERE user_id = $3 AND conversation_id = ANY($4)
+		ON CONFLICT DO NOTHING`
+	_, err := tx.Exec(ctx, query, folder.ID, folder.UpdatedAt, folder.UserID, folder.ConversationIDs)
+	return err
+}
+
+// CreateFolder implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) CreateFolder(ctx context.Context, folder *domain.ConversationFolder) error {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO conversation_folder (id, user_id, name, conversation_types, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.Exec(ctx, query, folder.ID, folder.UserID, folder.Name, folder.ConversationTypes, folder.Position, folder.CreatedAt, folder.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	err = insertFolderItems(ctx, tx, folder)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateFolder implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) UpdateFolder(ctx context.Context, folder *domain.ConversationFolder) error {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE conversation_folder SET name = $1, conversation_types = $2, position = $3, updated_at = $4 WHERE id = $5`
+	_, err = tx.Exec(ctx, query, folder.Name, folder.ConversationTypes, folder.Position, folder.UpdatedAt, folder.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM conversation_folder_item WHERE folder_id = $1`, folder.ID)
+	if err != nil {
+		return err
+	}
+	err = insertFolderItems(ctx, tx, folder)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteFolder implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM conversation_folder_item WHERE folder_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM conversation_folder WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetFolderByID implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) GetFolderByID(ctx context.Context, id string) (*domain.ConversationFolder, error) {
+	var folder domain.ConversationFolder
+	fields, values := folder.MapFields()
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE((SELECT array_agg(conversation_id ORDER BY conversation_id) FROM conversation_folder_item WHERE folder_id = f.id), '{}')
+		FROM %s f WHERE id = $1`, strings.Join(fields, ", "), folder.TableName())
+	err := c.db.QueryRow(ctx, query, id).Scan(append(values, &folder.ConversationIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// GetListFolderByUserID implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) GetListFolderByUserID(ctx context.Context, userID string) ([]*domain.ConversationFolder, error) {
+	var temp domain.ConversationFolder
+	fields, _ := temp.MapFields()
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE((SELECT array_agg(conversation_id ORDER BY conversation_id) FROM conversation_folder_item WHERE folder_id = f.id), '{}')
+		FROM %s f WHERE user_id = $1 ORDER BY position, id`, strings.Join(fields, ", "), temp.TableName())
+	rows, err := c.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []*domain.ConversationFolder
+	for rows.Next() {
+		var folder domain.ConversationFolder
+		_, values := folder.MapFields()
+		if err := rows.Scan(append(values, &folder.ConversationIDs)...); err != nil {
+			return nil, err
+		}
+		folders = append(folders, &folder)
+	}
+	return folders, nil
+}
+
+// CountFolderByUserID implements domain.ConversationFolderRepository.
+func (c *conversationFolderRepository) CountFolderByUserID(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM conversation_folder WHERE user_id = $1`
+	err := c.db.QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+var _ domain.ConversationFolderRepository = &conversationFolderRepository{}
+
+func NewConversationFolderRepository(db *pgxpool.Pool) domain.ConversationFolderRepository {
+	return &conversationFolderRepository{db: db}
+}
@@ -0,0 +1,127 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type conversationInviteLinkRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateInviteLink implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) CreateInviteLink(ctx context.Context, inviteLink *domain.ConversationInviteLink) error {
+	query := `
+		INSERT INTO conversation_invite_link (id, conversation_id, token, created_by, expired_at, max_uses, used_count, require_approval, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := c.db.Exec(ctx, query, inviteLink.ID, inviteLink.ConversationID, inviteLink.Token, inviteLink.CreatedBy, inviteLink.ExpiredAt, inviteLink.MaxUses, inviteLink.UsedCount, inviteLink.RequireApproval, inviteLink.CreatedAt, inviteLink.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetInviteLinkByID implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) GetInviteLinkByID(ctx context.Context, id string) (*domain.ConversationInviteLink, error) {
+	var inviteLink domain.ConversationInviteLink
+	fields, values := inviteLink.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, strings.Join(fields, ", "), inviteLink.TableName())
+	err := c.db.QueryRow(ctx, query, id).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &inviteLink, nil
+}
+
+// GetInviteLinkByToken implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) GetInviteLinkByToken(ctx context.Context, token string) (*domain.ConversationInviteLink, error) {
+	var inviteLink domain.ConversationInviteLink
+	fields, values := inviteLink.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE token = $1`, strings.Join(fields, ", "), inviteLink.TableName())
+	err := c.db.QueryRow(ctx, query, token).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &inviteLink, nil
+}
+
+// GetListInviteLinkByConversationID implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) GetListInviteLinkByConversationID(ctx context.Context, conversationID string) ([]*domain.ConversationInviteLink, error) {
+	var temp domain.ConversationInviteLink
+	fields, _ := temp.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE conversation_id = $1 AND revoked_at IS NULL ORDER BY id DESC`, strings.Join(fields, ", "), temp.TableName())
+	rows, err := c.db.Query(ctx, query, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inviteLinks []*domain.ConversationInviteLink
+	for rows.Next() {
+		var inviteLink domain.ConversationInviteLink
+		_, values := inviteLink.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		inviteLinks = append(inviteLinks, &inviteLink)
+	}
+	return inviteLinks, nil
+}
+
+// RevokeInviteLink implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) RevokeInviteLink(ctx context.Context, id string) error {
+	query := `UPDATE conversation_invite_link SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	_, err := c.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// JoinByInviteLink implements domain.ConversationInviteLinkRepository.
+func (c *conversationInviteLinkRepository) JoinByInviteLink(ctx context.Context, inviteLinkID string, conversationMember *domain.ConversationMember) (bool, error) {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// a concurrent join of the same user inserts nothing and does not use up the link
+	inserted, err := insertConversationMembers(ctx, tx, conversationMember)
+	if err != nil {
+		return false, err
+	}
+	if inserted == 0 {
+		return false, nil
+	}
+
+	// the conditions are checked again here so concurrent joins can not exceed max_uses
+	query := `
+		UPDATE conversation_invite_link SET used_count = used_count + 1, updated_at = NOW()
+		WHERE id = $1
+			AND revoked_at IS NULL
+			AND (expired_at IS NULL OR expired_at > NOW())
+			AND (max_uses = 0 OR used_count < max_uses)
+	`
+	tag, err := tx.Exec(ctx, query, inviteLinkID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, domain.ErrInviteLinkInvalid
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+var _ domain.ConversationInviteLinkRepository = &conversationInviteLinkRepository{}
+
+func NewConversationInviteLinkRepository(db *pgxpool.Pool) domain.ConversationInviteLinkRepository {
+	return &conversationInviteLinkRepository{db: db}
+}
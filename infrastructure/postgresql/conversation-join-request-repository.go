@@ -0,0 +1,147 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type conversationJoinRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateJoinRequest implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) CreateJoinRequest(ctx context.Context, joinRequest *domain.ConversationJoinRequest) (*domain.ConversationJoinRequest, error) {
+	query := `
+		INSERT INTO conversation_join_request (id, conversation_id, user_id, invite_link_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (conversation_id, user_id) WHERE status = 'PENDING' DO NOTHING
+	`
+	tag, err := c.db.Exec(ctx, query, joinRequest.ID, joinRequest.ConversationID, joinRequest.UserID, joinRequest.InviteLinkID, joinRequest.Status, joinRequest.CreatedAt, joinRequest.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return c.GetPendingJoinRequest(ctx, joinRequest.ConversationID, joinRequest.UserID)
+	}
+	return joinRequest, nil
+}
+
+// GetJoinRequestByID implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) GetJoinRequestByID(ctx context.Context, id string) (*domain.ConversationJoinRequest, error) {
+	var joinRequest domain.ConversationJoinRequest
+	fields, values := joinRequest.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, strings.Join(fields, ", "), joinRequest.TableName())
+	err := c.db.QueryRow(ctx, query, id).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+// GetPendingJoinRequest implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) GetPendingJoinRequest(ctx context.Context, conversationID string, userID string) (*domain.ConversationJoinRequest, error) {
+	var joinRequest domain.ConversationJoinRequest
+	fields, values := joinRequest.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE conversation_id = $1 AND user_id = $2 AND status = $3`, strings.Join(fields, ", "), joinRequest.TableName())
+	err := c.db.QueryRow(ctx, query, conversationID, userID, domain.JoinRequestStatusPending).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+// GetListPendingJoinRequestWithUser implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) GetListPendingJoinRequestWithUser(ctx context.Context, conversationID string) ([]*domain.ConversationJoinRequestWithUser, error) {
+	var temp domain.ConversationJoinRequest
+	fields, _ := temp.MapFields()
+	for i, field := range fields {
+		fields[i] = "jr." + field
+	}
+	query := fmt.Sprintf(`
+		SELECT %s, ui.full_name, ui.avatar, ui.type FROM conversation_join_request jr
+		INNER JOIN user_info ui ON jr.user_id = ui.id
+		WHERE jr.conversation_id = $1 AND jr.status = $2
+		ORDER BY jr.created_at`, strings.Join(fields, ", "))
+	rows, err := c.db.Query(ctx, query, conversationID, domain.JoinRequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var joinRequests []*domain.ConversationJoinRequestWithUser
+	for rows.Next() {
+		var joinRequest domain.ConversationJoinRequestWithUser
+		_, values := joinRequest.MapFields()
+		if err := rows.Scan(append(values, &joinRequest.FullName, &joinRequest.Avatar, &joinRequest.UserType)...); err != nil {
+			return nil, err
+		}
+		joinRequests = append(joinRequests, &joinRequest)
+	}
+	return joinRequests, nil
+}
+
+// reviewJoinRequest stores the outcome of a pending request, it fails when somebody else reviewed it first.
+func reviewJoinRequest(ctx context.Context, db dbExecutor, joinRequest *domain.ConversationJoinRequest) error {
+	query := `
+		UPDATE conversation_join_request SET status = $1, reviewed_by = $2, reviewed_at = $3, updated_at = $3
+		WHERE id = $4 AND status = $5
+	`
+	tag, err := db.Exec(ctx, query, joinRequest.Status, joinRequest.ReviewedBy, joinRequest.ReviewedAt, joinRequest.ID, domain.JoinRequestStatusPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrJoinRequestNotPending
+	}
+	return nil
+}
+
+// ApproveJoinRequest implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) ApproveJoinRequest(ctx context.Context, joinRequest *domain.ConversationJoinRequest, conversationMember *domain.ConversationMember) error {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = reviewJoinRequest(ctx, tx, joinRequest)
+	if err != nil {
+		return err
+	}
+
+	var inserted int64
+	if conversationMember != nil {
+		inserted, err = insertConversationMembers(ctx, tx, conversationMember)
+		if err != nil {
+			return err
+		}
+	}
+
+	// a user who joined meanwhile does not use up the link
+	if joinRequest.InviteLinkID != nil && inserted > 0 {
+		// the admin approved the request, the limits of the link are not checked again
+		query := `UPDATE conversation_invite_link SET used_count = used_count + 1, updated_at = NOW() WHERE id = $1`
+		_, err = tx.Exec(ctx, query, *joinRequest.InviteLinkID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RejectJoinRequest implements domain.ConversationJoinRequestRepository.
+func (c *conversationJoinRequestRepository) RejectJoinRequest(ctx context.Context, joinRequest *domain.ConversationJoinRequest) error {
+	return reviewJoinRequest(ctx, c.db, joinRequest)
+}
+
+var _ domain.ConversationJoinRequestRepository = &conversationJoinRequestRepository{}
+
+func NewConversationJoinRequestRepository(db *pgxpool.Pool) domain.ConversationJoinRequestRepository {
+	return &conversationJoinRequestRepository{db: db}
+}
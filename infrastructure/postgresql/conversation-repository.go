@@ -9,6 +9,7 @@ import (
 
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,6 +17,31 @@ type conversationRepository struct {
 	db *pgxpool.Pool
 }
 
+// dbExecutor is implemented by the pool and by a transaction,
+// so the member writes can run alone or inside a bigger transaction.
+type dbExecutor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// insertConversationMembers adds the members to their conversations, a user who is already a member is skipped.
+// It returns the number of members actually added.
+func insertConversationMembers(ctx context.Context, db dbExecutor, conversationMembers ...*domain.ConversationMember) (int64, error) {
+	query := `
+		INSERT INTO conversation_member (id, conversation_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (conversation_id, user_id) DO NOTHING
+	`
+	var inserted int64
+	for _, conversationMember := range conversationMembers {
+		tag, err := db.Exec(ctx, query, conversationMember.ID, conversationMember.ConversationID, conversationMember.UserID, conversationMember.Role, conversationMember.CreatedAt, conversationMember.UpdatedAt)
+		if err != nil {
+			return inserted, err
+		}
+		inserted += tag.RowsAffected()
+	}
+	return inserted, nil
+}
+
 // CreateConversation implements domain.ConversationRepository.
 func (c *conversationRepository) CreateConversation(ctx context.Context, conversation *domain.Conversation, conversationMembers []*domain.ConversationMember) (*domain.Conversation, error) {
 	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
@@ -24,25 +50,23 @@ func (c *conversationRepository) CreateConversation(ctx context.Context, convers
 	}
 	defer tx.Rollback(ctx)
 
+	// a DM that already exists for the same pair of users is left untouched
 	query := `
-		INSERT INTO conversation (id, created_at, type, title, avatar, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO conversation (id, created_at, type, title, avatar, updated_at, dm_key, is_public, description, require_approval)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (dm_key) DO NOTHING
 	`
-	_, err = tx.Exec(ctx, query, conversation.ID, conversation.CreatedAt, conversation.Type, conversation.Title, conversation.Avatar, conversation.UpdatedAt)
+	tag, err := tx.Exec(ctx, query, conversation.ID, conversation.CreatedAt, conversation.Type, conversation.Title, conversation.Avatar, conversation.UpdatedAt, conversation.DMKey, conversation.IsPublic, conversation.Description, conversation.RequireApproval)
 	if err != nil {
 		return nil, err
 	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrConversationAlreadyExists
+	}
 
-	query = `
-		INSERT INTO conversation_member (id, conversation_id, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-
-	for _, conversationMember := range conversationMembers {
-		_, err = tx.Exec(ctx, query, conversationMember.ID, conversationMember.ConversationID, conversationMember.UserID, conversationMember.CreatedAt, conversationMember.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
+	_, err = insertConversationMembers(ctx, tx, conversationMembers...)
+	if err != nil {
+		return nil, err
 	}
 
 	err = tx.Commit(ctx)
@@ -54,48 +78,111 @@ func (c *conversationRepository) CreateConversation(ctx context.Context, convers
 }
 
 // GetConversationByID implements domain.ConversationRepository.
-func (c *conversationRepository) GetConversationByID(ctx context.Context, id string) (*domain.Conversation, []*domain.ConversationMemberWithUser, error) {
+func (c *conversationRepository) GetConversationByID(ctx context.Context, id string) (*domain.Conversation, error) {
 	var conversation domain.Conversation
-	var conversationMembers []*domain.ConversationMemberWithUser
 
 	fields, values := conversation.MapFields()
-	// query get conversation and conversation members
 	query := fmt.Sprintf(`SELECT %s FROM conversation WHERE id = $1`, strings.Join(fields, ", "))
 	row := c.db.QueryRow(ctx, query, id)
 
 	if err := row.Scan(values...); err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+// GetListJoinedConversation implements domain.ConversationRepository.
+func (c *conversationRepository) GetListJoinedConversation(ctx context.Context, userID string) ([]*domain.Conversation, error) {
+	var conversation domain.Conversation
+	fields, _ := conversation.MapFields()
+	for i := range fields {
+		fields[i] = "c." + fields[i]
 	}
+	query := fmt.Sprintf(`
+		SELECT %s FROM conversation c
+		INNER JOIN conversation_member cm ON cm.conversation_id = c.id
+		WHERE cm.user_id = $1
+		ORDER BY c.created_at`, strings.Join(fields, ", "))
+	rows, err := c.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversations []*domain.Conversation
+	for rows.Next() {
+		var conversation domain.Conversation
+		_, values := conversation.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, &conversation)
+	}
+	return conversations, rows.Err()
+}
 
-	query = `SELECT cm.conversation_id, cm.user_id, ui.full_name, ui.avatar, ui.type FROM conversation_member cm
+// GetListConversationMemberWithUser implements domain.ConversationRepository.
+func (c *conversationRepository) GetListConversationMemberWithUser(ctx context.Context, conversationID string, keyword string, lastUserID string, limit int) ([]*domain.ConversationMemberWithUser, error) {
+	var conversationMembers []*domain.ConversationMemberWithUser
+
+	params := []any{conversationID}
+	condition := ""
+	if keyword != "" {
+		params = append(params, keyword)
+		condition += fmt.Sprintf(" AND ui.full_name ILIKE '%%' || $%d || '%%'", len(params))
+	}
+	if lastUserID != "" {
+		params = append(params, lastUserID)
+		condition += fmt.Sprintf(" AND cm.user_id > $%d", len(params))
+	}
+	query := fmt.Sprintf(`SELECT cm.conversation_id, cm.user_id, cm.role, ui.full_name, ui.avatar, ui.type, ui.status_emoji, ui.status_text, ui.status_expires_at FROM conversation_member cm
 		INNER JOIN user_info ui ON cm.user_id = ui.id
-		WHERE cm.conversation_id = $1`
-	rows, err := c.db.Query(ctx, query, id)
+		WHERE cm.conversation_id = $1%s
+		ORDER BY cm.user_id LIMIT %d`, condition, limit)
+	rows, err := c.db.Query(ctx, query, params...)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var conversationMember domain.ConversationMemberWithUser
-		if err := rows.Scan(&conversationMember.ConversationID, &conversationMember.UserID, &conversationMember.FullName, &conversationMember.Avatar, &conversationMember.UserType); err != nil {
-			return nil, nil, err
+		if err := rows.Scan(&conversationMember.ConversationID, &conversationMember.UserID, &conversationMember.Role, &conversationMember.FullName, &conversationMember.Avatar, &conversationMember.UserType,
+			&conversationMember.StatusEmoji, &conversationMember.StatusText, &conversationMember.StatusExpiresAt); err != nil {
+			return nil, err
 		}
 		conversationMembers = append(conversationMembers, &conversationMember)
 	}
 
-	return &conversation, conversationMembers, nil
+	return conversationMembers, nil
 }
 
 // GetListConversationByUserID implements domain.ConversationRepository.
-func (c *conversationRepository) GetListConversationByUserID(ctx context.Context, userID string, lastMessageID string, limit int) ([]*domain.Conversation, error) {
+func (c *conversationRepository) GetListConversationByUserID(ctx context.Context, userID string, filter *domain.ConversationFilter, lastMessageID string, limit int) ([]*domain.Conversation, error) {
 	var conversations []*domain.Conversation
 	var conditionLastMessageID string
 	var params []any
 	params = append(params, userID)
 	if lastMessageID != "" {
-		conditionLastMessageID = `AND last_message_id < $2`
 		params = append(params, lastMessageID)
+		conditionLastMessageID = fmt.Sprintf(`AND c.last_message_id < $%d`, len(params))
+	}
+	// a conversation deleted by the user is hidden until a new message comes
+	conditionMember := `AND me.archived_at IS NULL AND (me.history_cleared_at IS NULL OR m.created_at > me.history_cleared_at)`
+	orderBy := `c.last_message_id DESC`
+	switch {
+	case filter.Archived:
+		conditionMember = `AND me.archived_at IS NOT NULL`
+	case filter.Pinned:
+		conditionMember += ` AND me.pinned_at IS NOT NULL`
+		orderBy = `me.pinned_at DESC`
+	default:
+		conditionMember += ` AND me.pinned_at IS NULL`
+	}
+	if filter.Folder != nil {
+		params = append(params, filter.Folder.ConversationTypes, filter.Folder.ID)
+		conditionMember += fmt.Sprintf(` AND (c.type = ANY($%d) OR c.id IN (SELECT conversation_id FROM conversation_folder_item WHERE folder_id = $%d))`, len(params)-1, len(params))
 	}
 	// Add NULL handling for last_message_id
 	// fieldsWithCoalesce := []string{
@@ -128,10 +215,12 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 
 	query := fmt.Sprintf(`
 		WITH conversation_data AS (
-			SELECT c.id, c.created_at, c.type, c.title, c.avatar, c.updated_at, c.deleted_at, 
+			SELECT c.id, c.created_at, c.type, c.title, c.avatar, c.updated_at, c.deleted_at, c.is_public, c.description, c.slow_mode_seconds, c.daily_message_limit,
 				COALESCE(c.last_message_id::text, '') as last_message_id,
+				c.last_message_seq,
 				COALESCE(m.id::text, '') as message_id,
 				COALESCE(m.conversation_id::text, '') as message_conversation_id,
+				COALESCE(m.seq, 0) as message_seq,
 				COALESCE(m.user_id::text, '') as message_user_id,
 				COALESCE(m.type::text, '') as message_type,
 				COALESCE(m.body::text, '') as message_body,
@@ -141,16 +230,20 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 				COALESCE(ui.id::text, '') as user_id,
 				COALESCE(ui.full_name::text, '') as user_full_name,
 				COALESCE(ui.avatar::text, '') as user_avatar,
-				COALESCE(ui.type::text, '') as user_type
+				COALESCE(ui.type::text, '') as user_type,
+				me.notification_level, me.muted_until, me.muted_forever, me.archived_at, me.history_cleared_at, me.pinned_at,
+				(
+					SELECT COUNT(*) FROM message um
+					WHERE um.conversation_id = c.id AND um.user_id != $1
+						AND um.seq > COALESCE((SELECT sm.message_seq FROM seen_message sm WHERE sm.conversation_id = c.id AND sm.user_id = $1), 0)
+						AND (me.history_cleared_at IS NULL OR um.created_at > me.history_cleared_at)
+				) as unread_count
 			FROM conversation c
+			INNER JOIN conversation_member me ON me.conversation_id = c.id AND me.user_id = $1
 			LEFT JOIN message m ON c.last_message_id = m.id
 			LEFT JOIN user_info ui ON m.user_id = ui.id
-			WHERE c.id IN (
-				SELECT DISTINCT conversation_id 
-				FROM conversation_member 
-				WHERE user_id = $1
-			) %s
-			ORDER BY c.last_message_id DESC
+			WHERE c.deleted_at IS NULL %s %s
+			ORDER BY %s
 			LIMIT %d
 		),
 		conversation_members AS (
@@ -166,7 +259,8 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 				) as members
 			FROM conversation_member cm
 			INNER JOIN user_info ui ON cm.user_id = ui.id
-			WHERE cm.conversation_id IN (SELECT id FROM conversation_data)
+			-- channels can have too many subscribers to be listed
+			WHERE cm.conversation_id IN (SELECT id FROM conversation_data WHERE type != 'CHANNEL')
 			GROUP BY cm.conversation_id
 		)
 		SELECT 
@@ -174,7 +268,7 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 			COALESCE(cm.members::text, '[]') as members
 		FROM conversation_data cd
 		LEFT JOIN conversation_members cm ON cd.id = cm.conversation_id
-		ORDER BY cd.last_message_id DESC`, conditionLastMessageID, limit)
+		ORDER BY cd.pinned_at DESC NULLS LAST, cd.last_message_id DESC`, conditionMember, conditionLastMessageID, orderBy, limit)
 
 	rows, err := c.db.Query(ctx, query, params...)
 	if err != nil && err != pgx.ErrNoRows {
@@ -190,6 +284,7 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 		var conversation domain.Conversation
 		var message domain.Message
 		var userInfo domain.UserInfo
+		var membership domain.ConversationMember
 		var members []*domain.UserInfo
 		var membersString string
 		values := []any{
@@ -200,9 +295,15 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 			&conversation.Avatar,
 			&conversation.UpdatedAt,
 			&conversation.DeletedAt,
+			&conversation.IsPublic,
+			&conversation.Description,
+			&conversation.SlowModeSeconds,
+			&conversation.DailyMessageLimit,
 			&conversation.LastMessageID,
+			&conversation.LastMessageSeq,
 			&message.ID,
 			&message.ConversationID,
+			&message.Seq,
 			&message.UserID,
 			&message.Type,
 			&message.Body,
@@ -213,6 +314,13 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 			&userInfo.FullName,
 			&userInfo.Avatar,
 			&userInfo.Type,
+			&membership.NotificationLevel,
+			&membership.MutedUntil,
+			&membership.MutedForever,
+			&membership.ArchivedAt,
+			&membership.HistoryClearedAt,
+			&membership.PinnedAt,
+			&conversation.UnreadCount,
 			&membersString,
 		}
 		if err := rows.Scan(values...); err != nil {
@@ -234,6 +342,9 @@ func (c *conversationRepository) GetListConversationByUserID(ctx context.Context
 			return nil, err
 		}
 		conversation.Members = members
+		membership.ConversationID = conversation.ID
+		membership.UserID = userID
+		conversation.Membership = &membership
 		fmt.Println(conversation.Members)
 		conversations = append(conversations, &conversation)
 	}
@@ -259,8 +370,10 @@ func (c *conversationRepository) UpdateLastMessageID(ctx context.Context, conver
 	if err != nil {
 		return err
 	}
+	// the updates are asynchronous, a message older than the current last one is ignored
 	query = `
-		UPDATE conversation SET last_message_id = $1, updated_at = $2 WHERE id = $3
+		UPDATE conversation SET last_message_id = $1, updated_at = $2
+		WHERE id = $3 AND COALESCE((SELECT seq FROM message WHERE id = conversation.last_message_id), 0) < (SELECT seq FROM message WHERE id = $1)
 	`
 
 	_, err = tx.Exec(ctx, query, lastMessageID, time.Now(), conversationID)
@@ -285,10 +398,404 @@ func NewConversationRepository(db *pgxpool.Pool) domain.ConversationRepository {
 
 func (c *conversationRepository) CheckIsMemberOfConversation(ctx context.Context, userID string, conversationID string) (bool, error) {
 	var isMember int
-	query := `SELECT 1 FROM conversation_member WHERE user_id = $1 AND conversation_id = $2`
+	query := `
+		SELECT 1 FROM conversation_member
+		WHERE user_id = $1 AND conversation_id = $2
+			AND EXISTS (SELECT 1 FROM conversation c WHERE c.id = conversation_id AND c.deleted_at IS NULL)`
 	err := c.db.QueryRow(ctx, query, userID, conversationID).Scan(&isMember)
 	if err != nil {
 		return false, err
 	}
 	return isMember == 1, nil
 }
+
+// GetConversationMember implements domain.ConversationRepository.
+func (c *conversationRepository) GetConversationMember(ctx context.Context, conversationID string, userID string) (*domain.ConversationMember, error) {
+	var conversationMember domain.ConversationMember
+	fields, values := conversationMember.MapFields()
+	// the members of a deleted conversation can not use it anymore
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE conversation_id = $1 AND user_id = $2
+			AND EXISTS (SELECT 1 FROM conversation c WHERE c.id = conversation_id AND c.deleted_at IS NULL)`, strings.Join(fields, ", "), conversationMember.TableName())
+	err := c.db.QueryRow(ctx, query, conversationID, userID).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &conversationMember, nil
+}
+
+// UpdateConversation implements domain.ConversationRepository.
+func (c *conversationRepository) UpdateConversation(ctx context.Context, conversation *domain.Conversation) error {
+	query := `
+		UPDATE conversation SET title = $1, avatar = $2, require_approval = $3, slow_mode_seconds = $4, daily_message_limit = $5, updated_at = $6
+		WHERE id = $7
+	`
+	_, err := c.db.Exec(ctx, query, conversation.Title, conversation.Avatar, conversation.RequireApproval, conversation.SlowModeSeconds, conversation.DailyMessageLimit, conversation.UpdatedAt, conversation.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountConversationMember implements domain.ConversationRepository.
+func (c *conversationRepository) CountConversationMember(ctx context.Context, conversationID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM conversation_member WHERE conversation_id = $1 AND deleted_at IS NULL`
+	err := c.db.QueryRow(ctx, query, conversationID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetConversationByDMKey implements domain.ConversationRepository.
+func (c *conversationRepository) GetConversationByDMKey(ctx context.Context, dmKey string) (*domain.Conversation, error) {
+	var conversation domain.Conversation
+	fields, values := conversation.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM conversation WHERE dm_key = $1 AND deleted_at IS NULL`, strings.Join(fields, ", "))
+	err := c.db.QueryRow(ctx, query, dmKey).Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// MergeDuplicateDMConversations implements domain.ConversationRepository.
+func (c *conversationRepository) MergeDuplicateDMConversations(ctx context.Context) (int, error) {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// oldest first, so the first conversation of every pair is the one that is kept
+	query := `
+		SELECT c.id, string_agg(cm.user_id, ':' ORDER BY cm.user_id) AS dm_key
+		FROM conversation c
+		INNER JOIN conversation_member cm ON cm.conversation_id = c.id
+		WHERE c.type = $1 AND c.deleted_at IS NULL
+		GROUP BY c.id, c.created_at
+		HAVING COUNT(*) = 2
+		ORDER BY c.created_at, c.id
+	`
+	rows, err := tx.Query(ctx, query, domain.ConversationTypeDM)
+	if err != nil {
+		return 0, err
+	}
+
+	var dmKeys []string
+	conversationIDsByDMKey := make(map[string][]string)
+	for rows.Next() {
+		var conversationID, dmKey string
+		if err := rows.Scan(&conversationID, &dmKey); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if _, ok := conversationIDsByDMKey[dmKey]; !ok {
+			dmKeys = append(dmKeys, dmKey)
+		}
+		conversationIDsByDMKey[dmKey] = append(conversationIDsByDMKey[dmKey], conversationID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var merged int
+	for _, dmKey := range dmKeys {
+		conversationIDs := conversationIDsByDMKey[dmKey]
+		keepID, duplicateIDs := conversationIDs[0], conversationIDs[1:]
+
+		if len(duplicateIDs) == 0 {
+			_, err = tx.Exec(ctx, `UPDATE conversation SET dm_key = $2 WHERE id = $1 AND dm_key IS NULL`, keepID, dmKey)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// renumber the messages of the merged conversations, through negative seqs to keep them unique
+		query = `
+			UPDATE message m SET conversation_id = $1, seq = -n.seq
+			FROM (SELECT id, row_number() OVER (ORDER BY id) AS seq FROM message WHERE conversation_id = $1 OR conversation_id = ANY($2)) n
+			WHERE m.id = n.id
+		`
+		_, err = tx.Exec(ctx, query, keepID, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(ctx, `UPDATE message SET seq = -seq WHERE conversation_id = $1`, keepID)
+		if err != nil {
+			return 0, err
+		}
+
+		// keep the furthest seen pointer of every user
+		query = `
+			WITH moved AS (
+				DELETE FROM seen_message WHERE conversation_id = ANY($2)
+				RETURNING message_id, user_id, created_at
+			)
+			INSERT INTO seen_message (id, message_id, user_id, conversation_id, created_at, updated_at)
+			SELECT DISTINCT ON (user_id) gen_random_uuid()::text, message_id, user_id, $1, created_at, NOW()
+			FROM moved
+			ORDER BY user_id, message_id DESC
+			ON CONFLICT (user_id, conversation_id) DO UPDATE
+			SET message_id = GREATEST(seen_message.message_id, EXCLUDED.message_id), updated_at = NOW()
+		`
+		_, err = tx.Exec(ctx, query, keepID, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+		query = `UPDATE seen_message sm SET message_seq = m.seq FROM message m WHERE m.id = sm.message_id AND sm.conversation_id = $1`
+		_, err = tx.Exec(ctx, query, keepID)
+		if err != nil {
+			return 0, err
+		}
+
+		// the attachments and the reactions follow their messages
+		_, err = tx.Exec(ctx, `UPDATE message_attachment SET conversation_id = $1 WHERE conversation_id = ANY($2)`, keepID, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(ctx, `UPDATE message_reaction SET conversation_id = $1 WHERE conversation_id = ANY($2)`, keepID, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM conversation_member WHERE conversation_id = ANY($1)`, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE conversation SET dm_key = NULL, deleted_at = NOW(), updated_at = NOW() WHERE id = ANY($1)`, duplicateIDs)
+		if err != nil {
+			return 0, err
+		}
+		merged += len(duplicateIDs)
+
+		query = `
+			UPDATE conversation SET dm_key = $2,
+				last_message_id = COALESCE((SELECT MAX(id) FROM message WHERE conversation_id = $1), ''),
+				last_message_seq = COALESCE((SELECT MAX(seq) FROM message WHERE conversation_id = $1), 0),
+				updated_at = NOW()
+			WHERE id = $1
+		`
+		_, err = tx.Exec(ctx, query, keepID, dmKey)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return merged, nil
+}
+
+// UpdateNotificationSetting implements domain.ConversationRepository.
+func (c *conversationRepository) UpdateNotificationSetting(ctx context.Context, conversationMember *domain.ConversationMember) error {
+	query := `UPDATE conversation_member SET notification_level = $1, muted_until = $2, muted_forever = $3, updated_at = $4 WHERE conversation_id = $5 AND user_id = $6`
+	_, err := c.db.Exec(ctx, query, conversationMember.NotificationLevel, conversationMember.MutedUntil, conversationMember.MutedForever, conversationMember.UpdatedAt, conversationMember.ConversationID, conversationMember.UserID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetListConversationMemberByUserIDs implements domain.ConversationRepository.
+func (c *conversationRepository) GetListConversationMemberByUserIDs(ctx context.Context, conversationID string, userIDs []string) ([]*domain.ConversationMember, error) {
+	var conversationMember domain.ConversationMember
+	fields, _ := conversationMember.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE conversation_id = $1 AND user_id = ANY($2)`, strings.Join(fields, ", "), conversationMember.TableName())
+	rows, err := c.db.Query(ctx, query, conversationID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversationMembers []*domain.ConversationMember
+	for rows.Next() {
+		var member domain.ConversationMember
+		_, values := member.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		conversationMembers = append(conversationMembers, &member)
+	}
+	return conversationMembers, rows.Err()
+}
+
+// CountUnreadMessageByUserID implements domain.ConversationRepository.
+func (c *conversationRepository) CountUnreadMessageByUserID(ctx context.Context, userID string, now time.Time) (int, int, error) {
+	var unreadMessageCount, unreadConversationCount int
+	query := `
+		SELECT COUNT(*), COUNT(DISTINCT m.conversation_id)
+		FROM message m
+		INNER JOIN conversation_member cm ON cm.conversation_id = m.conversation_id AND cm.user_id = $1
+		INNER JOIN conversation c ON c.id = m.conversation_id AND c.deleted_at IS NULL
+		LEFT JOIN seen_message sm ON sm.conversation_id = m.conversation_id AND sm.user_id = $1
+		WHERE m.user_id != $1
+			AND m.seq > COALESCE(sm.message_seq, 0)
+			AND (cm.history_cleared_at IS NULL OR m.created_at > cm.history_cleared_at)
+			AND NOT cm.muted_forever
+			AND (cm.muted_until IS NULL OR cm.muted_until <= $2)`
+	err := c.db.QueryRow(ctx, query, userID, now).Scan(&unreadMessageCount, &unreadConversationCount)
+	if err != nil {
+		return 0, 0, err
+	}
+	return unreadMessageCount, unreadConversationCount, nil
+}
+
+// UpdateArchivedAt implements domain.ConversationRepository.
+func (c *conversationRepository) UpdateArchivedAt(ctx context.Context, conversationID string, userID string, archivedAt *time.Time) error {
+	query := `UPDATE conversation_member SET archived_at = $1, updated_at = $2 WHERE conversation_id = $3 AND user_id = $4`
+	_, err := c.db.Exec(ctx, query, archivedAt, time.Now(), conversationID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnarchiveConversation implements domain.ConversationRepository.
+func (c *conversationRepository) UnarchiveConversation(ctx context.Context, conversationID string, now time.Time) error {
+	query := `
+		UPDATE conversation_member SET archived_at = NULL, updated_at = $2
+		WHERE conversation_id = $1 AND archived_at IS NOT NULL
+			AND NOT muted_forever AND (muted_until IS NULL OR muted_until <= $2)`
+	_, err := c.db.Exec(ctx, query, conversationID, now)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClearHistory implements domain.ConversationRepository.
+func (c *conversationRepository) ClearHistory(ctx context.Context, conversationID string, userID string, clearedAt time.Time) error {
+	query := `UPDATE conversation_member SET history_cleared_at = $1, archived_at = NULL, updated_at = $1 WHERE conversation_id = $2 AND user_id = $3`
+	_, err := c.db.Exec(ctx, query, clearedAt, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdatePinnedAt implements domain.ConversationRepository.
+func (c *conversationRepository) UpdatePinnedAt(ctx context.Context, conversationID string, userID string, pinnedAt *time.Time) error {
+	query := `UPDATE conversation_member SET pinned_at = $1, updated_at = $2 WHERE conversation_id = $3 AND user_id = $4`
+	_, err := c.db.Exec(ctx, query, pinnedAt, time.Now(), conversationID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountPinnedConversation implements domain.ConversationRepository.
+func (c *conversationRepository) CountPinnedConversation(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM conversation_member cm
+		INNER JOIN conversation c ON c.id = cm.conversation_id AND c.deleted_at IS NULL
+		WHERE cm.user_id = $1 AND cm.pinned_at IS NOT NULL`
+	err := c.db.QueryRow(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SearchPublicChannel implements domain.ConversationRepository.
+func (c *conversationRepository) SearchPublicChannel(ctx context.Context, keyword string, lastConversationID string, limit int) ([]*domain.Conversation, error) {
+	var temp domain.Conversation
+	fields, _ := temp.MapFields()
+	for i, field := range fields {
+		fields[i] = "c." + field
+	}
+	params := []any{domain.ConversationTypeChannel, keyword}
+	condition := ""
+	if lastConversationID != "" {
+		params = append(params, lastConversationID)
+		condition = fmt.Sprintf("AND c.id < $%d", len(params))
+	}
+	query := fmt.Sprintf(`
+		SELECT %s, (SELECT COUNT(*) FROM conversation_member cm WHERE cm.conversation_id = c.id) AS member_count
+		FROM conversation c
+		WHERE c.type = $1 AND c.is_public AND c.deleted_at IS NULL AND c.title ILIKE '%%' || $2 || '%%' %s
+		ORDER BY c.id DESC LIMIT %d`, strings.Join(fields, ", "), condition, limit)
+	rows, err := c.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversations []*domain.Conversation
+	for rows.Next() {
+		var conversation domain.Conversation
+		_, values := conversation.MapFields()
+		if err := rows.Scan(append(values, &conversation.MemberCount)...); err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, &conversation)
+	}
+	return conversations, nil
+}
+
+// AddConversationMember implements domain.ConversationRepository.
+func (c *conversationRepository) AddConversationMember(ctx context.Context, conversationMember *domain.ConversationMember) error {
+	_, err := insertConversationMembers(ctx, c.db, conversationMember)
+	return err
+}
+
+// DeleteConversationMember implements domain.ConversationRepository.
+func (c *conversationRepository) DeleteConversationMember(ctx context.Context, conversationID string, userID string) error {
+	query := `DELETE FROM conversation_member WHERE conversation_id = $1 AND user_id = $2`
+	_, err := c.db.Exec(ctx, query, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetListConversationAdminIDs implements domain.ConversationRepository.
+func (c *conversationRepository) GetListConversationAdminIDs(ctx context.Context, conversationID string) ([]string, error) {
+	query := `SELECT user_id FROM conversation_member WHERE conversation_id = $1 AND role = ANY($2)`
+	rows, err := c.db.Query(ctx, query, conversationID, []string{domain.ConversationMemberRoleOwner, domain.ConversationMemberRoleAdmin})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// GetListChannelIDByUserID implements domain.ConversationRepository.
+func (c *conversationRepository) GetListChannelIDByUserID(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT c.id FROM conversation c
+		INNER JOIN conversation_member cm ON cm.conversation_id = c.id AND cm.user_id = $1
+		WHERE c.type = $2 AND c.deleted_at IS NULL`
+	rows, err := c.db.Query(ctx, query, userID, domain.ConversationTypeChannel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversationIDs []string
+	for rows.Next() {
+		var conversationID string
+		if err := rows.Scan(&conversationID); err != nil {
+			return nil, err
+		}
+		conversationIDs = append(conversationIDs, conversationID)
+	}
+	return conversationIDs, nil
+}
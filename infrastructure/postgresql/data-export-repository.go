@@ -0,0 +1,64 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type dataExportRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateDataExport implements domain.DataExportRepository.
+func (d *dataExportRepository) CreateDataExport(ctx context.Context, dataExport *domain.DataExport) error {
+	fields, values := dataExport.MapFields()
+	placeholders := make([]string, len(fields))
+	for i := range fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, dataExport.TableName(), strings.Join(fields, ","), strings.Join(placeholders, ","))
+	_, err := d.db.Exec(ctx, query, values...)
+	return err
+}
+
+// UpdateDataExport implements domain.DataExportRepository.
+func (d *dataExportRepository) UpdateDataExport(ctx context.Context, dataExport *domain.DataExport) error {
+	query := `UPDATE data_export SET status = $1, bucket_name = $2, object_name = $3, error = $4, completed_at = $5 WHERE id = $6`
+	_, err := d.db.Exec(ctx, query, dataExport.Status, dataExport.BucketName, dataExport.ObjectName, dataExport.Error, dataExport.CompletedAt, dataExport.ID)
+	return err
+}
+
+// GetListDataExportByAccountID implements domain.DataExportRepository.
+func (d *dataExportRepository) GetListDataExportByAccountID(ctx context.Context, accountID string) ([]*domain.DataExport, error) {
+	var dataExport domain.DataExport
+	fields, _ := dataExport.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE account_id = $1 ORDER BY created_at DESC`, strings.Join(fields, ","), dataExport.TableName())
+	rows, err := d.db.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dataExports []*domain.DataExport
+	for rows.Next() {
+		var dataExport domain.DataExport
+		_, values := dataExport.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		dataExports = append(dataExports, &dataExport)
+	}
+	return dataExports, rows.Err()
+}
+
+func NewDataExportRepository(db *pgxpool.Pool) domain.DataExportRepository {
+	return &dataExportRepository{
+		db: db,
+	}
+}
+
+var _ domain.DataExportRepository = &dataExportRepository{}
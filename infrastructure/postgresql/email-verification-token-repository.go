@@ -0,0 +1,86 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type emailVerificationTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateEmailVerificationToken implements domain.EmailVerificationTokenRepository.
+func (e *emailVerificationTokenRepository) CreateEmailVerificationToken(ctx context.Context, token *domain.EmailVerificationToken) error {
+	fields, values := token.MapFields()
+	placeholders := make([]string, len(fields))
+	for i := range fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, token.TableName(), strings.Join(fields, ","), strings.Join(placeholders, ","))
+	_, err := e.db.Exec(ctx, query, values...)
+	return err
+}
+
+// CountEmailVerificationTokenSince implements domain.EmailVerificationTokenRepository.
+func (e *emailVerificationTokenRepository) CountEmailVerificationTokenSince(ctx context.Context, accountID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM email_verification_token WHERE account_id = $1 AND created_at > $2`
+	var count int
+	err := e.db.QueryRow(ctx, query, accountID, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UseEmailVerificationToken implements domain.EmailVerificationTokenRepository.
+func (e *emailVerificationTokenRepository) UseEmailVerificationToken(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	tx, err := e.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE email_verification_token SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expired_at > $2
+		RETURNING account_id
+	`
+	var accountID string
+	err = tx.QueryRow(ctx, query, tokenHash, now).Scan(&accountID)
+	if err != nil {
+		return "", err
+	}
+
+	// the other links sent to the account stop working
+	query = `UPDATE email_verification_token SET used_at = $2 WHERE account_id = $1 AND used_at IS NULL`
+	_, err = tx.Exec(ctx, query, accountID, now)
+	if err != nil {
+		return "", err
+	}
+
+	query = `UPDATE account SET email_verified_at = $2, updated_at = $2 WHERE id = $1 AND email_verified_at IS NULL`
+	_, err = tx.Exec(ctx, query, accountID, now)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+	return accountID, nil
+}
+
+func NewEmailVerificationTokenRepository(db *pgxpool.Pool) domain.EmailVerificationTokenRepository {
+	return &emailVerificationTokenRepository{
+		db: db,
+	}
+}
+
+var _ domain.EmailVerificationTokenRepository = &emailVerificationTokenRepository{}
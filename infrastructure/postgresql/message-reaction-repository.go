@@ -0,0 +1,67 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type messageReactionRepository struct {
+	db *pgxpool.Pool
+}
+
+// AddReaction implements domain.MessageReactionRepository.
+func (m *messageReactionRepository) AddReaction(ctx context.Context, reaction *domain.MessageReaction) error {
+	query := `
+		INSERT INTO message_reaction (id, message_id, conversation_id, user_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+	`
+	_, err := m.db.Exec(ctx, query, reaction.ID, reaction.MessageID, reaction.ConversationID, reaction.UserID, reaction.Emoji, reaction.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveReaction implements domain.MessageReactionRepository.
+func (m *messageReactionRepository) RemoveReaction(ctx context.Context, messageID string, userID string, emoji string) error {
+	query := `DELETE FROM message_reaction WHERE message_id = $1 AND user_id = $2 AND emoji = $3`
+	_, err := m.db.Exec(ctx, query, messageID, userID, emoji)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetListReactionSummaryByMessageIDs implements domain.MessageReactionRepository.
+func (m *messageReactionRepository) GetListReactionSummaryByMessageIDs(ctx context.Context, messageIDs []string, userID string) ([]*domain.MessageReactionSummary, error) {
+	query := `
+		SELECT message_id, emoji, COUNT(*), BOOL_OR(user_id = $2)
+		FROM message_reaction
+		WHERE message_id = ANY($1)
+		GROUP BY message_id, emoji
+		ORDER BY message_id, MIN(created_at)`
+	rows, err := m.db.Query(ctx, query, messageIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*domain.MessageReactionSummary
+	for rows.Next() {
+		var summary domain.MessageReactionSummary
+		if err := rows.Scan(&summary.MessageID, &summary.Emoji, &summary.Count, &summary.ReactedByMe); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &summary)
+	}
+	return summaries, nil
+}
+
+var _ domain.MessageReactionRepository = &messageReactionRepository{}
+
+func NewMessageReactionRepository(db *pgxpool.Pool) domain.MessageReactionRepository {
+	return &messageReactionRepository{db: db}
+}
@@ -16,8 +16,37 @@ type messageRepository struct {
 
 // CreateMessage implements domain.MessageRepository.
 func (m *messageRepository) CreateMessage(ctx context.Context, message *domain.Message) (*domain.Message, error) {
-	query := `INSERT INTO message (id, conversation_id, user_id, type, body, created_at, updated_at, deleted_at, reply_to) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	_, err := m.db.Exec(ctx, query, message.ID, message.ConversationID, message.UserID, message.Type, message.Body, message.CreatedAt, message.UpdatedAt, message.DeletedAt, message.ReplyTo)
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the row lock serializes the senders of the conversation, and a rollback releases the seq so there is no gap
+	query := `UPDATE conversation SET last_message_seq = last_message_seq + 1 WHERE id = $1 RETURNING last_message_seq`
+	err = tx.QueryRow(ctx, query, message.ConversationID).Scan(&message.Seq)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `INSERT INTO message (id, conversation_id, seq, user_id, type, body, created_at, updated_at, deleted_at, reply_to) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err = tx.Exec(ctx, query, message.ID, message.ConversationID, message.Seq, message.UserID, message.Type, message.Body, message.CreatedAt, message.UpdatedAt, message.DeletedAt, message.ReplyTo)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO message_attachment (id, message_id, conversation_id, user_id, kind, message_type, url, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	for _, attachment := range message.Attachments {
+		_, err = tx.Exec(ctx, query, attachment.ID, attachment.MessageID, attachment.ConversationID, attachment.UserID, attachment.Kind, attachment.MessageType, attachment.URL, attachment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -25,10 +54,11 @@ func (m *messageRepository) CreateMessage(ctx context.Context, message *domain.M
 }
 
 // GetListMessageByConversationID implements domain.MessageRepository.
-func (m *messageRepository) GetListMessageByConversationID(ctx context.Context, conversationID string, lastID string, limit int) ([]*domain.Message, error) {
+func (m *messageRepository) GetListMessageByConversationID(ctx context.Context, filter *domain.MessageFilter) ([]*domain.Message, error) {
 	fields := []string{
 		"m.id",
 		"m.conversation_id",
+		"m.seq",
 		"m.user_id",
 		"m.type",
 		"m.body",
@@ -41,16 +71,24 @@ func (m *messageRepository) GetListMessageByConversationID(ctx context.Context,
 		"u.avatar",
 		"u.type",
 	}
-	condition := "conversation_id = $1"
-	if lastID != "" {
-		condition = fmt.Sprintf("%s AND m.id < $2", condition)
-	}
-	query := fmt.Sprintf(`SELECT %s FROM message AS m JOIN user_info AS u ON m.user_id = u.id WHERE %s ORDER BY m.id DESC LIMIT %d`, strings.Join(fields, ","), condition, limit)
+	condition := "m.conversation_id = $1"
 	var params []any
-	params = append(params, conversationID)
-	if lastID != "" {
-		params = append(params, lastID)
+	params = append(params, filter.ConversationID)
+	orderBy := "m.seq DESC"
+	if filter.BeforeSeq > 0 {
+		params = append(params, filter.BeforeSeq)
+		condition = fmt.Sprintf("%s AND m.seq < $%d", condition, len(params))
+	}
+	if filter.AfterSeq > 0 {
+		params = append(params, filter.AfterSeq)
+		condition = fmt.Sprintf("%s AND m.seq > $%d", condition, len(params))
+		orderBy = "m.seq ASC"
+	}
+	if filter.Since != nil {
+		params = append(params, *filter.Since)
+		condition = fmt.Sprintf("%s AND m.created_at > $%d", condition, len(params))
 	}
+	query := fmt.Sprintf(`SELECT %s FROM message AS m JOIN user_info AS u ON m.user_id = u.id WHERE %s ORDER BY %s LIMIT %d`, strings.Join(fields, ","), condition, orderBy, filter.Limit)
 	rows, err := m.db.Query(ctx, query, params...)
 	if err != nil && err != pgx.ErrNoRows {
 		return nil, err
@@ -67,6 +105,7 @@ func (m *messageRepository) GetListMessageByConversationID(ctx context.Context,
 		values := []any{
 			&message.ID,
 			&message.ConversationID,
+			&message.Seq,
 			&message.UserID,
 			&message.Type,
 			&message.Body,
@@ -89,11 +128,35 @@ func (m *messageRepository) GetListMessageByConversationID(ctx context.Context,
 	return messages, nil
 }
 
+// GetListMessageByUserID implements domain.MessageRepository.
+func (m *messageRepository) GetListMessageByUserID(ctx context.Context, userID string, lastID string, limit int) ([]*domain.Message, error) {
+	var message domain.Message
+	fields, _ := message.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM message WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3`, strings.Join(fields, ","))
+	rows, err := m.db.Query(ctx, query, userID, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domain.Message
+	for rows.Next() {
+		var message domain.Message
+		_, values := message.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+	return messages, rows.Err()
+}
+
 // GetMessageByID implements domain.MessageRepository.
 func (m *messageRepository) GetMessageByID(ctx context.Context, id string) (*domain.Message, error) {
 	fields := []string{
 		"m.id",
 		"m.conversation_id",
+		"m.seq",
 		"m.user_id",
 		"m.type",
 		"m.body",
@@ -104,6 +167,7 @@ func (m *messageRepository) GetMessageByID(ctx context.Context, id string) (*dom
 		"u.id",
 		"u.full_name",
 		"u.avatar",
+		"u.avatar_privacy",
 		"u.type",
 	}
 	query := fmt.Sprintf(`SELECT %s FROM message AS m JOIN user_info AS u ON m.user_id = u.id WHERE m.id = $1`, strings.Join(fields, ","))
@@ -113,6 +177,7 @@ func (m *messageRepository) GetMessageByID(ctx context.Context, id string) (*dom
 	values := []any{
 		&message.ID,
 		&message.ConversationID,
+		&message.Seq,
 		&message.UserID,
 		&message.Type,
 		&message.Body,
@@ -123,6 +188,7 @@ func (m *messageRepository) GetMessageByID(ctx context.Context, id string) (*dom
 		&user.ID,
 		&user.FullName,
 		&user.Avatar,
+		&user.AvatarPrivacy,
 		&user.Type,
 	}
 	err := row.Scan(values...)
@@ -133,6 +199,49 @@ func (m *messageRepository) GetMessageByID(ctx context.Context, id string) (*dom
 	return &message, nil
 }
 
+// GetListMessageAttachment implements domain.MessageRepository.
+func (m *messageRepository) GetListMessageAttachment(ctx context.Context, filter *domain.MessageAttachmentFilter) ([]*domain.MessageAttachment, error) {
+	var temp domain.MessageAttachment
+	fields, _ := temp.MapFields()
+	for i, field := range fields {
+		fields[i] = "a." + field
+	}
+	params := []any{filter.ConversationID, filter.Kind}
+	condition := "a.conversation_id = $1 AND a.kind = $2"
+	if filter.UserID != "" {
+		params = append(params, filter.UserID)
+		condition = fmt.Sprintf("%s AND a.user_id = $%d", condition, len(params))
+	}
+	if filter.LastID != "" {
+		params = append(params, filter.LastID)
+		condition = fmt.Sprintf("%s AND a.id < $%d", condition, len(params))
+	}
+	if filter.Since != nil {
+		params = append(params, *filter.Since)
+		condition = fmt.Sprintf("%s AND a.created_at > $%d", condition, len(params))
+	}
+	query := fmt.Sprintf(`
+		SELECT %s FROM message_attachment AS a
+		INNER JOIN message AS m ON m.id = a.message_id AND m.deleted_at IS NULL
+		WHERE %s ORDER BY a.id DESC LIMIT %d`, strings.Join(fields, ", "), condition, filter.Limit)
+	rows, err := m.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*domain.MessageAttachment
+	for rows.Next() {
+		var attachment domain.MessageAttachment
+		_, values := attachment.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, &attachment)
+	}
+	return attachments, nil
+}
+
 var _ domain.MessageRepository = &messageRepository{}
 
 func NewMessageRepository(db *pgxpool.Pool) domain.MessageRepository {
@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type passwordResetTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreatePasswordResetToken implements domain.PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	fields, values := token.MapFields()
+	placeholders := make([]string, len(fields))
+	for i := range fields {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, token.TableName(), strings.Join(fields, ","), strings.Join(placeholders, ","))
+	_, err := p.db.Exec(ctx, query, values...)
+	return err
+}
+
+// CountPasswordResetTokenSince implements domain.PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) CountPasswordResetTokenSince(ctx context.Context, accountID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM password_reset_token WHERE account_id = $1 AND created_at > $2`
+	var count int
+	err := p.db.QueryRow(ctx, query, accountID, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ResetPassword implements domain.PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) ResetPassword(ctx context.Context, tokenHash string, password string, now time.Time) (string, error) {
+	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// the other tokens sent to the account are used up as well
+	query := `
+		WITH used AS (
+			UPDATE password_reset_token SET used_at = $2
+			WHERE token_hash = $1 AND used_at IS NULL AND expired_at > $2
+			RETURNING account_id
+		), others AS (
+			UPDATE password_reset_token SET used_at = $2
+			WHERE account_id IN (SELECT account_id FROM used) AND token_hash != $1 AND used_at IS NULL
+		)
+		SELECT account_id FROM used
+	`
+	var accountID string
+	err = tx.QueryRow(ctx, query, tokenHash, now).Scan(&accountID)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.Exec(ctx, `UPDATE account SET password = $1, updated_at = $2 WHERE id = $3`, password, now, accountID)
+	if err != nil {
+		return "", err
+	}
+
+	return accountID, tx.Commit(ctx)
+}
+
+func NewPasswordResetTokenRepository(db *pgxpool.Pool) domain.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+var _ domain.PasswordResetTokenRepository = &passwordResetTokenRepository{}
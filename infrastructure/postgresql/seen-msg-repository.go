@@ -5,6 +5,7 @@ import (
 
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,14 +24,20 @@ func (r *seenMessageRepository) CreateSeenMessage(ctx context.Context, seenMessa
 	ctx, span := r.obs.StartSpan(ctx, "seenMessageRepository.CreateSeenMessage")
 	defer span()
 	logger := r.obs.Logger.WithContext(ctx)
+	// the pointer only moves forward, a late or replayed event does not move it back
 	query := `
-		INSERT INTO seen_message (id, message_id, user_id, conversation_id)
-		VALUES ($1, $2, $3, $4)
-		ON CONFLICT (user_id, conversation_id) DO UPDATE SET message_id = EXCLUDED.message_id, updated_at = current_timestamp
+		INSERT INTO seen_message (id, message_id, message_seq, user_id, conversation_id)
+		SELECT $1, m.id, m.seq, $3, m.conversation_id FROM message m WHERE m.id = $2 AND m.conversation_id = $4
+		ON CONFLICT (user_id, conversation_id) DO UPDATE
+		SET message_id = EXCLUDED.message_id, message_seq = EXCLUDED.message_seq, updated_at = current_timestamp
+		WHERE seen_message.message_seq < EXCLUDED.message_seq
+		RETURNING message_seq
 	`
-	_, err := r.db.Exec(ctx, query, seenMessage.ID, seenMessage.MessageID, seenMessage.UserID, seenMessage.ConversationID)
+	err := r.db.QueryRow(ctx, query, seenMessage.ID, seenMessage.MessageID, seenMessage.UserID, seenMessage.ConversationID).Scan(&seenMessage.MessageSeq)
 	if err != nil {
-		logger.Error("failed to upsert seen message", err, seenMessage)
+		if err != pgx.ErrNoRows {
+			logger.Error("failed to upsert seen message", err, seenMessage)
+		}
 		return err
 	}
 	return nil
@@ -41,7 +48,7 @@ func (r *seenMessageRepository) GetListSeenMessageByConversationID(ctx context.C
 	defer span()
 	logger := r.obs.Logger.WithContext(ctx)
 	query := `
-		SELECT id, message_id, user_id, conversation_id, created_at, updated_at
+		SELECT id, message_id, message_seq, user_id, conversation_id, created_at, updated_at
 		FROM seen_message
 		WHERE conversation_id = $1
 	`
@@ -55,7 +62,7 @@ func (r *seenMessageRepository) GetListSeenMessageByConversationID(ctx context.C
 	var seenMessages []*domain.SeenMessage
 	for rows.Next() {
 		var seenMessage domain.SeenMessage
-		err := rows.Scan(&seenMessage.ID, &seenMessage.MessageID, &seenMessage.UserID, &seenMessage.ConversationID, &seenMessage.CreatedAt, &seenMessage.UpdatedAt)
+		err := rows.Scan(&seenMessage.ID, &seenMessage.MessageID, &seenMessage.MessageSeq, &seenMessage.UserID, &seenMessage.ConversationID, &seenMessage.CreatedAt, &seenMessage.UpdatedAt)
 		if err != nil {
 			logger.Error("failed to scan seen message", err, seenMessage)
 			continue
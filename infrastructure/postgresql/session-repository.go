@@ -96,6 +96,79 @@ func (s *sessionRepository) UpdateExpiredAt(ctx context.Context, token string, n
 	return nil
 }
 
+// CreateRefreshToken implements domain.SessionRepository.
+func (s *sessionRepository) CreateRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) error {
+	fields, values := refreshToken.MapFields()
+	_, err := s.db.Exec(ctx, insertQuery(refreshToken.TableName(), fields), values...)
+	return err
+}
+
+// RotateRefreshToken implements domain.SessionRepository.
+func (s *sessionRepository) RotateRefreshToken(ctx context.Context, tokenHash string, newRefreshToken *domain.RefreshToken, now time.Time) (*domain.Session, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// the row is locked so that a token refreshed twice at once is seen as reused
+	var sessionToken string
+	var usedAt *time.Time
+	query := `SELECT session_token, used_at FROM refresh_token WHERE token_hash = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, query, tokenHash).Scan(&sessionToken, &usedAt)
+	if err != nil {
+		return nil, err
+	}
+	var session domain.Session
+	if usedAt != nil {
+		query = `
+			UPDATE session SET is_active = false, updated_at = $2 WHERE session_token = $1
+			RETURNING session_token, account_id, created_at, updated_at, expired_at, is_active, user_agent, ip_address`
+		err = tx.QueryRow(ctx, query, sessionToken, now).Scan(&session.SessionToken, &session.AccountID, &session.CreatedAt, &session.UpdatedAt, &session.ExpiredAt, &session.IsActive, &session.UserAgent, &session.IPAddress)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return &session, domain.ErrRefreshTokenReused
+	}
+
+	// a revoked or expired session keeps its refresh token unused and gets no new one
+	query = `SELECT is_active, expired_at FROM session WHERE session_token = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, query, sessionToken).Scan(&session.IsActive, &session.ExpiredAt)
+	if err != nil {
+		return nil, err
+	}
+	if (session.IsActive != nil && !*session.IsActive) || (session.ExpiredAt != nil && session.ExpiredAt.Before(now)) {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE refresh_token SET used_at = $2 WHERE token_hash = $1`, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+	newRefreshToken.SessionToken = sessionToken
+	fields, values := newRefreshToken.MapFields()
+	_, err = tx.Exec(ctx, insertQuery(newRefreshToken.TableName(), fields), values...)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE session SET updated_at = $2 WHERE session_token = $1
+		RETURNING session_token, account_id, created_at, updated_at, expired_at, is_active, user_agent, ip_address`
+	err = tx.QueryRow(ctx, query, sessionToken, now).Scan(&session.SessionToken, &session.AccountID, &session.CreatedAt, &session.UpdatedAt, &session.ExpiredAt, &session.IsActive, &session.UserAgent, &session.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
 func NewSessionRepository(db *pgxpool.Pool) *sessionRepository {
 	return &sessionRepository{
 		db: db,
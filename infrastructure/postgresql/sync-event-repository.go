@@ -0,0 +1,119 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type syncEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateSyncEvent implements domain.SyncEventRepository.
+func (s *syncEventRepository) CreateSyncEvent(ctx context.Context, syncEvent *domain.SyncEvent) error {
+	query := `
+		INSERT INTO sync_event (conversation_id, user_ids, type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, xact_id`
+	return s.db.QueryRow(ctx, query, syncEvent.ConversationID, syncEvent.UserIDs, syncEvent.Type, syncEvent.Payload, syncEvent.CreatedAt).Scan(&syncEvent.ID, &syncEvent.XactID)
+}
+
+// GetListSyncEventByUserID implements domain.SyncEventRepository.
+func (s *syncEventRepository) GetListSyncEventByUserID(ctx context.Context, userID string, after domain.SyncCursor, limit int) ([]*domain.SyncEvent, error) {
+	// the events of the user go through the index on user_ids, the events of the conversations through the
+	// memberships of the user, every transaction older than the snapshot xmin is over so its events are visible
+	query := `
+		WITH snapshot AS (
+			SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS xmin
+		)
+		SELECT e.id, e.xact_id, e.conversation_id, e.user_ids, e.type, e.payload, e.created_at
+		FROM (
+			SELECT e.*
+			FROM sync_event e
+			WHERE e.user_ids @> ARRAY[$1]::text[] AND (e.xact_id, e.id) > ($2, $3)
+			UNION ALL
+			SELECT e.*
+			FROM conversation_member cm
+			INNER JOIN sync_event e ON e.conversation_id = cm.conversation_id
+			WHERE cm.user_id = $1 AND e.created_at >= cm.created_at AND (e.xact_id, e.id) > ($2, $3)
+		) e, snapshot
+		WHERE e.xact_id < snapshot.xmin
+		ORDER BY e.xact_id, e.id
+		LIMIT $4`
+	rows, err := s.db.Query(ctx, query, userID, after.XactID, after.EventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var syncEvents []*domain.SyncEvent
+	for rows.Next() {
+		var syncEvent domain.SyncEvent
+		_, fields := syncEvent.MapFields()
+		err := rows.Scan(fields...)
+		if err != nil {
+			return nil, err
+		}
+		syncEvents = append(syncEvents, &syncEvent)
+	}
+	return syncEvents, rows.Err()
+}
+
+// GetSyncEventBounds implements domain.SyncEventRepository.
+func (s *syncEventRepository) GetSyncEventBounds(ctx context.Context) (domain.SyncCursor, domain.SyncCursor, error) {
+	var committed, purged domain.SyncCursor
+	query := `
+		SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint,
+			COALESCE((SELECT xact_id FROM sync_event_purge), 0),
+			COALESCE((SELECT event_id FROM sync_event_purge), 0)`
+	err := s.db.QueryRow(ctx, query).Scan(&committed.XactID, &purged.XactID, &purged.EventID)
+	if err != nil {
+		return domain.SyncCursor{}, domain.SyncCursor{}, err
+	}
+	return committed, purged, nil
+}
+
+// DeleteSyncEventBefore implements domain.SyncEventRepository.
+func (s *syncEventRepository) DeleteSyncEventBefore(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var deleted int64
+	var latest domain.SyncCursor
+	query := `
+		WITH deleted AS (
+			DELETE FROM sync_event WHERE created_at < $1 RETURNING xact_id, id
+		), latest AS (
+			SELECT xact_id, id FROM deleted ORDER BY xact_id DESC, id DESC LIMIT 1
+		)
+		SELECT (SELECT COUNT(*) FROM deleted), COALESCE((SELECT xact_id FROM latest), 0), COALESCE((SELECT id FROM latest), 0)`
+	err = tx.QueryRow(ctx, query, before).Scan(&deleted, &latest.XactID, &latest.EventID)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		query = `
+			INSERT INTO sync_event_purge (id, xact_id, event_id, updated_at) VALUES (true, $1, $2, NOW())
+			ON CONFLICT (id) DO UPDATE SET xact_id = EXCLUDED.xact_id, event_id = EXCLUDED.event_id, updated_at = NOW()
+			WHERE (sync_event_purge.xact_id, sync_event_purge.event_id) < (EXCLUDED.xact_id, EXCLUDED.event_id)`
+		_, err = tx.Exec(ctx, query, latest.XactID, latest.EventID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return deleted, tx.Commit(ctx)
+}
+
+var _ domain.SyncEventRepository = &syncEventRepository{}
+
+func NewSyncEventRepository(db *pgxpool.Pool) *syncEventRepository {
+	return &syncEventRepository{db: db}
+}
@@ -0,0 +1,146 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type userBlockRepository struct {
+	db *pgxpool.Pool
+}
+
+// CreateUserBlock implements domain.UserBlockRepository.
+func (u *userBlockRepository) CreateUserBlock(ctx context.Context, userBlock *domain.UserBlock) error {
+	tx, err := u.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO user_block (id, user_id, blocked_user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, blocked_user_id) DO NOTHING
+	`
+	_, err = tx.Exec(ctx, query, userBlock.ID, userBlock.UserID, userBlock.BlockedUserID, userBlock.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM contact WHERE (user_id = $1 AND friend_id = $2) OR (user_id = $2 AND friend_id = $1)`
+	_, err = tx.Exec(ctx, query, userBlock.UserID, userBlock.BlockedUserID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteUserBlock implements domain.UserBlockRepository.
+func (u *userBlockRepository) DeleteUserBlock(ctx context.Context, userID string, blockedUserID string) (bool, error) {
+	tag, err := u.db.Exec(ctx, `DELETE FROM user_block WHERE user_id = $1 AND blocked_user_id = $2`, userID, blockedUserID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetListUserBlockWithUser implements domain.UserBlockRepository.
+func (u *userBlockRepository) GetListUserBlockWithUser(ctx context.Context, userID string) ([]*domain.UserBlockWithUser, error) {
+	var temp domain.UserBlock
+	fields, _ := temp.MapFields()
+	for i, field := range fields {
+		fields[i] = "b." + field
+	}
+	query := fmt.Sprintf(`
+		SELECT %s, ui.id, ui.full_name, ui.avatar, ui.type FROM user_block b
+		INNER JOIN user_info ui ON ui.id = b.blocked_user_id
+		WHERE b.user_id = $1
+		ORDER BY b.created_at DESC`, strings.Join(fields, ", "))
+	rows, err := u.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userBlocks []*domain.UserBlockWithUser
+	for rows.Next() {
+		var userBlock domain.UserBlockWithUser
+		var user domain.UserInfo
+		_, values := userBlock.MapFields()
+		if err := rows.Scan(append(values, &user.ID, &user.FullName, &user.Avatar, &user.Type)...); err != nil {
+			return nil, err
+		}
+		userBlock.User = &user
+		userBlocks = append(userBlocks, &userBlock)
+	}
+	return userBlocks, rows.Err()
+}
+
+// scanUserIDs reads a list of user ids.
+func scanUserIDs(rows pgx.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// GetListBlockedUserID implements domain.UserBlockRepository.
+func (u *userBlockRepository) GetListBlockedUserID(ctx context.Context, userID string) ([]string, error) {
+	rows, err := u.db.Query(ctx, `SELECT blocked_user_id FROM user_block WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
+// GetListUserIDBlocking implements domain.UserBlockRepository.
+func (u *userBlockRepository) GetListUserIDBlocking(ctx context.Context, blockedUserID string, userIDs []string) ([]string, error) {
+	rows, err := u.db.Query(ctx, `SELECT user_id FROM user_block WHERE blocked_user_id = $1 AND user_id = ANY($2)`, blockedUserID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
+// IsBlockedBetween implements domain.UserBlockRepository.
+func (u *userBlockRepository) IsBlockedBetween(ctx context.Context, userID string, otherUserID string) (bool, error) {
+	var blocked bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_block
+			WHERE (user_id = $1 AND blocked_user_id = $2) OR (user_id = $2 AND blocked_user_id = $1)
+		)`
+	err := u.db.QueryRow(ctx, query, userID, otherUserID).Scan(&blocked)
+	return blocked, err
+}
+
+// IsBlockedInConversation implements domain.UserBlockRepository.
+func (u *userBlockRepository) IsBlockedInConversation(ctx context.Context, conversationID string, userID string) (bool, error) {
+	var blocked bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM conversation_member cm
+			INNER JOIN user_block b ON (b.user_id = $2 AND b.blocked_user_id = cm.user_id) OR (b.user_id = cm.user_id AND b.blocked_user_id = $2)
+			WHERE cm.conversation_id = $1 AND cm.user_id != $2
+		)`
+	err := u.db.QueryRow(ctx, query, conversationID, userID).Scan(&blocked)
+	return blocked, err
+}
+
+var _ domain.UserBlockRepository = &userBlockRepository{}
+
+func NewUserBlockRepository(db *pgxpool.Pool) *userBlockRepository {
+	return &userBlockRepository{db: db}
+}
@@ -32,6 +32,27 @@ func (u *userOnlineRepository) GetUserOnlineByConversationID(ctx context.Context
 	return userOnlines, nil
 }
 
+// GetUserOnlineByUserIDs implements domain.UserOnlineRepository.
+func (u *userOnlineRepository) GetUserOnlineByUserIDs(ctx context.Context, userIDs []string) ([]*domain.UserOnline, error) {
+	query := `SELECT id, user_id, connection_id, created_at FROM user_online WHERE user_id = ANY($1)`
+	rows, err := u.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userOnlines []*domain.UserOnline
+	for rows.Next() {
+		var userOnline domain.UserOnline
+		err := rows.Scan(&userOnline.ID, &userOnline.UserID, &userOnline.ConnectionID, &userOnline.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		userOnlines = append(userOnlines, &userOnline)
+	}
+	return userOnlines, nil
+}
+
 // CreateUserOnline implements domain.UserOnlineRepository.
 func (u *userOnlineRepository) CreateUserOnline(ctx context.Context, userOnline *domain.UserOnline) error {
 	query := `INSERT INTO user_online (id, user_id, connection_id, created_at) VALUES ($1, $2, $3, $4)`
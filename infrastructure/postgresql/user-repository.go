@@ -2,12 +2,14 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -27,8 +29,8 @@ func (u *userRepository) GetListUser(ctx context.Context, keyword string, limit
 	conditions := []string{}
 
 	if keyword != "" {
-		conditions = append(conditions, fmt.Sprintf("(full_name ILIKE $%d OR email ILIKE $%d)", len(args)+1, len(args)+2))
-		args = append(args, "%"+keyword+"%", "%"+keyword+"%")
+		conditions = append(conditions, fmt.Sprintf("(full_name ILIKE $%d OR email ILIKE $%d OR handle ILIKE $%d)", len(args)+1, len(args)+2, len(args)+3))
+		args = append(args, "%"+keyword+"%", "%"+keyword+"%", "%"+strings.TrimPrefix(keyword, "@")+"%")
 	}
 
 	if lastID != "" {
@@ -37,6 +39,7 @@ func (u *userRepository) GetListUser(ctx context.Context, keyword string, limit
 	}
 
 	conditions = append(conditions, "type = 'EXTERNAL'")
+	conditions = append(conditions, "deleted_at IS NULL")
 
 	if len(conditions) > 0 {
 		query = fmt.Sprintf("%s WHERE %s", query, strings.Join(conditions, " AND "))
@@ -78,17 +81,26 @@ func (u *userRepository) GetListUserWithConversation(ctx context.Context, userID
 				WHERE user_id = $1
 			)
 		)
-		SELECT %s, uc.conversation_id
+		SELECT %s, uc.conversation_id,
+			COALESCE((
+				SELECT CASE WHEN ct.status = '%s' THEN '%s' WHEN ct.user_id = $1 THEN '%s' ELSE '%s' END
+				FROM contact ct
+				WHERE (ct.user_id = $1 AND ct.friend_id = u.id) OR (ct.user_id = u.id AND ct.friend_id = $1)
+				ORDER BY ct.user_id = $1 DESC LIMIT 1
+			), '%s') AS contact_state
 		FROM %s u 
 		LEFT JOIN user_conversations uc ON u.id = uc.user_id
-		WHERE u.id != $1`, strings.Join(fields, ","), user.TableName())
+		WHERE u.id != $1
+			AND NOT EXISTS (SELECT 1 FROM user_block b WHERE b.user_id = u.id AND b.blocked_user_id = $1)`, strings.Join(fields, ","),
+		domain.ContactStatusAccepted, domain.ContactStateFriend, domain.ContactStateRequestSent, domain.ContactStateRequestReceived, domain.ContactStateNone,
+		user.TableName())
 
 	args := []any{userID}
 	conditions := []string{}
 
 	if keyword != "" {
-		conditions = append(conditions, fmt.Sprintf("(u.full_name ILIKE $%d OR u.email ILIKE $%d)", len(args)+1, len(args)+2))
-		args = append(args, "%"+keyword+"%", "%"+keyword+"%")
+		conditions = append(conditions, fmt.Sprintf("(u.full_name ILIKE $%d OR u.email ILIKE $%d OR u.handle ILIKE $%d)", len(args)+1, len(args)+2, len(args)+3))
+		args = append(args, "%"+keyword+"%", "%"+keyword+"%", "%"+strings.TrimPrefix(keyword, "@")+"%")
 	}
 
 	if lastID != "" {
@@ -97,6 +109,7 @@ func (u *userRepository) GetListUserWithConversation(ctx context.Context, userID
 	}
 
 	conditions = append(conditions, "u.type = 'EXTERNAL'")
+	conditions = append(conditions, "u.deleted_at IS NULL")
 
 	if len(conditions) > 0 {
 		query = fmt.Sprintf("%s AND %s", query, strings.Join(conditions, " AND "))
@@ -114,7 +127,7 @@ func (u *userRepository) GetListUserWithConversation(ctx context.Context, userID
 	for rows.Next() {
 		var user domain.UserInfo
 		_, values := user.MapFields()
-		values = append(values, &user.ConversationID)
+		values = append(values, &user.ConversationID, &user.ContactState)
 		if err := rows.Scan(values...); err != nil {
 			return nil, err
 		}
@@ -204,14 +217,113 @@ func (u *userRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 
 // UpdateUser implements domain.UserRepository.
 func (u *userRepository) UpdateUser(ctx context.Context, user *domain.UserInfo) error {
-	query := `UPDATE user_info SET full_name = $1, avatar = $2, updated_at = NOW() WHERE id = $3`
-	_, err := u.db.Exec(ctx, query, user.FullName, user.Avatar, user.ID)
+	query := `UPDATE user_info SET full_name = $1, avatar = $2, handle = $3, bio = $4, updated_at = NOW() WHERE id = $5`
+	_, err := u.db.Exec(ctx, query, user.FullName, user.Avatar, user.Handle, user.Bio, user.ID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return domain.ErrHandleTaken
+	}
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetUserByHandle implements domain.UserRepository.
+func (u *userRepository) GetUserByHandle(ctx context.Context, handle string) (*domain.UserInfo, error) {
+	var user domain.UserInfo
+	fields, values := user.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE lower(handle) = lower($1)`, strings.Join(fields, ","), user.TableName())
+	row := u.db.QueryRow(ctx, query, handle)
+	err := row.Scan(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetListUserByIDs implements domain.UserRepository.
+func (u *userRepository) GetListUserByIDs(ctx context.Context, ids []string) ([]*domain.UserInfo, error) {
+	var user domain.UserInfo
+	fields, _ := user.MapFields()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ANY($1)`, strings.Join(fields, ","), user.TableName())
+	rows, err := u.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*domain.UserInfo
+	for rows.Next() {
+		var user domain.UserInfo
+		_, values := user.MapFields()
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+// UpdatePrivacySetting implements domain.UserRepository.
+func (u *userRepository) UpdatePrivacySetting(ctx context.Context, user *domain.UserInfo) error {
+	query := `UPDATE user_info SET last_seen_privacy = $1, avatar_privacy = $2, direct_message_privacy = $3, group_add_privacy = $4, updated_at = NOW() WHERE id = $5`
+	_, err := u.db.Exec(ctx, query, user.LastSeenPrivacy, user.AvatarPrivacy, user.DirectMessagePrivacy, user.GroupAddPrivacy, user.ID)
+	return err
+}
+
+// UpdateUserStatus implements domain.UserRepository.
+func (u *userRepository) UpdateUserStatus(ctx context.Context, user *domain.UserInfo) error {
+	query := `UPDATE user_info SET status_emoji = $1, status_text = $2, status_expires_at = $3, updated_at = NOW() WHERE id = $4`
+	_, err := u.db.Exec(ctx, query, user.StatusEmoji, user.StatusText, user.StatusExpiresAt, user.ID)
+	return err
+}
+
+// ClearExpiredUserStatus implements domain.UserRepository.
+func (u *userRepository) ClearExpiredUserStatus(ctx context.Context, now time.Time) ([]string, error) {
+	query := `UPDATE user_info SET status_emoji = '', status_text = '', status_expires_at = NULL, updated_at = NOW()
+		WHERE status_expires_at <= $1 RETURNING id`
+	rows, err := u.db.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
+// GetListRelatedUserID implements domain.UserRepository.
+func (u *userRepository) GetListRelatedUserID(ctx context.Context, userID string) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT friend_id FROM contact WHERE user_id = $1 AND status = '%s'
+		UNION
+		SELECT cm.user_id FROM conversation_member cm
+		INNER JOIN conversation c ON c.id = cm.conversation_id
+		WHERE c.type IN ('%s', '%s')
+			AND cm.conversation_id IN (SELECT conversation_id FROM conversation_member WHERE user_id = $1)
+			AND cm.user_id != $1`, domain.ContactStatusAccepted, domain.ConversationTypeDM, domain.ConversationTypeGroup)
+	rows, err := u.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
+// GetListContactAndDMUserID implements domain.UserRepository.
+func (u *userRepository) GetListContactAndDMUserID(ctx context.Context, userID string) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT friend_id FROM contact WHERE user_id = $1 AND status = '%s'
+		UNION
+		SELECT cm.user_id FROM conversation_member cm
+		INNER JOIN conversation c ON c.id = cm.conversation_id
+		WHERE c.type = '%s'
+			AND cm.conversation_id IN (SELECT conversation_id FROM conversation_member WHERE user_id = $1)
+			AND cm.user_id != $1`, domain.ContactStatusAccepted, domain.ConversationTypeDM)
+	rows, err := u.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserIDs(rows)
+}
+
 func NewUserRepository(db *pgxpool.Pool, obs *observability.Observability) domain.UserRepository {
 	return &userRepository{
 		db:  db,
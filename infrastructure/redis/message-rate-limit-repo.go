@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// allowSendMessageScript checks and updates both counters atomically,
+// so concurrent sends on different app instances can not exceed the limits.
+// It returns the rejected rule (0 when allowed) and the milliseconds to wait.
+var allowSendMessageScript = redis.NewScript(`
+local slow_mode = tonumber(ARGV[1])
+local daily_limit = tonumber(ARGV[2])
+if slow_mode > 0 then
+	local ttl = redis.call('PTTL', KEYS[1])
+	if ttl > 0 then
+		return {1, ttl}
+	end
+end
+if daily_limit > 0 then
+	local count = tonumber(redis.call('GET', KEYS[2]) or '0')
+	if count >= daily_limit then
+		return {2, redis.call('PTTL', KEYS[2])}
+	end
+	redis.call('INCR', KEYS[2])
+	if count == 0 then
+		redis.call('PEXPIRE', KEYS[2], ARGV[3])
+	end
+end
+if slow_mode > 0 then
+	redis.call('SET', KEYS[1], 1, 'PX', slow_mode)
+end
+return {0, 0}
+`)
+
+type messageRateLimitRepository struct {
+	client *redis.Client
+}
+
+func slowModeKey(conversationID string, userID string) string {
+	return fmt.Sprintf("slow_mode:%s:%s", conversationID, userID)
+}
+
+// dailyMessageKey changes every day (UTC), so the counter resets at midnight.
+func dailyMessageKey(conversationID string, userID string, now time.Time) string {
+	return fmt.Sprintf("daily_message:%s:%s:%s", conversationID, userID, now.UTC().Format("20060102"))
+}
+
+// AllowSendMessage implements domain.MessageRateLimitRepository.
+func (m *messageRateLimitRepository) AllowSendMessage(ctx context.Context, conversationID string, userID string, slowMode time.Duration, dailyLimit int, now time.Time) error {
+	nextDay := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	keys := []string{slowModeKey(conversationID, userID), dailyMessageKey(conversationID, userID, now)}
+	result, err := allowSendMessageScript.Run(ctx, m.client, keys, slowMode.Milliseconds(), dailyLimit, nextDay.Sub(now).Milliseconds()).Int64Slice()
+	if err != nil {
+		return err
+	}
+
+	retryAfter := time.Duration(result[1]) * time.Millisecond
+	switch result[0] {
+	case 1:
+		return &domain.RateLimitError{Reason: domain.RateLimitReasonSlowMode, RetryAfter: retryAfter}
+	case 2:
+		return &domain.RateLimitError{Reason: domain.RateLimitReasonDailyLimit, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func NewMessageRateLimitRepository(client *redis.Client) *messageRateLimitRepository {
+	return &messageRateLimitRepository{
+		client: client,
+	}
+}
+
+var _ domain.MessageRateLimitRepository = &messageRateLimitRepository{}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// messageViewRepository keeps a HyperLogLog of the viewers per message,
+// the counts are approximate but the memory does not grow with the number of viewers.
+type messageViewRepository struct {
+	client *redis.Client
+}
+
+func messageViewKey(messageID string) string {
+	return fmt.Sprintf("message_view:%s", messageID)
+}
+
+// AddMessageViews implements domain.MessageViewRepository.
+func (m *messageViewRepository) AddMessageViews(ctx context.Context, userID string, messageIDs []string) error {
+	pipe := m.client.Pipeline()
+	for _, messageID := range messageIDs {
+		pipe.PFAdd(ctx, messageViewKey(messageID), userID)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CountMessageViews implements domain.MessageViewRepository.
+func (m *messageViewRepository) CountMessageViews(ctx context.Context, messageIDs []string) (map[string]int64, error) {
+	pipe := m.client.Pipeline()
+	cmds := make(map[string]*redis.IntCmd, len(messageIDs))
+	for _, messageID := range messageIDs {
+		cmds[messageID] = pipe.PFCount(ctx, messageViewKey(messageID))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make(map[string]int64, len(messageIDs))
+	for messageID, cmd := range cmds {
+		views[messageID] = cmd.Val()
+	}
+	return views, nil
+}
+
+func NewMessageViewRepository(client *redis.Client) *messageViewRepository {
+	return &messageViewRepository{
+		client: client,
+	}
+}
+
+var _ domain.MessageViewRepository = &messageViewRepository{}
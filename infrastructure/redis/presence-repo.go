@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/redis/go-redis/v9"
+)
+
+// refreshConnectionScript keeps the status of the connection, so a refresh racing with a disconnect
+// or a status change does not bring back an old value.
+var refreshConnectionScript = redis.NewScript(`
+local value = redis.call('HGET', KEYS[1], ARGV[1])
+if not value then
+	return 0
+end
+local status = string.match(value, '^[^:]+')
+redis.call('HSET', KEYS[1], ARGV[1], status .. ':' .. ARGV[2])
+redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+redis.call('ZADD', KEYS[2], 'GT', ARGV[2], ARGV[3])
+return 1
+`)
+
+// claimDueCheckScript pushes the due checks back to the lease and returns their users.
+var claimDueCheckScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, userID in ipairs(due) do
+	redis.call('ZADD', KEYS[1], ARGV[2], userID)
+end
+return due
+`)
+
+// completeCheckScript only removes the check still at its lease, a check scheduled later is kept.
+var completeCheckScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if score and tonumber(score) == tonumber(ARGV[2]) then
+	redis.call('ZREM', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// presenceCheckKey holds the users whose presence must be checked, scored by the time of the check in unix ms.
+// The checks outlive the instances, the offline events are sent even when the connections were on a crashed one.
+const presenceCheckKey = "presence_check"
+
+// publishedStatusTTL only bounds the keys of the users who left, a missing status is published again.
+const publishedStatusTTL = 24 * time.Hour
+
+type presenceRepository struct {
+	client *redis.Client
+}
+
+// presenceKey holds a field per connection of the user, valued "<status>:<expiration in unix ms>".
+func presenceKey(userID string) string {
+	return fmt.Sprintf("presence:%s", userID)
+}
+
+func lastSeenKey(userID string) string {
+	return fmt.Sprintf("presence_last_seen:%s", userID)
+}
+
+func publishedStatusKey(userID string) string {
+	return fmt.Sprintf("presence_status:%s", userID)
+}
+
+// SetConnectionStatus implements domain.PresenceRepository.
+func (p *presenceRepository) SetConnectionStatus(ctx context.Context, userID string, connectionID string, status string, expiredAt time.Time) error {
+	pipe := p.client.TxPipeline()
+	pipe.HSet(ctx, presenceKey(userID), connectionID, fmt.Sprintf("%s:%d", status, expiredAt.UnixMilli()))
+	pipe.PExpireAt(ctx, presenceKey(userID), expiredAt)
+	pipe.ZAddGT(ctx, presenceCheckKey, redis.Z{Score: float64(expiredAt.UnixMilli()), Member: userID})
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RefreshConnection implements domain.PresenceRepository.
+func (p *presenceRepository) RefreshConnection(ctx context.Context, userID string, connectionID string, expiredAt time.Time) error {
+	return refreshConnectionScript.Run(ctx, p.client, []string{presenceKey(userID), presenceCheckKey}, connectionID, expiredAt.UnixMilli(), userID).Err()
+}
+
+// ScheduleCheck implements domain.PresenceRepository.
+func (p *presenceRepository) ScheduleCheck(ctx context.Context, userID string, at time.Time) error {
+	// the check left at the expiration of the connection is brought forward, a connection still open
+	// pushes it back on its next refresh
+	return p.client.ZAddLT(ctx, presenceCheckKey, redis.Z{Score: float64(at.UnixMilli()), Member: userID}).Err()
+}
+
+// ClaimDueCheck implements domain.PresenceRepository.
+func (p *presenceRepository) ClaimDueCheck(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]string, error) {
+	return claimDueCheckScript.Run(ctx, p.client, []string{presenceCheckKey}, now.UnixMilli(), leaseUntil.UnixMilli(), limit).StringSlice()
+}
+
+// CompleteCheck implements domain.PresenceRepository.
+func (p *presenceRepository) CompleteCheck(ctx context.Context, userID string, leaseUntil time.Time) error {
+	return completeCheckScript.Run(ctx, p.client, []string{presenceCheckKey}, userID, leaseUntil.UnixMilli()).Err()
+}
+
+// DeleteConnection implements domain.PresenceRepository.
+func (p *presenceRepository) DeleteConnection(ctx context.Context, userID string, connectionID string) error {
+	return p.client.HDel(ctx, presenceKey(userID), connectionID).Err()
+}
+
+// SetLastSeenAt implements domain.PresenceRepository.
+func (p *presenceRepository) SetLastSeenAt(ctx context.Context, userID string, lastSeenAt time.Time) error {
+	return p.client.Set(ctx, lastSeenKey(userID), lastSeenAt.UnixMilli(), 0).Err()
+}
+
+// GetListPresence implements domain.PresenceRepository.
+func (p *presenceRepository) GetListPresence(ctx context.Context, userIDs []string, now time.Time) ([]*domain.Presence, error) {
+	pipe := p.client.Pipeline()
+	connections := make([]*redis.MapStringStringCmd, len(userIDs))
+	lastSeens := make([]*redis.StringCmd, len(userIDs))
+	for i, userID := range userIDs {
+		connections[i] = pipe.HGetAll(ctx, presenceKey(userID))
+		lastSeens[i] = pipe.Get(ctx, lastSeenKey(userID))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	presences := make([]*domain.Presence, len(userIDs))
+	for i, userID := range userIDs {
+		presence := &domain.Presence{
+			UserID: userID,
+			Status: domain.PresenceStatusOffline,
+		}
+		var expiredConnectionIDs []string
+		for connectionID, value := range connections[i].Val() {
+			status, expiredAt, ok := strings.Cut(value, ":")
+			expiredAtMilli, err := strconv.ParseInt(expiredAt, 10, 64)
+			if !ok || err != nil || expiredAtMilli <= now.UnixMilli() {
+				expiredConnectionIDs = append(expiredConnectionIDs, connectionID)
+				continue
+			}
+			if status == domain.PresenceStatusOnline || presence.Status == domain.PresenceStatusOffline {
+				presence.Status = status
+			}
+		}
+		// the connections of a crashed instance are never deleted
+		if len(expiredConnectionIDs) > 0 {
+			p.client.HDel(ctx, presenceKey(userID), expiredConnectionIDs...)
+		}
+
+		lastSeenAt, err := lastSeens[i].Int64()
+		if err == nil {
+			presence.LastSeenAt = pointer.ToPtr(time.UnixMilli(lastSeenAt))
+		}
+		presences[i] = presence
+	}
+	return presences, nil
+}
+
+// SwapPublishedStatus implements domain.PresenceRepository.
+func (p *presenceRepository) SwapPublishedStatus(ctx context.Context, userID string, status string) (string, error) {
+	previous, err := p.client.SetArgs(ctx, publishedStatusKey(userID), status, redis.SetArgs{
+		TTL: publishedStatusTTL,
+		Get: true,
+	}).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return previous, err
+}
+
+func NewPresenceRepository(client *redis.Client) *presenceRepository {
+	return &presenceRepository{
+		client: client,
+	}
+}
+
+var _ domain.PresenceRepository = &presenceRepository{}
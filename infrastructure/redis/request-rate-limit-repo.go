@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// allowRequestScript counts the request and starts the window on the first one,
+// it returns the count including this request.
+var allowRequestScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+type requestRateLimitRepository struct {
+	client *redis.Client
+}
+
+func requestRateLimitKey(key string) string {
+	return fmt.Sprintf("request_rate_limit:%s", key)
+}
+
+// AllowRequest implements domain.RequestRateLimitRepository.
+func (r *requestRateLimitRepository) AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	count, err := allowRequestScript.Run(ctx, r.client, []string{requestRateLimitKey(key)}, window.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return count <= int64(limit), nil
+}
+
+func NewRequestRateLimitRepository(client *redis.Client) *requestRateLimitRepository {
+	return &requestRateLimitRepository{
+		client: client,
+	}
+}
+
+var _ domain.RequestRateLimitRepository = &requestRateLimitRepository{}
@@ -30,6 +30,15 @@ func (u *userCacheRepository) SetUserIDByAccountID(ctx context.Context, accountI
 	return nil
 }
 
+// DeleteUserIDByAccountID implements domain.UserCacheRepository.
+func (u *userCacheRepository) DeleteUserIDByAccountID(ctx context.Context, accountID string) error {
+	_, err := u.client.Del(ctx, fmt.Sprintf("user_id:%s", accountID)).Result()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func NewUserCacheRepository(client *redis.Client) *userCacheRepository {
 	return &userCacheRepository{
 		client: client,
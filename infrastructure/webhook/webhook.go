@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/chat-socio/backend/pkg/webhook"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender posts the events with the given timeout. The receiver checks the signature header,
+// "sha256=" followed by the hex HMAC-SHA256 of the body with the webhook secret.
+func NewHTTPSender(timeout time.Duration) webhook.Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: checkAddress,
+	}
+	return &httpSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// no proxy, the address checked is the one connected to
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// a redirect could lead the request inside the network, it is reported as a failure
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// checkAddress refuses to connect to an address inside the network of the server,
+// it runs after the host is resolved so a host resolving to another address later is caught too.
+func checkAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || webhook.IsForbiddenIP(ip) {
+		return webhook.ErrForbiddenAddress
+	}
+	return nil
+}
+
+// Send implements webhook.Sender.
+func (h *httpSender) Send(ctx context.Context, request *webhook.Request) error {
+	mac := hmac.New(sha256.New, []byte(request.Secret))
+	mac.Write(request.Body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, request.Event)
+	req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
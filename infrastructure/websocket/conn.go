@@ -6,8 +6,10 @@ import (
 )
 
 type WSConnection struct {
-	conn *websocket.Conn
-	id   string
+	conn   *websocket.Conn
+	id     string
+	userID string
+	topics map[string]struct{} // guarded by the lock of WebSocket
 }
 
 func (wsConn *WSConnection) SendMessage(message []byte) error {
@@ -50,6 +52,14 @@ func (wsConn *WSConnection) SetID(id string) {
 	wsConn.id = id
 }
 
+func (wsConn *WSConnection) GetUserID() string {
+	return wsConn.userID
+}
+
+func (wsConn *WSConnection) SetUserID(userID string) {
+	wsConn.userID = userID
+}
+
 func NewWSConnection(conn *websocket.Conn) (*WSConnection, error) {
 	// Generate a unique ID for the connection
 	id, err := uuid.NewID()
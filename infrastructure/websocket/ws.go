@@ -9,12 +9,14 @@ import (
 
 type WebSocket struct {
 	mapConnections map[string]*WSConnection
+	mapTopics      map[string]map[string]*WSConnection // topic -> connection id -> connection
 	lock           *sync.RWMutex
 }
 
 func NewWebSocket() *WebSocket {
 	return &WebSocket{
 		mapConnections: make(map[string]*WSConnection),
+		mapTopics:      make(map[string]map[string]*WSConnection),
 		lock:           &sync.RWMutex{},
 	}
 }
@@ -53,9 +55,57 @@ func (ws *WebSocket) RemoveConnection(id string) {
 	defer ws.lock.Unlock()
 	if conn, ok := ws.mapConnections[id]; ok {
 		conn.Close()
+		for topic := range conn.topics {
+			ws.unsubscribe(topic, conn)
+		}
 		delete(ws.mapConnections, id)
 	}
 }
+
+// Subscribe registers the connection to the topic, events of the topic are then sent
+// to the connections of this instance without looking up the subscribers.
+func (ws *WebSocket) Subscribe(topic string, id string) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	conn, ok := ws.mapConnections[id]
+	if !ok {
+		return
+	}
+	if conn.topics == nil {
+		conn.topics = make(map[string]struct{})
+	}
+	conn.topics[topic] = struct{}{}
+	if _, ok := ws.mapTopics[topic]; !ok {
+		ws.mapTopics[topic] = make(map[string]*WSConnection)
+	}
+	ws.mapTopics[topic][id] = conn
+}
+
+func (ws *WebSocket) Unsubscribe(topic string, id string) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	if conn, ok := ws.mapConnections[id]; ok {
+		ws.unsubscribe(topic, conn)
+	}
+}
+
+func (ws *WebSocket) unsubscribe(topic string, conn *WSConnection) {
+	delete(conn.topics, topic)
+	delete(ws.mapTopics[topic], conn.id)
+	if len(ws.mapTopics[topic]) == 0 {
+		delete(ws.mapTopics, topic)
+	}
+}
+
+func (ws *WebSocket) GetTopicConnections(topic string) []*WSConnection {
+	ws.lock.RLock()
+	defer ws.lock.RUnlock()
+	connections := make([]*WSConnection, 0, len(ws.mapTopics[topic]))
+	for _, conn := range ws.mapTopics[topic] {
+		connections = append(connections, conn)
+	}
+	return connections
+}
 func (ws *WebSocket) GetAllConnections() []*WSConnection {
 	ws.lock.RLock()
 	defer ws.lock.RUnlock()
@@ -2,12 +2,31 @@ package domain
 
 import "time"
 
+const (
+	AccountDeletionGracePeriod = 30 * 24 * time.Hour
+	// DeletedUserFullName replaces the name of the deleted users, their messages stay for the other participants
+	DeletedUserFullName = "Deleted user"
+
+	AccountRoleUser  = "user"
+	AccountRoleAdmin = "admin"
+)
+
 type Account struct {
 	ID        string     `json:"id,omitempty"`
 	Username  string     `json:"username,omitempty"`
 	Password  string     `json:"-"`
 	CreatedAt *time.Time `json:"created_at,omitempty"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// EmailVerifiedAt is nil until the owner follows the link of the verification email
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// DeletionScheduledAt is the end of the grace period of a deletion request, the account can still cancel it before
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+	Role                string     `json:"role,omitempty"`
+	// SuspendedUntil and BannedAt are set by the admins, the account can not log in meanwhile
+	SuspendedUntil    *time.Time `json:"suspended_until,omitempty"`
+	BannedAt          *time.Time `json:"banned_at,omitempty"`
+	RestrictionReason string     `json:"restriction_reason,omitempty"`
 }
 
 func (a *Account) TableName() string {
@@ -21,11 +40,44 @@ func (a *Account) MapFields() ([]string, []any) {
 			"password",
 			"created_at",
 			"updated_at",
+			"email_verified_at",
+			"deletion_scheduled_at",
+			"deleted_at",
+			"role",
+			"suspended_until",
+			"banned_at",
+			"restriction_reason",
 		}, []any{
 			&a.ID,
 			&a.Username,
 			&a.Password,
 			&a.CreatedAt,
 			&a.UpdatedAt,
+			&a.EmailVerifiedAt,
+			&a.DeletionScheduledAt,
+			&a.DeletedAt,
+			&a.Role,
+			&a.SuspendedUntil,
+			&a.BannedAt,
+			&a.RestrictionReason,
 		}
 }
+
+func (a *Account) IsEmailVerified() bool {
+	return a.EmailVerifiedAt != nil
+}
+
+func (a *Account) IsAdmin() bool {
+	return a.Role == AccountRoleAdmin
+}
+
+// RestrictionError returns ErrAccountBanned or ErrAccountSuspended when the account can not log in at now.
+func (a *Account) RestrictionError(now time.Time) error {
+	if a.BannedAt != nil {
+		return ErrAccountBanned
+	}
+	if a.SuspendedUntil != nil && a.SuspendedUntil.After(now) {
+		return ErrAccountSuspended
+	}
+	return nil
+}
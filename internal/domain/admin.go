@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+const (
+	AdminActionListUser           = "LIST_USER"
+	AdminActionListSession        = "LIST_SESSION"
+	AdminActionSuspendAccount     = "SUSPEND_ACCOUNT"
+	AdminActionBanAccount         = "BAN_ACCOUNT"
+	AdminActionReinstateAccount   = "REINSTATE_ACCOUNT"
+	AdminActionResetPassword      = "RESET_PASSWORD"
+	AdminActionDeleteConversation = "DELETE_CONVERSATION"
+
+	AdminTargetUser         = "user"
+	AdminTargetAccount      = "account"
+	AdminTargetConversation = "conversation"
+
+	MaxAdminReasonLength = 500
+)
+
+// AdminAuditLog records an action of an admin, Detail holds its parameters like the reason or the search keyword.
+type AdminAuditLog struct {
+	ID             string         `json:"id,omitempty"`
+	AdminAccountID string         `json:"admin_account_id,omitempty"`
+	Action         string         `json:"action,omitempty"`
+	TargetType     string         `json:"target_type,omitempty"`
+	TargetID       string         `json:"target_id,omitempty"`
+	Detail         map[string]any `json:"detail,omitempty"`
+	CreatedAt      *time.Time     `json:"created_at,omitempty"`
+}
+
+func (a *AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
+
+func (a *AdminAuditLog) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"admin_account_id",
+			"action",
+			"target_type",
+			"target_id",
+			"detail",
+			"created_at",
+		}, []any{
+			&a.ID,
+			&a.AdminAccountID,
+			&a.Action,
+			&a.TargetType,
+			&a.TargetID,
+			&a.Detail,
+			&a.CreatedAt,
+		}
+}
+
+// AccountWithUser is an account with its user, as listed to the admins.
+type AccountWithUser struct {
+	Account *Account
+	User    *UserInfo
+}
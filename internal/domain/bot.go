@@ -0,0 +1,91 @@
+package domain
+
+import "time"
+
+const (
+	MaxBotPerOwner        = 20
+	MaxBotTokenPerBot     = 10
+	MaxBotTokenNameLength = 100
+	// BotWebhookTimeout bounds the delivery of an event to the webhook of a bot.
+	BotWebhookTimeout = 10 * time.Second
+)
+
+// The reasons sent to the websockets of a bot when they are closed.
+const (
+	BotSessionRevokedReasonDeleted      = "BOT_DELETED"
+	BotSessionRevokedReasonTokenRevoked = "BOT_TOKEN_REVOKED"
+)
+
+// Bot is an INTERNAL user created and managed by its owner. It authenticates with bot tokens instead of
+// a password, and receives the events of its conversations on its webhook or its own websocket connection.
+type Bot struct {
+	ID            string     `json:"id,omitempty"`
+	UserID        string     `json:"user_id,omitempty"`
+	OwnerID       string     `json:"owner_id,omitempty"`
+	WebhookURL    string     `json:"webhook_url,omitempty"`
+	WebhookSecret string     `json:"-"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (b *Bot) TableName() string {
+	return "bot"
+}
+
+func (b *Bot) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"user_id",
+			"owner_id",
+			"webhook_url",
+			"webhook_secret",
+			"created_at",
+			"updated_at",
+			"deleted_at",
+		}, []any{
+			&b.ID,
+			&b.UserID,
+			&b.OwnerID,
+			&b.WebhookURL,
+			&b.WebhookSecret,
+			&b.CreatedAt,
+			&b.UpdatedAt,
+			&b.DeletedAt,
+		}
+}
+
+// BotToken authenticates a bot until it is revoked, only its hash is stored.
+type BotToken struct {
+	ID         string     `json:"id,omitempty"`
+	BotID      string     `json:"bot_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	TokenHash  string     `json:"-"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (b *BotToken) TableName() string {
+	return "bot_token"
+}
+
+func (b *BotToken) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"bot_id",
+			"name",
+			"token_hash",
+			"created_at",
+			"last_used_at",
+			"revoked_at",
+		}, []any{
+			&b.ID,
+			&b.BotID,
+			&b.Name,
+			&b.TokenHash,
+			&b.CreatedAt,
+			&b.LastUsedAt,
+			&b.RevokedAt,
+		}
+}
@@ -2,10 +2,24 @@ package domain
 
 import "time"
 
+const (
+	ContactStatusPending  = "PENDING"
+	ContactStatusAccepted = "ACCEPTED"
+)
+
+// contact state of a user as seen by another user
+const (
+	ContactStateNone            = "NONE"
+	ContactStateFriend          = "FRIEND"
+	ContactStateRequestSent     = "REQUEST_SENT"
+	ContactStateRequestReceived = "REQUEST_RECEIVED"
+)
+
 type Contact struct {
 	ID        string     `json:"id,omitempty"`
 	UserID    string     `json:"user_id,omitempty"`
 	FriendID  string     `json:"friend_id,omitempty"`
+	Status    string     `json:"status,omitempty"` // a pending contact is a friend request sent by the user
 	CreatedAt *time.Time `json:"created_at,omitempty"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
@@ -19,13 +33,22 @@ func (c *Contact) MapFields() ([]string, []any) {
 			"id",
 			"user_id",
 			"friend_id",
+			"status",
 			"created_at",
 			"updated_at", 
 		}, []any{
 			&c.ID,
 			&c.UserID,
 			&c.FriendID,
+			&c.Status,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		}
 }
+
+// ContactWithUser is a contact or a friend request with the other user of it.
+type ContactWithUser struct {
+	Contact
+	User     *UserInfo
+	IsOnline bool
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+const (
+	MaxConversationFolder   = 10
+	MaxPinnedConversation   = 5
+	MaxConversationInFolder = 200
+)
+
+// ConversationFolder groups conversations of a user, a conversation belongs to the folder
+// when it is added explicitly or when its type matches ConversationTypes.
+type ConversationFolder struct {
+	ID                string     `json:"id,omitempty"`
+	UserID            string     `json:"user_id,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	ConversationTypes []string   `json:"conversation_types,omitempty"`
+	Position          int        `json:"position,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+	ConversationIDs   []string   `json:"conversation_ids,omitempty"`
+}
+
+func (c *ConversationFolder) TableName() string {
+	return "conversation_folder"
+}
+
+func (c *ConversationFolder) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"user_id",
+			"name",
+			"conversation_types",
+			"position",
+			"created_at",
+			"updated_at",
+		}, []any{
+			&c.ID,
+			&c.UserID,
+			&c.Name,
+			&c.ConversationTypes,
+			&c.Position,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+type ConversationInviteLink struct {
+	ID              string     `json:"id,omitempty"`
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	Token           string     `json:"token,omitempty"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	MaxUses         int        `json:"max_uses,omitempty"` // 0 means unlimited
+	UsedCount       int        `json:"used_count,omitempty"`
+	RequireApproval bool       `json:"require_approval,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func (c *ConversationInviteLink) TableName() string {
+	return "conversation_invite_link"
+}
+
+func (c *ConversationInviteLink) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"conversation_id",
+			"token",
+			"created_by",
+			"expired_at",
+			"max_uses",
+			"used_count",
+			"require_approval",
+			"revoked_at",
+			"created_at",
+			"updated_at",
+		}, []any{
+			&c.ID,
+			&c.ConversationID,
+			&c.Token,
+			&c.CreatedBy,
+			&c.ExpiredAt,
+			&c.MaxUses,
+			&c.UsedCount,
+			&c.RequireApproval,
+			&c.RevokedAt,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		}
+}
+
+// IsUsable reports whether the link can still be used to join at the given time.
+func (c *ConversationInviteLink) IsUsable(now time.Time) bool {
+	if c.RevokedAt != nil {
+		return false
+	}
+	if c.ExpiredAt != nil && !c.ExpiredAt.After(now) {
+		return false
+	}
+	if c.MaxUses > 0 && c.UsedCount >= c.MaxUses {
+		return false
+	}
+	return true
+}
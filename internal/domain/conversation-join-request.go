@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+const (
+	JoinRequestStatusPending  = "PENDING"
+	JoinRequestStatusApproved = "APPROVED"
+	JoinRequestStatusRejected = "REJECTED"
+)
+
+// ConversationJoinRequest is created instead of a membership when the conversation requires
+// the approval of an admin to join.
+type ConversationJoinRequest struct {
+	ID             string     `json:"id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	UserID         string     `json:"user_id,omitempty"`
+	InviteLinkID   *string    `json:"invite_link_id,omitempty"` // nil when the user found the conversation by discovery
+	Status         string     `json:"status,omitempty"`
+	ReviewedBy     *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+func (c *ConversationJoinRequest) TableName() string {
+	return "conversation_join_request"
+}
+
+func (c *ConversationJoinRequest) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"conversation_id",
+			"user_id",
+			"invite_link_id",
+			"status",
+			"reviewed_by",
+			"reviewed_at",
+			"created_at",
+			"updated_at",
+		}, []any{
+			&c.ID,
+			&c.ConversationID,
+			&c.UserID,
+			&c.InviteLinkID,
+			&c.Status,
+			&c.ReviewedBy,
+			&c.ReviewedAt,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		}
+}
+
+type ConversationJoinRequestWithUser struct {
+	ConversationJoinRequest
+	FullName string
+	Avatar   string
+	UserType string
+}
@@ -2,14 +2,30 @@ package domain
 
 import "time"
 
+const (
+	ConversationMemberRoleOwner  = "OWNER"
+	ConversationMemberRoleAdmin  = "ADMIN"
+	ConversationMemberRoleMember = "MEMBER"
+
+	NotificationLevelAll      = "ALL"
+	NotificationLevelMentions = "MENTIONS"
+)
+
 type ConversationMember struct {
-	ID             string     `json:"id,omitempty"`
-	ConversationID string     `json:"conversation_id,omitempty"`
-	UserID         string     `json:"user_id,omitempty"`
-	CreatedAt      *time.Time `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
-	User           *UserInfo  `json:"-"`
+	ID                string     `json:"id,omitempty"`
+	ConversationID    string     `json:"conversation_id,omitempty"`
+	UserID            string     `json:"user_id,omitempty"`
+	Role              string     `json:"role,omitempty"`
+	NotificationLevel string     `json:"notification_level,omitempty"`
+	MutedUntil        *time.Time `json:"muted_until,omitempty"`
+	MutedForever      bool       `json:"muted_forever,omitempty"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	HistoryClearedAt  *time.Time `json:"history_cleared_at,omitempty"` // messages before are hidden to the member
+	PinnedAt          *time.Time `json:"pinned_at,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+	User              *UserInfo  `json:"-"`
 }
 
 func (c *ConversationMember) TableName() string {
@@ -21,6 +37,13 @@ func (c *ConversationMember) MapFields() ([]string, []any) {
 			"id",
 			"conversation_id",
 			"user_id",
+			"role",
+			"notification_level",
+			"muted_until",
+			"muted_forever",
+			"archived_at",
+			"history_cleared_at",
+			"pinned_at",
 			"created_at",
 			"updated_at",
 			"deleted_at",
@@ -28,16 +51,49 @@ func (c *ConversationMember) MapFields() ([]string, []any) {
 			&c.ID,
 			&c.ConversationID,
 			&c.UserID,
+			&c.Role,
+			&c.NotificationLevel,
+			&c.MutedUntil,
+			&c.MutedForever,
+			&c.ArchivedAt,
+			&c.HistoryClearedAt,
+			&c.PinnedAt,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 			&c.DeletedAt,
 		}
 }
 
+// IsAdmin reports whether the member can manage the conversation settings.
+func (c *ConversationMember) IsAdmin() bool {
+	return c.Role == ConversationMemberRoleOwner || c.Role == ConversationMemberRoleAdmin
+}
+
+// IsMuted reports whether the conversation is muted for the member at the given time.
+func (c *ConversationMember) IsMuted(now time.Time) bool {
+	return c.MutedForever || (c.MutedUntil != nil && c.MutedUntil.After(now))
+}
+
+// ShouldNotify is the single place deciding whether the member is alerted about a message,
+// every notification delivery path must go through it. senderBlocked tells whether the member blocked the sender.
+func (c *ConversationMember) ShouldNotify(message *Message, senderBlocked bool, now time.Time) bool {
+	if message.UserID == c.UserID || senderBlocked || c.IsMuted(now) {
+		return false
+	}
+	if c.NotificationLevel == NotificationLevelMentions {
+		return message.Mentions(c.UserID)
+	}
+	return true
+}
+
 type ConversationMemberWithUser struct {
-	ConversationID string
-	UserID         string
-	FullName       string
-	Avatar         string
-	UserType       string
+	ConversationID  string
+	UserID          string
+	Role            string
+	FullName        string
+	Avatar          string
+	UserType        string
+	StatusEmoji     string
+	StatusText      string
+	StatusExpiresAt *time.Time
 }
@@ -1,23 +1,39 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+)
 
 const (
 	ConversationTypeDM    = "DM"
 	ConversationTypeGroup = "GROUP"
+	// ConversationTypeChannel is a broadcast conversation, only admins post and subscribers read and react.
+	ConversationTypeChannel = "CHANNEL"
 )
 
 type Conversation struct {
-	ID            string      `json:"id,omitempty"`
-	CreatedAt     *time.Time  `json:"created_at,omitempty"`
-	Type          string      `json:"type,omitempty"`
-	Title         string      `json:"title,omitempty"`
-	Avatar        string      `json:"avatar,omitempty"`
-	UpdatedAt     *time.Time  `json:"updated_at,omitempty"`
-	DeletedAt     *time.Time  `json:"deleted_at,omitempty"`
-	LastMessageID string      `json:"last_message_id,omitempty"`
-	LastMessage   *Message    `json:"-"`
-	Members       []*UserInfo `json:"members,omitempty"`
+	ID                string              `json:"id,omitempty"`
+	CreatedAt         *time.Time          `json:"created_at,omitempty"`
+	Type              string              `json:"type,omitempty"`
+	Title             string              `json:"title,omitempty"`
+	Avatar            string              `json:"avatar,omitempty"`
+	UpdatedAt         *time.Time          `json:"updated_at,omitempty"`
+	DeletedAt         *time.Time          `json:"deleted_at,omitempty"`
+	LastMessageID     string              `json:"last_message_id,omitempty"`
+	LastMessageSeq    int64               `json:"last_message_seq,omitempty"` // seq of the latest message, the next one takes the following
+	IsPublic          bool                `json:"is_public,omitempty"`
+	Description       string              `json:"description,omitempty"`
+	RequireApproval   bool                `json:"require_approval,omitempty"`    // joins by invite link or discovery become join requests
+	SlowModeSeconds   int                 `json:"slow_mode_seconds,omitempty"`   // minimum interval between two messages of a member
+	DailyMessageLimit int                 `json:"daily_message_limit,omitempty"` // messages a member can send per day
+	DMKey             *string             `json:"-"`
+	LastMessage       *Message            `json:"-"`
+	Members           []*UserInfo         `json:"members,omitempty"`
+	Membership        *ConversationMember `json:"-"` // settings of the user listing the conversations
+	UnreadCount       int                 `json:"-"`
+	MemberCount       int                 `json:"-"`
 }
 
 func (c *Conversation) TableName() string {
@@ -34,6 +50,12 @@ func (c *Conversation) MapFields() ([]string, []any) {
 			"updated_at",
 			"deleted_at",
 			"last_message_id",
+			"last_message_seq",
+			"is_public",
+			"description",
+			"require_approval",
+			"slow_mode_seconds",
+			"daily_message_limit",
 		}, []any{
 			&c.ID,
 			&c.CreatedAt,
@@ -43,5 +65,30 @@ func (c *Conversation) MapFields() ([]string, []any) {
 			&c.UpdatedAt,
 			&c.DeletedAt,
 			&c.LastMessageID,
+			&c.LastMessageSeq,
+			&c.IsPublic,
+			&c.Description,
+			&c.RequireApproval,
+			&c.SlowModeSeconds,
+			&c.DailyMessageLimit,
 		}
 }
+
+// HasSendRateLimit reports whether the messages of the members are rate limited, admins are exempted.
+func (c *Conversation) HasSendRateLimit() bool {
+	return c.SlowModeSeconds > 0 || c.DailyMessageLimit > 0
+}
+
+// NewDMKey returns the key identifying the DM between two users, whatever the order of the users is.
+func NewDMKey(userID string, otherUserID string) string {
+	userIDs := []string{userID, otherUserID}
+	slices.Sort(userIDs)
+	return strings.Join(userIDs, ":")
+}
+
+// ConversationFilter narrows the list of conversations of a user.
+type ConversationFilter struct {
+	Archived bool
+	Pinned   bool // only the pinned conversations when true, only the others otherwise
+	Folder   *ConversationFolder
+}
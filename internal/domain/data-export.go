@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+const (
+	DataExportStatusPending   = "PENDING"
+	DataExportStatusCompleted = "COMPLETED"
+	DataExportStatusFailed    = "FAILED"
+
+	DataExportBucketName = "data-export"
+	// DataExportURLExpiration is how long a download link of an archive works
+	DataExportURLExpiration = time.Hour
+	// a new export can be requested once the previous one is older than the interval
+	DataExportInterval = 24 * time.Hour
+)
+
+// DataExport is a "download my data" job, the archive gathers the account, the profile, the sessions,
+// the conversations and the messages written by the user.
+type DataExport struct {
+	ID          string     `json:"id,omitempty"`
+	AccountID   string     `json:"account_id,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	BucketName  string     `json:"bucket_name,omitempty"`
+	ObjectName  string     `json:"object_name,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (d *DataExport) TableName() string {
+	return "data_export"
+}
+
+func (d *DataExport) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"account_id",
+			"status",
+			"bucket_name",
+			"object_name",
+			"error",
+			"created_at",
+			"completed_at",
+		}, []any{
+			&d.ID,
+			&d.AccountID,
+			&d.Status,
+			&d.BucketName,
+			&d.ObjectName,
+			&d.Error,
+			&d.CreatedAt,
+			&d.CompletedAt,
+		}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+const (
+	EmailVerificationTokenTTL = 24 * time.Hour
+	// a new verification email can be sent once per interval, and at most MaxEmailVerificationPerHour times an hour
+	EmailVerificationResendInterval = time.Minute
+	MaxEmailVerificationPerHour     = 5
+)
+
+// EmailVerificationToken proves the owner of the account receives the mails sent to its email.
+type EmailVerificationToken struct {
+	ID        string     `json:"id,omitempty"`
+	AccountID string     `json:"account_id,omitempty"`
+	TokenHash string     `json:"-"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+func (e *EmailVerificationToken) TableName() string {
+	return "email_verification_token"
+}
+
+func (e *EmailVerificationToken) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"account_id",
+			"token_hash",
+			"expired_at",
+			"used_at",
+			"created_at",
+		}, []any{
+			&e.ID,
+			&e.AccountID,
+			&e.TokenHash,
+			&e.ExpiredAt,
+			&e.UsedAt,
+			&e.CreatedAt,
+		}
+}
@@ -1,9 +1,113 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrNoRows = errors.New("no rows in result set")
 
 	ErrNotFoundMemberOfConversation = errors.New("user is not a member of conversation")
+	ErrPermissionDenied             = errors.New("permission denied")
+	ErrConversationNotUpdatable     = errors.New("conversation can not be updated")
+	ErrUploadedObjectNotFound       = errors.New("uploaded object not found")
+	ErrUploadBucketNotAllowed       = errors.New("bucket is not open to uploads")
+	ErrConversationAlreadyExists    = errors.New("conversation already exists")
+
+	ErrInviteLinkNotAllowed = errors.New("invite links are only available for groups")
+	ErrInviteLinkNotFound   = errors.New("invite link not found")
+	ErrInviteLinkInvalid    = errors.New("invite link is expired, revoked or used up")
+
+	ErrJoinRequestNotAllowed = errors.New("join requests are only available for groups and channels")
+	ErrJoinRequestNotFound   = errors.New("join request not found")
+	ErrJoinRequestNotPending = errors.New("join request has already been reviewed")
+
+	ErrPinnedConversationLimit    = errors.New("too many pinned conversations")
+	ErrConversationFolderNotFound = errors.New("conversation folder not found")
+	ErrConversationFolderLimit    = errors.New("too many conversation folders")
+
+	ErrChannelNotFound  = errors.New("channel not found")
+	ErrChannelNotPublic = errors.New("channel is not public")
+	ErrMessageNotFound  = errors.New("message not found")
+
+	ErrSendRateLimitNotAllowed = errors.New("slow mode and message limits are only available for groups")
+
+	ErrSyncCursorInvalid = errors.New("invalid sync cursor")
+
+	ErrContactNotFound        = errors.New("contact not found")
+	ErrContactRequestNotFound = errors.New("contact request not found")
+	ErrContactAlreadyExists   = errors.New("user is already a contact")
+	ErrContactSelf            = errors.New("cannot add yourself as a contact")
+	ErrUserNotFound           = errors.New("user not found")
+
+	ErrUserBlocked    = errors.New("user is blocked")
+	ErrUserBlockSelf  = errors.New("cannot block yourself")
+	ErrUserNotBlocked = errors.New("user is not blocked")
+
+	ErrHandleInvalid  = errors.New("handle must be 3 to 30 letters, digits or underscores and start with a letter")
+	ErrHandleReserved = errors.New("handle is reserved")
+	ErrHandleTaken    = errors.New("handle is already taken")
+
+	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid or expired")
+	ErrPasswordResetRateLimited  = errors.New("too many password reset requests, try again later")
+
+	ErrEmailNotVerified              = errors.New("email is not verified")
+	ErrEmailVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+	ErrEmailVerificationRateLimited  = errors.New("too many verification emails, try again later")
+
+	ErrAccountDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	ErrDataExportRateLimited       = errors.New("a data export was already requested recently")
+
+	ErrPresenceStatusInvalid  = errors.New("presence status must be ONLINE or AWAY")
+	ErrTooManyPresenceUserIDs = errors.New("too many users to get the presence of")
+
+	ErrBotNotFound          = errors.New("bot not found")
+	ErrBotLimitReached      = errors.New("too many bots")
+	ErrBotTokenNotFound     = errors.New("bot token not found")
+	ErrBotTokenLimitReached = errors.New("too many bot tokens")
+	ErrBotTokenInvalid      = errors.New("bot token is invalid or revoked")
+	ErrBotWebhookNotPublic  = errors.New("webhook_url must resolve to a public address")
+
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrAccountSuspended     = errors.New("account is suspended")
+	ErrAccountBanned        = errors.New("account is banned")
+	ErrAdminTargetAdmin     = errors.New("admin accounts can not be restricted")
+	ErrAccountNotRestricted = errors.New("account is not suspended or banned")
+	ErrConversationNotFound = errors.New("conversation not found")
+
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session is revoked")
+	ErrSessionInactive     = errors.New("session is expired or not active")
+)
+
+const (
+	PrivacyReasonDirectMessage = "DIRECT_MESSAGE_RESTRICTED"
+	PrivacyReasonGroupAdd      = "GROUP_ADD_RESTRICTED"
 )
+
+// PrivacyError is returned when the privacy settings of some users refuse the action.
+type PrivacyError struct {
+	Reason  string
+	UserIDs []string
+}
+
+func (e *PrivacyError) Error() string {
+	return fmt.Sprintf("refused by the privacy settings of the users (%s)", e.Reason)
+}
+
+const (
+	RateLimitReasonSlowMode   = "SLOW_MODE"
+	RateLimitReasonDailyLimit = "DAILY_LIMIT"
+)
+
+// RateLimitError is returned when a member sends messages faster than the conversation allows.
+type RateLimitError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many messages (%s), retry after %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AttachmentKindMedia = "MEDIA" // images and videos
+	AttachmentKindFile  = "FILE"  // files and voice messages
+	AttachmentKindLink  = "LINK"  // urls found in the text messages
+
+	MaxLinkPerMessage = 20
+)
+
+var linkRegexp = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// MessageAttachment indexes a media, a file or a link shared in a message,
+// so the gallery of a conversation does not scan the message bodies.
+type MessageAttachment struct {
+	ID             string     `json:"id,omitempty"`
+	MessageID      string     `json:"message_id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	UserID         string     `json:"user_id,omitempty"`
+	Kind           string     `json:"kind,omitempty"`
+	MessageType    string     `json:"message_type,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+func (m *MessageAttachment) TableName() string {
+	return "message_attachment"
+}
+
+func (m *MessageAttachment) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"message_id",
+			"conversation_id",
+			"user_id",
+			"kind",
+			"message_type",
+			"url",
+			"created_at",
+		}, []any{
+			&m.ID,
+			&m.MessageID,
+			&m.ConversationID,
+			&m.UserID,
+			&m.Kind,
+			&m.MessageType,
+			&m.URL,
+			&m.CreatedAt,
+		}
+}
+
+// MessageAttachmentFilter selects a page of the gallery of a conversation.
+type MessageAttachmentFilter struct {
+	ConversationID string
+	Kind           string
+	UserID         string     // sender, every sender when empty
+	Since          *time.Time // only the attachments created after since when it is set
+	LastID         string
+	Limit          int
+}
+
+// ExtractAttachments classifies the message for the gallery, the body of a media or file message is its url.
+func (m *Message) ExtractAttachments() []*MessageAttachment {
+	var kind string
+	var urls []string
+	switch m.Type {
+	case MessageTypeImage, MessageTypeVideo:
+		kind, urls = AttachmentKindMedia, []string{m.Body}
+	case MessageTypeFile, MessageTypeAudio:
+		kind, urls = AttachmentKindFile, []string{m.Body}
+	case MessageTypeText:
+		kind = AttachmentKindLink
+		for _, url := range linkRegexp.FindAllString(m.Body, -1) {
+			url = strings.TrimRight(url, ".,;:!?)")
+			if !slices.Contains(urls, url) {
+				urls = append(urls, url)
+			}
+		}
+		if len(urls) > MaxLinkPerMessage {
+			urls = urls[:MaxLinkPerMessage]
+		}
+	}
+
+	attachments := make([]*MessageAttachment, 0, len(urls))
+	for i, url := range urls {
+		attachments = append(attachments, &MessageAttachment{
+			ID:             fmt.Sprintf("%s:%02d", m.ID, i),
+			MessageID:      m.ID,
+			ConversationID: m.ConversationID,
+			UserID:         m.UserID,
+			Kind:           kind,
+			MessageType:    m.Type,
+			URL:            url,
+			CreatedAt:      m.CreatedAt,
+		})
+	}
+	return attachments
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type MessageReaction struct {
+	ID             string     `json:"id,omitempty"`
+	MessageID      string     `json:"message_id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	UserID         string     `json:"user_id,omitempty"`
+	Emoji          string     `json:"emoji,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+func (m *MessageReaction) TableName() string {
+	return "message_reaction"
+}
+
+func (m *MessageReaction) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"message_id",
+			"conversation_id",
+			"user_id",
+			"emoji",
+			"created_at",
+		}, []any{
+			&m.ID,
+			&m.MessageID,
+			&m.ConversationID,
+			&m.UserID,
+			&m.Emoji,
+			&m.CreatedAt,
+		}
+}
+
+// MessageReactionSummary counts the reactions of a message by emoji.
+type MessageReactionSummary struct {
+	MessageID   string `json:"message_id,omitempty"`
+	Emoji       string `json:"emoji,omitempty"`
+	Count       int    `json:"count,omitempty"`
+	ReactedByMe bool   `json:"reacted_by_me,omitempty"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 const (
 	MessageTypeText     = "text"
@@ -15,18 +18,23 @@ const (
 	MessageTypeSystem   = "system"
 )
 
+// MentionAll mentions every member of the conversation, a single user is mentioned with "@<user_id>".
+const MentionAll = "@all"
+
 type Message struct {
-	ID             string     `json:"id,omitempty"`
-	ConversationID string     `json:"conversation_id,omitempty"`
-	UserID         string     `json:"user_id,omitempty"`
-	Type           string     `json:"type,omitempty"`
-	Body           string     `json:"body,omitempty"`
-	CreatedAt      *time.Time `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
-	ReplyTo        string     `json:"reply_to,omitempty"`
-	User           *UserInfo  `json:"-"`
-	IgnoreSend     string     `json:"-"`
+	ID             string               `json:"id,omitempty"`
+	ConversationID string               `json:"conversation_id,omitempty"`
+	Seq            int64                `json:"seq,omitempty"` // position in the conversation, without gaps
+	UserID         string               `json:"user_id,omitempty"`
+	Type           string               `json:"type,omitempty"`
+	Body           string               `json:"body,omitempty"`
+	CreatedAt      *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time           `json:"updated_at,omitempty"`
+	DeletedAt      *time.Time           `json:"deleted_at,omitempty"`
+	ReplyTo        string               `json:"reply_to,omitempty"`
+	User           *UserInfo            `json:"-"`
+	IgnoreSend     string               `json:"-"`
+	Attachments    []*MessageAttachment `json:"-"` // stored with the message
 }
 
 func (m *Message) TableName() string {
@@ -37,6 +45,7 @@ func (m *Message) MapFields() ([]string, []any) {
 	return []string{
 			"id",
 			"conversation_id",
+			"seq",
 			"user_id",
 			"type",
 			"body",
@@ -47,6 +56,7 @@ func (m *Message) MapFields() ([]string, []any) {
 		}, []any{
 			&m.ID,
 			&m.ConversationID,
+			&m.Seq,
 			&m.UserID,
 			&m.Type,
 			&m.Body,
@@ -56,3 +66,20 @@ func (m *Message) MapFields() ([]string, []any) {
 			&m.ReplyTo,
 		}
 }
+
+// MessageFilter selects a page of the messages of a conversation by their seq.
+type MessageFilter struct {
+	ConversationID string
+	BeforeSeq      int64      // messages before this seq, newest first
+	AfterSeq       int64      // messages after this seq, oldest first, used to fill the gaps
+	Since          *time.Time // only the messages created after since when it is set
+	Limit          int
+}
+
+// Mentions reports whether the text of the message mentions the user.
+func (m *Message) Mentions(userID string) bool {
+	if m.Type != MessageTypeText {
+		return false
+	}
+	return strings.Contains(m.Body, MentionAll) || strings.Contains(m.Body, "@"+userID)
+}
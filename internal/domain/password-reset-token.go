@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	PasswordResetTokenTTL = 30 * time.Minute
+	// a new reset email can be sent to an account once per interval, and at most MaxPasswordResetPerHour times an hour
+	PasswordResetResendInterval = time.Minute
+	MaxPasswordResetPerHour     = 5
+	// MaxPasswordResetPerIPPerHour limits the reset requests of a client, whatever the emails it asks for
+	MaxPasswordResetPerIPPerHour = 20
+)
+
+// PasswordResetToken lets the owner of the account email set a new password once, before it expires.
+type PasswordResetToken struct {
+	ID        string     `json:"id,omitempty"`
+	AccountID string     `json:"account_id,omitempty"`
+	TokenHash string     `json:"-"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+func (p *PasswordResetToken) TableName() string {
+	return "password_reset_token"
+}
+
+func (p *PasswordResetToken) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"account_id",
+			"token_hash",
+			"expired_at",
+			"used_at",
+			"created_at",
+		}, []any{
+			&p.ID,
+			&p.AccountID,
+			&p.TokenHash,
+			&p.ExpiredAt,
+			&p.UsedAt,
+			&p.CreatedAt,
+		}
+}
+
+// HashToken returns the value stored for a secret token, so a leaked table can not be used to act on the accounts.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
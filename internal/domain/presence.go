@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	PresenceStatusOnline  = "ONLINE"
+	PresenceStatusAway    = "AWAY"
+	PresenceStatusOffline = "OFFLINE"
+)
+
+const (
+	// PresenceConnectionTTL is how long a connection counts without being refreshed,
+	// so the connections of a crashed instance go offline by themselves.
+	PresenceConnectionTTL = 90 * time.Second
+	// PresenceRefreshInterval is how often an instance refreshes its open connections.
+	PresenceRefreshInterval = 30 * time.Second
+	// PresenceOfflineGracePeriod delays the offline event, a reconnection in between sends nothing.
+	PresenceOfflineGracePeriod = 15 * time.Second
+	MaxPresenceUserIDs         = 100
+	// PresenceCheckInterval is how often an instance looks for the users whose presence may have changed,
+	// a user is claimed for PresenceCheckLease so that an instance stopping meanwhile does not lose the check.
+	PresenceCheckInterval = 5 * time.Second
+	PresenceCheckLease    = 30 * time.Second
+	MaxPresenceCheckUser  = 100
+)
+
+// Presence is the status of a user over all their connections: online when one of them is online,
+// away when all of them are away and offline without any.
+type Presence struct {
+	UserID     string     `json:"user_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
+
+func IsValidPresenceStatus(status string) bool {
+	return status == PresenceStatusOnline || status == PresenceStatusAway
+}
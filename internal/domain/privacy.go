@@ -0,0 +1,24 @@
+package domain
+
+// The privacy settings of a user tell who, besides the user, is allowed to see or do something.
+const (
+	PrivacyEveryone = "everyone"
+	PrivacyContacts = "contacts"
+	PrivacyNobody   = "nobody"
+)
+
+func IsValidPrivacy(privacy string) bool {
+	return privacy == PrivacyEveryone || privacy == PrivacyContacts || privacy == PrivacyNobody
+}
+
+// PrivacyAllows reports whether a user, a contact or not, passes the privacy setting.
+func PrivacyAllows(privacy string, isContact bool) bool {
+	switch privacy {
+	case PrivacyNobody:
+		return false
+	case PrivacyContacts:
+		return isContact
+	default:
+		return true
+	}
+}
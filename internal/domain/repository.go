@@ -11,6 +11,57 @@ type AccountRepository interface {
 	GetAccountByID(ctx context.Context, id string) (*Account, error)
 	UpdatePassword(ctx context.Context, id string, password string) error
 	CreateAccountUser(ctx context.Context, account *Account, user *UserInfo) error
+	// ScheduleAccountDeletion sets the end of the grace period, a nil time cancels the deletion.
+	ScheduleAccountDeletion(ctx context.Context, id string, deletionScheduledAt *time.Time) error
+	GetListAccountIDToDelete(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// AnonymizeAccount replaces the personal data of the account and its user, revokes its sessions and drops
+	// its contacts, blocks, tokens and data exports. The messages of the user are kept.
+	AnonymizeAccount(ctx context.Context, id string, now time.Time) error
+}
+
+// AdminRepository writes the audit log of an admin action in the same transaction as the action.
+type AdminRepository interface {
+	// GetListAccountWithUser returns a page of accounts ordered by id descending, the keyword filters on the email,
+	// the full name and the handle of their user. The deleted, suspended and banned accounts are included.
+	GetListAccountWithUser(ctx context.Context, keyword string, lastID string, limit int) ([]*AccountWithUser, error)
+	CreateAdminAuditLog(ctx context.Context, auditLog *AdminAuditLog) error
+	// GetListAdminAuditLog returns a page of the audit log from the newest, the target filters are optional.
+	GetListAdminAuditLog(ctx context.Context, targetType string, targetID string, lastID string, limit int) ([]*AdminAuditLog, error)
+	// UpdateAccountRestriction saves the suspension and the ban of the account and deactivates its sessions.
+	UpdateAccountRestriction(ctx context.Context, account *Account, auditLog *AdminAuditLog) error
+	// ResetPassword removes the password of the account and deactivates its sessions, the owner has to reset it.
+	ResetPassword(ctx context.Context, accountID string, auditLog *AdminAuditLog) error
+	// SoftDeleteConversation returns false when the conversation does not exist or is already deleted.
+	SoftDeleteConversation(ctx context.Context, conversationID string, now time.Time, auditLog *AdminAuditLog) (bool, error)
+}
+
+type DataExportRepository interface {
+	CreateDataExport(ctx context.Context, dataExport *DataExport) error
+	UpdateDataExport(ctx context.Context, dataExport *DataExport) error
+	GetListDataExportByAccountID(ctx context.Context, accountID string) ([]*DataExport, error)
+}
+
+type PasswordResetTokenRepository interface {
+	CreatePasswordResetToken(ctx context.Context, token *PasswordResetToken) error
+	CountPasswordResetTokenSince(ctx context.Context, accountID string, since time.Time) (int, error)
+	// ResetPassword marks the token and the other tokens of its account as used and sets the password in the same
+	// transaction, then returns the account id. It returns pgx.ErrNoRows when the token is unknown, expired or
+	// already used.
+	ResetPassword(ctx context.Context, tokenHash string, password string, now time.Time) (string, error)
+}
+
+// RequestRateLimitRepository counts the requests of the clients on the public endpoints, shared by every app instance.
+type RequestRateLimitRepository interface {
+	// AllowRequest counts a request under the key, it returns false once limit requests were counted within the window.
+	AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+type EmailVerificationTokenRepository interface {
+	CreateEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error
+	CountEmailVerificationTokenSince(ctx context.Context, accountID string, since time.Time) (int, error)
+	// UseEmailVerificationToken marks the tokens of the account as used and its email as verified, then returns the
+	// account id. It returns pgx.ErrNoRows when the token is unknown, expired or already used.
+	UseEmailVerificationToken(ctx context.Context, tokenHash string, now time.Time) (string, error)
 }
 
 type UserRepository interface {
@@ -18,7 +69,18 @@ type UserRepository interface {
 	GetUserByID(ctx context.Context, id string) (*UserInfo, error)
 	GetUserByEmail(ctx context.Context, email string) (*UserInfo, error)
 	GetUserByAccountID(ctx context.Context, accountID string) (*UserInfo, error)
+	// UpdateUser returns ErrHandleTaken when another user has the handle.
 	UpdateUser(ctx context.Context, user *UserInfo) error
+	GetUserByHandle(ctx context.Context, handle string) (*UserInfo, error)
+	GetListUserByIDs(ctx context.Context, ids []string) ([]*UserInfo, error)
+	UpdatePrivacySetting(ctx context.Context, user *UserInfo) error
+	UpdateUserStatus(ctx context.Context, user *UserInfo) error
+	// ClearExpiredUserStatus clears the statuses expired at now and returns the ids of their users.
+	ClearExpiredUserStatus(ctx context.Context, now time.Time) ([]string, error)
+	// GetListRelatedUserID returns the contacts of the user and the members of the DMs and groups they share.
+	GetListRelatedUserID(ctx context.Context, userID string) ([]string, error)
+	// GetListContactAndDMUserID returns the contacts of the user and the users they have a DM with.
+	GetListContactAndDMUserID(ctx context.Context, userID string) ([]string, error)
 	GetListUser(ctx context.Context, keyword string, limit int, lastID string) ([]*UserInfo, error)
 	GetListUserWithConversation(ctx context.Context, userID string, keyword string, limit int, lastID string) ([]*UserInfo, error)
 }
@@ -30,6 +92,11 @@ type SessionRepository interface {
 	DeactivateSession(ctx context.Context, token string) error
 	DeactiveAllSessionByAccountID(ctx context.Context, accountID string) error
 	UpdateExpiredAt(ctx context.Context, token string, newExpiredAt *time.Time) error
+	CreateRefreshToken(ctx context.Context, refreshToken *RefreshToken) error
+	// RotateRefreshToken marks the refresh token used, stores newRefreshToken for its session and returns the session.
+	// It returns pgx.ErrNoRows for an unknown token, and ErrRefreshTokenReused with the session it deactivated
+	// for a token used already. ErrRefreshTokenInvalid is returned, and nothing rotated, when the session is revoked or expired.
+	RotateRefreshToken(ctx context.Context, tokenHash string, newRefreshToken *RefreshToken, now time.Time) (*Session, error)
 }
 
 type SessionCacheRepository interface {
@@ -42,28 +109,208 @@ type UserOnlineRepository interface {
 	CreateUserOnline(ctx context.Context, userOnline *UserOnline) error
 	DeleteUserOnline(ctx context.Context, id string) error
 	GetUserOnlineByConversationID(ctx context.Context, conversationID string) ([]*UserOnline, error)
+	GetUserOnlineByUserIDs(ctx context.Context, userIDs []string) ([]*UserOnline, error)
 }
 
 type ConversationRepository interface {
 	CreateConversation(ctx context.Context, conversation *Conversation, conversationMembers []*ConversationMember) (*Conversation, error)
-	GetListConversationByUserID(ctx context.Context, userID string, lastMessageID string, limit int) ([]*Conversation, error)
-	GetConversationByID(ctx context.Context, id string) (*Conversation, []*ConversationMemberWithUser, error)
+	GetListConversationByUserID(ctx context.Context, userID string, filter *ConversationFilter, lastMessageID string, limit int) ([]*Conversation, error)
+	GetConversationByID(ctx context.Context, id string) (*Conversation, error)
+	// GetListJoinedConversation returns every conversation the user is a member of, archived ones included.
+	GetListJoinedConversation(ctx context.Context, userID string) ([]*Conversation, error)
+	// GetListConversationMemberWithUser returns a page of members ordered by user id,
+	// the keyword filters on the full name when it is set.
+	GetListConversationMemberWithUser(ctx context.Context, conversationID string, keyword string, lastUserID string, limit int) ([]*ConversationMemberWithUser, error)
 	UpdateLastMessageID(ctx context.Context, conversationID string, lastMessageID string) error
 	CheckIsMemberOfConversation(ctx context.Context, userID string, conversationID string) (bool, error)
+	GetConversationMember(ctx context.Context, conversationID string, userID string) (*ConversationMember, error)
+	UpdateConversation(ctx context.Context, conversation *Conversation) error
+	CountConversationMember(ctx context.Context, conversationID string) (int, error)
+	GetConversationByDMKey(ctx context.Context, dmKey string) (*Conversation, error)
+	// MergeDuplicateDMConversations moves the history of duplicated DMs into the oldest one
+	// and returns the number of removed duplicates.
+	MergeDuplicateDMConversations(ctx context.Context) (int, error)
+	UpdateNotificationSetting(ctx context.Context, conversationMember *ConversationMember) error
+	GetListConversationMemberByUserIDs(ctx context.Context, conversationID string, userIDs []string) ([]*ConversationMember, error)
+	// CountUnreadMessageByUserID returns the number of unread messages and of conversations having them,
+	// muted conversations are not counted.
+	CountUnreadMessageByUserID(ctx context.Context, userID string, now time.Time) (int, int, error)
+	UpdateArchivedAt(ctx context.Context, conversationID string, userID string, archivedAt *time.Time) error
+	// UnarchiveConversation brings the conversation back to the main list of the members who archived it,
+	// except for those who muted it.
+	UnarchiveConversation(ctx context.Context, conversationID string, now time.Time) error
+	ClearHistory(ctx context.Context, conversationID string, userID string, clearedAt time.Time) error
+	UpdatePinnedAt(ctx context.Context, conversationID string, userID string, pinnedAt *time.Time) error
+	CountPinnedConversation(ctx context.Context, userID string) (int, error)
+	// SearchPublicChannel returns the public channels matching the keyword with their member count.
+	SearchPublicChannel(ctx context.Context, keyword string, lastConversationID string, limit int) ([]*Conversation, error)
+	AddConversationMember(ctx context.Context, conversationMember *ConversationMember) error
+	DeleteConversationMember(ctx context.Context, conversationID string, userID string) error
+	GetListChannelIDByUserID(ctx context.Context, userID string) ([]string, error)
+	GetListConversationAdminIDs(ctx context.Context, conversationID string) ([]string, error)
+}
+
+type ConversationJoinRequestRepository interface {
+	// CreateJoinRequest returns the pending request of the user instead when there is already one.
+	CreateJoinRequest(ctx context.Context, joinRequest *ConversationJoinRequest) (*ConversationJoinRequest, error)
+	GetJoinRequestByID(ctx context.Context, id string) (*ConversationJoinRequest, error)
+	GetPendingJoinRequest(ctx context.Context, conversationID string, userID string) (*ConversationJoinRequest, error)
+	GetListPendingJoinRequestWithUser(ctx context.Context, conversationID string) ([]*ConversationJoinRequestWithUser, error)
+	// ApproveJoinRequest marks the request approved and adds the member in the same transaction,
+	// the invite link the request came from is counted as used. The member is nil when the user already joined.
+	ApproveJoinRequest(ctx context.Context, joinRequest *ConversationJoinRequest, conversationMember *ConversationMember) error
+	RejectJoinRequest(ctx context.Context, joinRequest *ConversationJoinRequest) error
+}
+
+type ContactRepository interface {
+	CreateContactRequest(ctx context.Context, contact *Contact) error
+	GetContactByID(ctx context.Context, id string) (*Contact, error)
+	// GetListContactBetween returns the rows of both directions between the two users.
+	GetListContactBetween(ctx context.Context, userID string, otherUserID string) ([]*Contact, error)
+	// AcceptContactRequest marks the request accepted and adds the contact in the other direction.
+	AcceptContactRequest(ctx context.Context, contact *Contact, reverseContact *Contact) error
+	DeleteContactRequest(ctx context.Context, id string) error
+	// DeleteContact removes both directions of the contact, it returns false when they were not contacts.
+	DeleteContact(ctx context.Context, userID string, friendID string) (bool, error)
+	GetListContactWithUser(ctx context.Context, userID string, keyword string, lastFriendID string, limit int) ([]*ContactWithUser, error)
+	// GetListContactRequestWithUser returns the pending requests received by the user, or sent when outgoing is set.
+	GetListContactRequestWithUser(ctx context.Context, userID string, outgoing bool) ([]*ContactWithUser, error)
+	// GetListFriendID returns the contacts of the user among friendIDs.
+	GetListFriendID(ctx context.Context, userID string, friendIDs []string) ([]string, error)
+}
+
+type UserBlockRepository interface {
+	// CreateUserBlock blocks the user and drops the contact and the friend requests between both users.
+	CreateUserBlock(ctx context.Context, userBlock *UserBlock) error
+	// DeleteUserBlock returns false when the user was not blocked.
+	DeleteUserBlock(ctx context.Context, userID string, blockedUserID string) (bool, error)
+	GetListUserBlockWithUser(ctx context.Context, userID string) ([]*UserBlockWithUser, error)
+	GetListBlockedUserID(ctx context.Context, userID string) ([]string, error)
+	// GetListUserIDBlocking returns the users among userIDs who blocked the blocked user.
+	GetListUserIDBlocking(ctx context.Context, blockedUserID string, userIDs []string) ([]string, error)
+	// IsBlockedBetween reports whether one of the users blocked the other.
+	IsBlockedBetween(ctx context.Context, userID string, otherUserID string) (bool, error)
+	// IsBlockedInConversation reports whether the user and another member of the conversation blocked each other.
+	IsBlockedInConversation(ctx context.Context, conversationID string, userID string) (bool, error)
+}
+
+type MessageReactionRepository interface {
+	AddReaction(ctx context.Context, reaction *MessageReaction) error
+	RemoveReaction(ctx context.Context, messageID string, userID string, emoji string) error
+	GetListReactionSummaryByMessageIDs(ctx context.Context, messageIDs []string, userID string) ([]*MessageReactionSummary, error)
+}
+
+// MessageRateLimitRepository keeps the send counters shared by every app instance.
+type MessageRateLimitRepository interface {
+	// AllowSendMessage counts a message of the user when the slow mode and the daily limit allow it,
+	// otherwise it returns a *RateLimitError and nothing is counted.
+	AllowSendMessage(ctx context.Context, conversationID string, userID string, slowMode time.Duration, dailyLimit int, now time.Time) error
+}
+
+// MessageViewRepository counts the distinct users who viewed the messages.
+type MessageViewRepository interface {
+	AddMessageViews(ctx context.Context, userID string, messageIDs []string) error
+	CountMessageViews(ctx context.Context, messageIDs []string) (map[string]int64, error)
+}
+
+type ConversationFolderRepository interface {
+	CreateFolder(ctx context.Context, folder *ConversationFolder) error
+	// UpdateFolder replaces the name, rules and conversations of the folder.
+	UpdateFolder(ctx context.Context, folder *ConversationFolder) error
+	DeleteFolder(ctx context.Context, id string) error
+	GetFolderByID(ctx context.Context, id string) (*ConversationFolder, error)
+	GetListFolderByUserID(ctx context.Context, userID string) ([]*ConversationFolder, error)
+	CountFolderByUserID(ctx context.Context, userID string) (int, error)
+}
+
+type ConversationInviteLinkRepository interface {
+	CreateInviteLink(ctx context.Context, inviteLink *ConversationInviteLink) error
+	GetInviteLinkByID(ctx context.Context, id string) (*ConversationInviteLink, error)
+	GetInviteLinkByToken(ctx context.Context, token string) (*ConversationInviteLink, error)
+	GetListInviteLinkByConversationID(ctx context.Context, conversationID string) ([]*ConversationInviteLink, error)
+	RevokeInviteLink(ctx context.Context, id string) error
+	// JoinByInviteLink consumes one use of the link and adds the member in the same transaction,
+	// it returns false when the user is already a member.
+	JoinByInviteLink(ctx context.Context, inviteLinkID string, conversationMember *ConversationMember) (bool, error)
 }
 
 type MessageRepository interface {
 	CreateMessage(ctx context.Context, message *Message) (*Message, error)
-	GetListMessageByConversationID(ctx context.Context, conversationID string, lastID string, limit int) ([]*Message, error)
+	// GetListMessageByConversationID returns a page of the messages, newest first, or oldest first when AfterSeq is set.
+	GetListMessageByConversationID(ctx context.Context, filter *MessageFilter) ([]*Message, error)
 	GetMessageByID(ctx context.Context, id string) (*Message, error)
+	// GetListMessageByUserID returns a page of the messages written by the user, ordered by id.
+	GetListMessageByUserID(ctx context.Context, userID string, lastID string, limit int) ([]*Message, error)
+	GetListMessageAttachment(ctx context.Context, filter *MessageAttachmentFilter) ([]*MessageAttachment, error)
+}
+
+type SyncEventRepository interface {
+	CreateSyncEvent(ctx context.Context, syncEvent *SyncEvent) error
+	// GetListSyncEventByUserID returns the committed events after the cursor that the user can see, in order. The
+	// events of a conversation are visible to its current members from the time they joined.
+	GetListSyncEventByUserID(ctx context.Context, userID string, after SyncCursor, limit int) ([]*SyncEvent, error)
+	// GetSyncEventBounds returns the position before which every event is committed, and the position of the
+	// latest purged event, zero when none was purged.
+	GetSyncEventBounds(ctx context.Context) (SyncCursor, SyncCursor, error)
+	// DeleteSyncEventBefore purges the events created before the time and records the latest position purged.
+	DeleteSyncEventBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type BotRepository interface {
+	// CreateBot creates the account and the INTERNAL user of the bot with it.
+	CreateBot(ctx context.Context, account *Account, user *UserInfo, bot *Bot) error
+	GetBotByID(ctx context.Context, id string) (*Bot, error)
+	GetListBotByOwnerID(ctx context.Context, ownerID string) ([]*Bot, error)
+	CountBotByOwnerID(ctx context.Context, ownerID string) (int, error)
+	UpdateBot(ctx context.Context, bot *Bot) error
+	// DeleteBot marks the bot and its user deleted and revokes its tokens.
+	DeleteBot(ctx context.Context, bot *Bot, now time.Time) error
+	CreateBotToken(ctx context.Context, botToken *BotToken) error
+	GetListBotTokenByBotID(ctx context.Context, botID string) ([]*BotToken, error)
+	CountActiveBotToken(ctx context.Context, botID string) (int, error)
+	// RevokeBotToken returns false when the token does not exist or was revoked already.
+	RevokeBotToken(ctx context.Context, botID string, tokenID string, now time.Time) (bool, error)
+	// GetAccountIDByBotToken returns the account of the bot of an active token and records its use,
+	// it returns pgx.ErrNoRows otherwise or when the account is suspended or banned.
+	GetAccountIDByBotToken(ctx context.Context, tokenHash string, now time.Time) (string, error)
+	// GetListWebhookBotByUserIDs returns the bots with a webhook among the users.
+	GetListWebhookBotByUserIDs(ctx context.Context, userIDs []string) ([]*Bot, error)
+	// GetListWebhookBotByConversationID returns the bots with a webhook among the members of the conversation.
+	GetListWebhookBotByConversationID(ctx context.Context, conversationID string) ([]*Bot, error)
 }
 
 type UserCacheRepository interface {
 	GetUserIDByAccountID(ctx context.Context, accountID string) (string, error)
 	SetUserIDByAccountID(ctx context.Context, accountID string, userID string) error
+	DeleteUserIDByAccountID(ctx context.Context, accountID string) error
+}
+
+// PresenceRepository keeps the connections of the users shared by every app instance.
+type PresenceRepository interface {
+	// SetConnectionStatus sets the status of the connection and keeps it until expiredAt, the presence of the user
+	// is checked again by then.
+	SetConnectionStatus(ctx context.Context, userID string, connectionID string, status string, expiredAt time.Time) error
+	// RefreshConnection keeps the connection with its status until expiredAt, it does nothing when the connection is gone.
+	// Like SetConnectionStatus, it pushes the check of the presence back to expiredAt.
+	RefreshConnection(ctx context.Context, userID string, connectionID string, expiredAt time.Time) error
+	// ScheduleCheck checks the presence of the user at the time, or earlier when an earlier check is scheduled already.
+	ScheduleCheck(ctx context.Context, userID string, at time.Time) error
+	// ClaimDueCheck returns the users whose check is due and pushes their check back to leaseUntil,
+	// so that it runs again when the claimer stops before completing it.
+	ClaimDueCheck(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]string, error)
+	// CompleteCheck removes the check of the user claimed until leaseUntil, unless it was scheduled again meanwhile.
+	CompleteCheck(ctx context.Context, userID string, leaseUntil time.Time) error
+	DeleteConnection(ctx context.Context, userID string, connectionID string) error
+	SetLastSeenAt(ctx context.Context, userID string, lastSeenAt time.Time) error
+	// GetListPresence returns the presence of every user, in the order of userIDs.
+	GetListPresence(ctx context.Context, userIDs []string, now time.Time) ([]*Presence, error)
+	// SwapPublishedStatus stores the status last sent to the other users and returns the previous one.
+	SwapPublishedStatus(ctx context.Context, userID string, status string) (string, error)
 }
 
 type SeenMessageRepository interface {
+	// CreateSeenMessage moves the seen pointer of the user forward and fills its seq,
+	// it returns pgx.ErrNoRows when the message is not after the current pointer.
 	CreateSeenMessage(ctx context.Context, seenMessage *SeenMessage) error
 	GetListSeenMessageByConversationID(ctx context.Context, conversationID string) ([]*SeenMessage, error)
 }
@@ -5,6 +5,7 @@ import "time"
 type SeenMessage struct {
 	ID             string     `json:"id,omitempty"`
 	MessageID      string     `json:"message_id,omitempty"`
+	MessageSeq     int64      `json:"message_seq,omitempty"`
 	UserID         string     `json:"user_id,omitempty"`
 	ConversationID string     `json:"conversation_id,omitempty"`
 	CreatedAt      *time.Time `json:"created_at,omitempty"`
@@ -19,6 +20,7 @@ func (s *SeenMessage) MapFields() ([]string, []any) {
 	return []string{
 			"id",
 			"message_id",
+			"message_seq",
 			"user_id",
 			"conversation_id",
 			"created_at",
@@ -26,6 +28,7 @@ func (s *SeenMessage) MapFields() ([]string, []any) {
 		}, []any{
 			&s.ID,
 			&s.MessageID,
+			&s.MessageSeq,
 			&s.UserID,
 			&s.ConversationID,
 			&s.CreatedAt,
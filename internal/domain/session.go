@@ -5,6 +5,13 @@ import (
 	"time"
 )
 
+const (
+	// SessionTTL is how long the session of a login can be refreshed
+	SessionTTL = 180 * 24 * time.Hour
+	// DefaultAccessTokenTTL is the lifetime of the access tokens when the jwt expiration is not configured
+	DefaultAccessTokenTTL = 15 * time.Minute
+)
+
 type Session struct {
 	SessionToken string     `json:"session_token,omitempty"` // UUID, generated by the server, primary key
 	AccountID    string     `json:"account_id,omitempty"`
@@ -82,3 +89,33 @@ func (s *Session) FromMap(m map[string]string) {
 		}
 	}
 }
+
+// RefreshToken is exchanged once for a new access token, the session rotates it on each refresh.
+// Only the hash of the token is stored.
+type RefreshToken struct {
+	ID           string     `json:"id,omitempty"`
+	SessionToken string     `json:"-"`
+	TokenHash    string     `json:"-"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
+
+func (r *RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+func (r *RefreshToken) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"session_token",
+			"token_hash",
+			"created_at",
+			"used_at",
+		}, []any{
+			&r.ID,
+			&r.SessionToken,
+			&r.TokenHash,
+			&r.CreatedAt,
+			&r.UsedAt,
+		}
+}
@@ -4,6 +4,7 @@ const (
 	//stream name
 	STREAM_NAME_WS_MESSAGE   = "WS_MESSAGE"
 	STREAM_NAME_CONVERSATION = "CONVERSATION"
+	STREAM_NAME_ACCOUNT      = "ACCOUNT"
 
 	//subject for conversation
 	SUBJECT_WILDCARD_CONVERSATION  = "conversation.*"
@@ -18,10 +19,20 @@ const (
 	CONSUMER_NAME_WS_MESSAGE_NEW                 = "ws_message_new_consumer"
 	CONSUMER_NAME_WS_MESSAGE_UPDATE_LAST_MESSAGE = "ws_message_update_last_message_consumer"
 	CONSUMER_NAME_SEEN_MESSAGE                   = "seen_message_consumer"
+	CONSUMER_NAME_SYNC_EVENT                     = "sync_event_consumer"
+	CONSUMER_NAME_BOT_WEBHOOK                    = "bot_webhook_consumer"
 	//queue name
 	QUEUE_NAME_WS_MESSAGE_UPDATE_LAST_MESSAGE = "ws_message_update_last_message_queue"
 	QUEUE_NAME_SEEN_MESSAGE                   = "seen_message_queue"
+	QUEUE_NAME_SYNC_EVENT                     = "sync_event_queue"
+	QUEUE_NAME_BOT_WEBHOOK                    = "bot_webhook_queue"
 
 	//subject for seen message
 	SUBJECT_SEEN_MESSAGE = "conversation.seen_message"
+
+	//subject for account
+	SUBJECT_WILDCARD_ACCOUNT  = "account.*"
+	SUBJECT_DATA_EXPORT       = "account.data_export"
+	CONSUMER_NAME_DATA_EXPORT = "data_export_consumer"
+	QUEUE_NAME_DATA_EXPORT    = "data_export_queue"
 )
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxSyncEvent is the largest delta returned by a sync, the client does a full resync above it.
+	MaxSyncEvent = 500
+	// SyncEventRetention is how long the events are kept for the sync.
+	SyncEventRetention = 30 * 24 * time.Hour
+)
+
+// syncedEventTypes are the realtime events replayed by the sync, the others are only useful live.
+var syncedEventTypes = []WebSocketMessageType{
+	WsMessage,
+	WsSeenMessage,
+	WsConversationUpdated,
+	WsMemberJoined,
+	WsReactionUpdated,
+	WsConversationPinUpdated,
+	WsFolderUpdated,
+	WsFolderDeleted,
+	WsChannelSubscribed,
+	WsChannelUnsubscribed,
+	WsJoinRequestCreated,
+	WsJoinRequestReviewed,
+	WsContactRequestCreated,
+	WsContactRequestAccepted,
+	WsContactRequestDeclined,
+	WsContactRequestCancelled,
+	WsContactRemoved,
+	WsUserBlocked,
+	WsUserUnblocked,
+	WsUserProfileUpdated,
+	WsUserStatusUpdated,
+	WsDataExportUpdated,
+	WsConversationDeleted,
+}
+
+type SyncEvent struct {
+	ID             int64          `json:"id,omitempty"`
+	XactID         int64          `json:"xact_id,omitempty"`
+	ConversationID *string        `json:"conversation_id,omitempty"`
+	UserIDs        []string       `json:"user_ids,omitempty"`
+	Type           string         `json:"type,omitempty"`
+	Payload        map[string]any `json:"payload,omitempty"`
+	CreatedAt      *time.Time     `json:"created_at,omitempty"`
+}
+
+func (s *SyncEvent) TableName() string {
+	return "sync_event"
+}
+
+func (s *SyncEvent) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"xact_id",
+			"conversation_id",
+			"user_ids",
+			"type",
+			"payload",
+			"created_at",
+		}, []any{
+			&s.ID,
+			&s.XactID,
+			&s.ConversationID,
+			&s.UserIDs,
+			&s.Type,
+			&s.Payload,
+			&s.CreatedAt,
+		}
+}
+
+// Cursor returns the position of the event in the log.
+func (s *SyncEvent) Cursor() SyncCursor {
+	return SyncCursor{XactID: s.XactID, EventID: s.ID}
+}
+
+// SyncCursor is a position in the event log. The events are read in the order of the transaction that
+// inserted them then of their id, and only once every transaction before them is over, so that an event
+// committed late never lands behind a cursor already handed out.
+type SyncCursor struct {
+	XactID  int64
+	EventID int64
+}
+
+// Before tells whether the position is earlier in the log than the other one.
+func (c SyncCursor) Before(other SyncCursor) bool {
+	return c.XactID < other.XactID || (c.XactID == other.XactID && c.EventID < other.EventID)
+}
+
+func (c SyncCursor) String() string {
+	return strconv.FormatInt(c.XactID, 10) + "-" + strconv.FormatInt(c.EventID, 10)
+}
+
+// ParseSyncCursor parses the cursor returned by SyncCursor.String.
+func ParseSyncCursor(cursor string) (SyncCursor, error) {
+	xactID, eventID, ok := strings.Cut(cursor, "-")
+	if !ok {
+		return SyncCursor{}, errors.New("sync cursor must be <xact_id>-<event_id>")
+	}
+	var c SyncCursor
+	var err error
+	c.XactID, err = strconv.ParseInt(xactID, 10, 64)
+	if err != nil || c.XactID < 0 {
+		return SyncCursor{}, errors.New("invalid sync cursor transaction id")
+	}
+	c.EventID, err = strconv.ParseInt(eventID, 10, 64)
+	if err != nil || c.EventID < 0 {
+		return SyncCursor{}, errors.New("invalid sync cursor event id")
+	}
+	return c, nil
+}
+
+// NewSyncEvent returns the event to log for the websocket message, or nil when it is not synced.
+// Like the websocket fanout, an event goes to the listed users or else to the members of its conversation.
+func NewSyncEvent(message *WebSocketMessage, now time.Time) *SyncEvent {
+	if !slices.Contains(syncedEventTypes, message.Type) {
+		return nil
+	}
+	syncEvent := &SyncEvent{
+		Type:      string(message.Type),
+		Payload:   message.Payload,
+		CreatedAt: &now,
+	}
+	if len(message.UserIDs) > 0 {
+		syncEvent.UserIDs = message.UserIDs
+		return syncEvent
+	}
+	conversationID, ok := message.Payload["conversation_id"].(string)
+	if !ok || conversationID == "" {
+		return nil
+	}
+	syncEvent.ConversationID = &conversationID
+	return syncEvent
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "encoding/json"
+
+const (
+	SystemActionConversationUpdated = "CONVERSATION_UPDATED"
+	SystemActionMemberJoined        = "MEMBER_JOINED"
+)
+
+// SystemMessage is the body of a message with type MessageTypeSystem.
+// It is stored as JSON so clients can render it in their own language.
+type SystemMessage struct {
+	Action  string         `json:"action,omitempty"`
+	ActorID string         `json:"actor_id,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+func (s *SystemMessage) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "slices"
+
+// UploadBucketNames are the buckets the clients upload to, the other buckets,
+// like the one of the data exports, are only written by the server.
+var UploadBucketNames = []string{"avatar"}
+
+func IsUploadBucket(bucketName string) bool {
+	return slices.Contains(UploadBucketNames, bucketName)
+}
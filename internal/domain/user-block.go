@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// UserBlock is a user blocking another one: the blocked user cannot find the user, start a DM or
+// write in their DM anymore, and the messages of the blocked user are flagged in the groups.
+type UserBlock struct {
+	ID            string     `json:"id,omitempty"`
+	UserID        string     `json:"user_id,omitempty"`
+	BlockedUserID string     `json:"blocked_user_id,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
+
+func (u *UserBlock) TableName() string {
+	return "user_block"
+}
+
+func (u *UserBlock) MapFields() ([]string, []any) {
+	return []string{
+			"id",
+			"user_id",
+			"blocked_user_id",
+			"created_at",
+		}, []any{
+			&u.ID,
+			&u.UserID,
+			&u.BlockedUserID,
+			&u.CreatedAt,
+		}
+}
+
+type UserBlockWithUser struct {
+	UserBlock
+	User *UserInfo
+}
@@ -1,25 +1,65 @@
 package domain
 
 import (
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
 const (
 	ExternalUserType = "EXTERNAL"
 	InternalUserType = "INTERNAL"
+
+	MaxBioLength = 500
+
+	MaxStatusEmojiLength = 16
+	MaxStatusTextLength  = 100
 )
 
+var regexHandle = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// reservedHandles can not be picked by users, they would be mistaken for the app or its staff.
+var reservedHandles = []string{
+	"admin",
+	"administrator",
+	"api",
+	"bot",
+	"chat",
+	"help",
+	"me",
+	"moderator",
+	"null",
+	"official",
+	"root",
+	"security",
+	"socio",
+	"staff",
+	"support",
+	"system",
+}
+
 type UserInfo struct {
-	ID             string     `json:"id,omitempty"`
-	AccountID      string     `json:"account_id,omitempty"`
-	Type           string     `json:"type,omitempty"`
-	Email          string     `json:"email,omitempty"`
-	FullName       string     `json:"full_name,omitempty"`
-	Avatar         string     `json:"avatar,omitempty"`
-	CreatedAt      *time.Time `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
-	ConversationID *string    `json:"-"` // for query conversation with another user
+	ID                   string     `json:"id,omitempty"`
+	AccountID            string     `json:"account_id,omitempty"`
+	Type                 string     `json:"type,omitempty"`
+	Email                string     `json:"email,omitempty"`
+	FullName             string     `json:"full_name,omitempty"`
+	Avatar               string     `json:"avatar,omitempty"`
+	Handle               *string    `json:"handle,omitempty"`
+	Bio                  string     `json:"bio,omitempty"`
+	LastSeenPrivacy      string     `json:"last_seen_privacy,omitempty"`
+	AvatarPrivacy        string     `json:"avatar_privacy,omitempty"`
+	DirectMessagePrivacy string     `json:"direct_message_privacy,omitempty"`
+	GroupAddPrivacy      string     `json:"group_add_privacy,omitempty"`
+	StatusEmoji          string     `json:"status_emoji,omitempty"`
+	StatusText           string     `json:"status_text,omitempty"`
+	StatusExpiresAt      *time.Time `json:"status_expires_at,omitempty"`
+	CreatedAt            *time.Time `json:"created_at,omitempty"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"`
+	ConversationID       *string    `json:"-"` // for query conversation with another user
+	ContactState         string     `json:"-"` // contact state with the user searching
 }
 
 func (u *UserInfo) TableName() string {
@@ -34,6 +74,15 @@ func (u *UserInfo) MapFields() ([]string, []any) {
 			"email",
 			"full_name",
 			"avatar",
+			"handle",
+			"bio",
+			"last_seen_privacy",
+			"avatar_privacy",
+			"direct_message_privacy",
+			"group_add_privacy",
+			"status_emoji",
+			"status_text",
+			"status_expires_at",
 			"created_at",
 			"updated_at",
 		}, []any{
@@ -43,7 +92,33 @@ func (u *UserInfo) MapFields() ([]string, []any) {
 			&u.Email,
 			&u.FullName,
 			&u.Avatar,
+			&u.Handle,
+			&u.Bio,
+			&u.LastSeenPrivacy,
+			&u.AvatarPrivacy,
+			&u.DirectMessagePrivacy,
+			&u.GroupAddPrivacy,
+			&u.StatusEmoji,
+			&u.StatusText,
+			&u.StatusExpiresAt,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		}
 }
+
+// NormalizeHandle lowercases the handle and drops the leading @.
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// ValidateHandle checks a normalized handle: 3 to 30 letters, digits or underscores starting with a letter,
+// and not a reserved word.
+func ValidateHandle(handle string) error {
+	if !regexHandle.MatchString(handle) {
+		return ErrHandleInvalid
+	}
+	if slices.Contains(reservedHandles, handle) {
+		return ErrHandleReserved
+	}
+	return nil
+}
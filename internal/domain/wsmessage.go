@@ -11,6 +11,44 @@ const (
 	WsPong              = "PONG"
 	WsUpdateLastMessage = "UPDATE_LAST_MESSAGE"
 	WsSeenMessage       = "SEEN_MESSAGE"
+
+	WsConversationUpdated = "CONVERSATION_UPDATED"
+	WsMemberJoined        = "MEMBER_JOINED"
+	WsReactionUpdated     = "REACTION_UPDATED"
+	// sent to the admins of the conversation, and to the requester once reviewed
+	WsJoinRequestCreated  = "JOIN_REQUEST_CREATED"
+	WsJoinRequestReviewed = "JOIN_REQUEST_REVIEWED"
+
+	// events syncing the state of a user between their devices
+	WsConversationPinUpdated = "CONVERSATION_PIN_UPDATED"
+	WsFolderUpdated          = "FOLDER_UPDATED"
+	WsFolderDeleted          = "FOLDER_DELETED"
+	// subscribe or unsubscribe the connections of the user to the posts of a channel
+	WsChannelSubscribed   = "CHANNEL_SUBSCRIBED"
+	WsChannelUnsubscribed = "CHANNEL_UNSUBSCRIBED"
+
+	// sent to both users of a friend request or a contact
+	WsContactRequestCreated   = "CONTACT_REQUEST_CREATED"
+	WsContactRequestAccepted  = "CONTACT_REQUEST_ACCEPTED"
+	WsContactRequestDeclined  = "CONTACT_REQUEST_DECLINED"
+	WsContactRequestCancelled = "CONTACT_REQUEST_CANCELLED"
+	WsContactRemoved          = "CONTACT_REMOVED"
+	// sent to the devices of the user blocking
+	WsUserBlocked   = "USER_BLOCKED"
+	WsUserUnblocked = "USER_UNBLOCKED"
+	// sent to the contacts of the user and the members of the DMs and groups they share
+	WsUserProfileUpdated = "USER_PROFILE_UPDATED"
+	WsUserStatusUpdated  = "USER_STATUS_UPDATED"
+	// sent to the devices of the user once their archive is ready or failed
+	WsDataExportUpdated = "DATA_EXPORT_UPDATED"
+	// sent by a connection going away or back online, and to the contacts and DM partners once the presence changed
+	WsPresenceUpdate  = "PRESENCE_UPDATE"
+	WsPresenceChanged = "PRESENCE_CHANGED"
+	// sent to the devices of the user before their connections are closed, once an admin restricted the account
+	// or reset its password
+	WsSessionRevoked = "SESSION_REVOKED"
+	// sent to the members of a conversation deleted by an admin
+	WsConversationDeleted = "CONVERSATION_DELETED"
 )
 
 // WebSocketMessage represents a message sent over a WebSocket connection.
@@ -23,6 +61,7 @@ type WebSocketMessage struct {
 	Type              WebSocketMessageType `json:"type,omitempty"`
 	Payload           map[string]any       `json:"payload,omitempty"`
 	IgnoreUserOnlines []string             `json:"ignore_user_onlines,omitempty"`
+	UserIDs           []string             `json:"user_ids,omitempty"` // send to these users instead of the conversation members
 }
 
 func NewWebSocketMessage(messageType WebSocketMessageType, payload map[string]any) *WebSocketMessage {
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type AccountHandler struct {
+	AccountUseCase usecase.AccountUseCase
+	Obs            *observability.Observability
+}
+
+func accountErrorStatus(err error) int {
+	switch err {
+	case usecase.ErrNotFoundAccount:
+		return http.StatusNotFound
+	case usecase.ErrWrongPassword, domain.ErrAccountDeletionNotScheduled:
+		return http.StatusBadRequest
+	case domain.ErrDataExportRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *AccountHandler) RequestDataExport(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AccountHandler.RequestDataExport")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	response, err := h.AccountUseCase.RequestDataExport(ctx, accountID)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.DataExportResponse]{
+		Message: "Data export requested successfully",
+		Data:    response,
+	})
+}
+
+func (h *AccountHandler) GetListDataExport(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AccountHandler.GetListDataExport")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	response, err := h.AccountUseCase.GetListDataExport(ctx, accountID)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.DataExportResponse]{
+		Message: "List data export fetched successfully",
+		Data:    response,
+	})
+}
+
+func (h *AccountHandler) ScheduleAccountDeletion(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AccountHandler.ScheduleAccountDeletion")
+	defer span()
+
+	var request presenter.DeleteAccountRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	response, err := h.AccountUseCase.ScheduleAccountDeletion(ctx, accountID, &request)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.AccountDeletionResponse]{
+		Message: "Account deletion scheduled successfully",
+		Data:    response,
+	})
+}
+
+func (h *AccountHandler) CancelAccountDeletion(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AccountHandler.CancelAccountDeletion")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	err := h.AccountUseCase.CancelAccountDeletion(ctx, accountID)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Account deletion cancelled successfully",
+	})
+}
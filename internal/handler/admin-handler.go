@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+const (
+	defaultAdminPageSize = 20
+	maxAdminPageSize     = 100
+)
+
+// AdminHandler serves the /admin routes, AdminMiddleware already checked the caller is an admin.
+type AdminHandler struct {
+	AdminUseCase usecase.AdminUseCase
+	Obs          *observability.Observability
+}
+
+func adminErrorStatus(err error) int {
+	switch err {
+	case domain.ErrAccountNotFound, domain.ErrConversationNotFound:
+		return http.StatusNotFound
+	case domain.ErrAdminTargetAdmin:
+		return http.StatusForbidden
+	case domain.ErrAccountNotRestricted:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func adminPageSize(c *app.RequestContext) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return defaultAdminPageSize
+	}
+	return min(limit, maxAdminPageSize)
+}
+
+func (h *AdminHandler) GetListUser(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.GetListUser")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	users, err := h.AdminUseCase.GetListUser(ctx, accountID, c.Query("keyword"), c.Query("last_id"), adminPageSize(c))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[[]*presenter.AdminUserResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.AdminUserResponse]{
+		Data:    users,
+		Message: "List user fetched successfully",
+	})
+}
+
+func (h *AdminHandler) GetListSession(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.GetListSession")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	sessions, err := h.AdminUseCase.GetListSession(ctx, accountID, c.Param("account_id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[[]*presenter.AdminSessionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.AdminSessionResponse]{
+		Data:    sessions,
+		Message: "List session fetched successfully",
+	})
+}
+
+func (h *AdminHandler) SuspendAccount(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.SuspendAccount")
+	defer span()
+
+	var request presenter.SuspendAccountRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.AdminAccountID = ctx.Value(utils.AccountIDKey).(string)
+	request.TargetID = c.Param("account_id")
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err := h.AdminUseCase.SuspendAccount(ctx, &request)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Account suspended successfully",
+	})
+}
+
+// bindAdminActionRequest binds the body of an action on the target of the route parameter, it responds on failure.
+func bindAdminActionRequest(ctx context.Context, c *app.RequestContext, param string) (*presenter.AdminActionRequest, bool) {
+	var request presenter.AdminActionRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	request.AdminAccountID = ctx.Value(utils.AccountIDKey).(string)
+	request.TargetID = c.Param(param)
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return &request, true
+}
+
+func (h *AdminHandler) BanAccount(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.BanAccount")
+	defer span()
+
+	request, ok := bindAdminActionRequest(ctx, c, "account_id")
+	if !ok {
+		return
+	}
+
+	err := h.AdminUseCase.BanAccount(ctx, request)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Account banned successfully",
+	})
+}
+
+func (h *AdminHandler) ReinstateAccount(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.ReinstateAccount")
+	defer span()
+
+	request, ok := bindAdminActionRequest(ctx, c, "account_id")
+	if !ok {
+		return
+	}
+
+	err := h.AdminUseCase.ReinstateAccount(ctx, request)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Account reinstated successfully",
+	})
+}
+
+func (h *AdminHandler) ResetPassword(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.ResetPassword")
+	defer span()
+
+	request, ok := bindAdminActionRequest(ctx, c, "account_id")
+	if !ok {
+		return
+	}
+
+	err := h.AdminUseCase.ResetPassword(ctx, request)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Password reset successfully",
+	})
+}
+
+func (h *AdminHandler) DeleteConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.DeleteConversation")
+	defer span()
+
+	request, ok := bindAdminActionRequest(ctx, c, "conversation_id")
+	if !ok {
+		return
+	}
+
+	err := h.AdminUseCase.DeleteConversation(ctx, request)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation deleted successfully",
+	})
+}
+
+// GetListAuditLog returns the audit log from the newest, target_type and target_id filter it.
+func (h *AdminHandler) GetListAuditLog(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "AdminHandler.GetListAuditLog")
+	defer span()
+
+	auditLogs, err := h.AdminUseCase.GetListAuditLog(ctx, c.Query("target_type"), c.Query("target_id"), c.Query("last_id"), adminPageSize(c))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), presenter.BaseResponse[[]*domain.AdminAuditLog]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*domain.AdminAuditLog]{
+		Data:    auditLogs,
+		Message: "List audit log fetched successfully",
+	})
+}
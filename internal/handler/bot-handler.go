@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type BotHandler struct {
+	BotUseCase  usecase.BotUseCase
+	UserUseCase usecase.UserUseCase
+	Obs         *observability.Observability
+}
+
+func botErrorStatus(err error) int {
+	switch err {
+	case domain.ErrBotNotFound, domain.ErrBotTokenNotFound:
+		return http.StatusNotFound
+	case domain.ErrBotLimitReached, domain.ErrBotTokenLimitReached, domain.ErrBotWebhookNotPublic, domain.ErrUploadedObjectNotFound, domain.ErrUploadBucketNotAllowed:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *BotHandler) CreateBot(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.CreateBot")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.CreateBotResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.CreateBotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.CreateBotRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.CreateBotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.OwnerID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.CreateBotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	bot, err := h.BotUseCase.CreateBot(ctx, &request)
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[*presenter.CreateBotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.CreateBotResponse]{
+		Data:    bot,
+		Message: "Bot created successfully",
+	})
+}
+
+func (h *BotHandler) GetListBot(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.GetListBot")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.BotResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	bots, err := h.BotUseCase.GetListBot(ctx, userID)
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[[]*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.BotResponse]{
+		Data:    bots,
+		Message: "List bot fetched successfully",
+	})
+}
+
+func (h *BotHandler) UpdateBot(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.UpdateBot")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.BotResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.UpdateBotRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.OwnerID = userID
+	request.BotID = c.Param("bot_id")
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	bot, err := h.BotUseCase.UpdateBot(ctx, &request)
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[*presenter.BotResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.BotResponse]{
+		Data:    bot,
+		Message: "Bot updated successfully",
+	})
+}
+
+func (h *BotHandler) DeleteBot(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.DeleteBot")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.BotUseCase.DeleteBot(ctx, userID, c.Param("bot_id"))
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Bot deleted successfully",
+	})
+}
+
+// CreateBotToken returns the new token, it can not be fetched again.
+func (h *BotHandler) CreateBotToken(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.CreateBotToken")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.BotTokenResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.CreateBotTokenRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	token, err := h.BotUseCase.CreateBotToken(ctx, userID, c.Param("bot_id"), &request)
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.BotTokenResponse]{
+		Data:    token,
+		Message: "Bot token created successfully",
+	})
+}
+
+func (h *BotHandler) GetListBotToken(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.GetListBotToken")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.BotTokenResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.BotUseCase.GetListBotToken(ctx, userID, c.Param("bot_id"))
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[[]*presenter.BotTokenResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.BotTokenResponse]{
+		Data:    tokens,
+		Message: "List bot token fetched successfully",
+	})
+}
+
+func (h *BotHandler) RevokeBotToken(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "BotHandler.RevokeBotToken")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.BotUseCase.RevokeBotToken(ctx, userID, c.Param("bot_id"), c.Param("token_id"))
+	if err != nil {
+		c.JSON(botErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Bot token revoked successfully",
+	})
+}
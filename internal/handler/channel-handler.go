@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type ChannelHandler struct {
+	ChannelUseCase usecase.ChannelUseCase
+	UserUseCase    usecase.UserUseCase
+	Obs            *observability.Observability
+}
+
+func channelErrorStatus(err error) int {
+	switch err {
+	case domain.ErrChannelNotFound:
+		return http.StatusNotFound
+	case domain.ErrChannelNotPublic, domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ChannelHandler) SearchChannel(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ChannelHandler.SearchChannel")
+	defer span()
+
+	keyword := c.Query("keyword")
+	lastID := c.Query("last_id")
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 20
+	}
+
+	channels, err := h.ChannelUseCase.SearchPublicChannel(ctx, keyword, lastID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ChannelResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ChannelResponse]{
+		Data:    channels,
+		Message: "List channel fetched successfully",
+	})
+}
+
+func (h *ChannelHandler) GetChannel(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ChannelHandler.GetChannel")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.ChannelUseCase.GetChannel(ctx, userID, c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(channelErrorStatus(err), presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ChannelResponse]{
+		Data:    channel,
+		Message: "Channel fetched successfully",
+	})
+}
+
+func (h *ChannelHandler) JoinChannel(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ChannelHandler.JoinChannel")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.ChannelUseCase.JoinChannel(ctx, userID, c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(channelErrorStatus(err), presenter.BaseResponse[*presenter.ChannelResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if channel.JoinRequest != nil {
+		c.JSON(http.StatusAccepted, presenter.BaseResponse[*presenter.ChannelResponse]{
+			Data:    channel,
+			Message: "Join request sent, waiting for approval",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ChannelResponse]{
+		Data:    channel,
+		Message: "Channel joined successfully",
+	})
+}
+
+func (h *ChannelHandler) LeaveChannel(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ChannelHandler.LeaveChannel")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ChannelUseCase.LeaveChannel(ctx, userID, c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(channelErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Channel left successfully",
+	})
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type ContactHandler struct {
+	ContactUseCase usecase.ContactUseCase
+	UserUseCase    usecase.UserUseCase
+	Obs            *observability.Observability
+}
+
+func contactErrorStatus(err error) int {
+	switch err {
+	case domain.ErrContactNotFound, domain.ErrContactRequestNotFound, domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrContactAlreadyExists:
+		return http.StatusConflict
+	case domain.ErrUserBlocked:
+		return http.StatusForbidden
+	case domain.ErrContactSelf:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ContactHandler) SendContactRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.SendContactRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.SendContactRequestRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.UserID = userID
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data, err := h.ContactUseCase.SendContactRequest(ctx, &request)
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+		Data:    data,
+		Message: "Contact request sent successfully",
+	})
+}
+
+func (h *ContactHandler) GetListContactRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.GetListContactRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ContactRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// the received requests by default, ?direction=outgoing lists the sent ones
+	data, err := h.ContactUseCase.GetListContactRequest(ctx, userID, c.Query("direction") == "outgoing")
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[[]*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ContactRequestResponse]{
+		Data:    data,
+		Message: "List contact request fetched successfully",
+	})
+}
+
+func (h *ContactHandler) AcceptContactRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.AcceptContactRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data, err := h.ContactUseCase.AcceptContactRequest(ctx, userID, c.Param("contact_request_id"))
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[*presenter.ContactRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ContactRequestResponse]{
+		Data:    data,
+		Message: "Contact request accepted successfully",
+	})
+}
+
+func (h *ContactHandler) DeclineContactRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.DeclineContactRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ContactUseCase.DeclineContactRequest(ctx, userID, c.Param("contact_request_id"))
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Contact request declined successfully",
+	})
+}
+
+func (h *ContactHandler) CancelContactRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.CancelContactRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ContactUseCase.CancelContactRequest(ctx, userID, c.Param("contact_request_id"))
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Contact request cancelled successfully",
+	})
+}
+
+func (h *ContactHandler) GetListContact(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.GetListContact")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ContactResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ContactResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 20
+	}
+	data, err := h.ContactUseCase.GetListContact(ctx, userID, c.Query("keyword"), c.Query("last_id"), limit)
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[[]*presenter.ContactResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ContactResponse]{
+		Data:    data,
+		Message: "List contact fetched successfully",
+	})
+}
+
+func (h *ContactHandler) RemoveContact(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ContactHandler.RemoveContact")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ContactUseCase.RemoveContact(ctx, userID, c.Param("user_id"))
+	if err != nil {
+		c.JSON(contactErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Contact removed successfully",
+	})
+}
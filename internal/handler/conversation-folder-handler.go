@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type ConversationFolderHandler struct {
+	ConversationFolderUseCase usecase.ConversationFolderUseCase
+	UserUseCase               usecase.UserUseCase
+	Obs                       *observability.Observability
+}
+
+func conversationFolderErrorStatus(err error) int {
+	switch err {
+	case domain.ErrConversationFolderNotFound:
+		return http.StatusNotFound
+	case domain.ErrConversationFolderLimit:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ConversationFolderHandler) CreateFolder(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationFolderHandler.CreateFolder")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.ConversationFolderRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	folder, err := h.ConversationFolderUseCase.CreateFolder(ctx, &request)
+	if err != nil {
+		c.JSON(conversationFolderErrorStatus(err), presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+		Data:    folder,
+		Message: "Conversation folder created successfully",
+	})
+}
+
+func (h *ConversationFolderHandler) UpdateFolder(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationFolderHandler.UpdateFolder")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.ConversationFolderRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.FolderID = c.Param("folder_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	folder, err := h.ConversationFolderUseCase.UpdateFolder(ctx, &request)
+	if err != nil {
+		c.JSON(conversationFolderErrorStatus(err), presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ConversationFolderResponse]{
+		Data:    folder,
+		Message: "Conversation folder updated successfully",
+	})
+}
+
+func (h *ConversationFolderHandler) DeleteFolder(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationFolderHandler.DeleteFolder")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ConversationFolderUseCase.DeleteFolder(ctx, userID, c.Param("folder_id"))
+	if err != nil {
+		c.JSON(conversationFolderErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation folder deleted successfully",
+	})
+}
+
+func (h *ConversationFolderHandler) GetListFolder(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationFolderHandler.GetListFolder")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ConversationFolderResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	folders, err := h.ConversationFolderUseCase.GetListFolder(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ConversationFolderResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ConversationFolderResponse]{
+		Data:    folders,
+		Message: "List conversation folder fetched successfully",
+	})
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 
+	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/internal/presenter"
 	"github.com/chat-socio/backend/internal/usecase"
 	"github.com/chat-socio/backend/internal/utils"
@@ -57,7 +61,27 @@ func (ch *ConversationHandler) SendMessage(ctx context.Context, c *app.RequestCo
 	}
 
 	sendMessageResponse, err := ch.ConversationUseCase.SendMessage(ctx, &request)
-	if err != nil {
+	var rateLimitErr *domain.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, presenter.BaseResponse[*presenter.RateLimitResponse]{
+			Message: err.Error(),
+			Data: &presenter.RateLimitResponse{
+				Reason:     rateLimitErr.Reason,
+				RetryAfter: retryAfter,
+			},
+		})
+		return
+	}
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied, domain.ErrUserBlocked:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
 		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
 			Message: err.Error(),
 		})
@@ -74,6 +98,22 @@ func (ch *ConversationHandler) CreateConversation(ctx context.Context, c *app.Re
 	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.CreateConversation")
 	defer span()
 
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
 	var request presenter.CreateConversationRequest
 	if err := c.BindAndValidate(&request); err != nil {
 		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationResponse]{
@@ -81,6 +121,7 @@ func (ch *ConversationHandler) CreateConversation(ctx context.Context, c *app.Re
 		})
 		return
 	}
+	request.UserID = userID
 
 	if err := request.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.ConversationResponse]{
@@ -90,6 +131,23 @@ func (ch *ConversationHandler) CreateConversation(ctx context.Context, c *app.Re
 	}
 
 	createConversationResponse, err := ch.ConversationUseCase.CreateConversation(ctx, &request)
+	var privacyErr *domain.PrivacyError
+	if errors.As(err, &privacyErr) {
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.PrivacyRefusedResponse]{
+			Message: err.Error(),
+			Data: &presenter.PrivacyRefusedResponse{
+				Reason:  privacyErr.Reason,
+				UserIDs: privacyErr.UserIDs,
+			},
+		})
+		return
+	}
+	if err == domain.ErrUserBlocked || err == domain.ErrPermissionDenied {
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationResponse]{
 			Message: err.Error(),
@@ -125,9 +183,16 @@ func (ch *ConversationHandler) GetListConversation(ctx context.Context, c *app.R
 
 	conversationID := c.Query("conversation_id")
 	if conversationID != "" {
-		conversation, err := ch.ConversationUseCase.GetConversationByID(ctx, conversationID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.GetListConversationResponse]{
+		conversation, err := ch.ConversationUseCase.GetConversationDetail(ctx, userID, conversationID)
+		switch err {
+		case nil:
+		case domain.ErrNotFoundMemberOfConversation:
+			c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.ConversationResponse]{
+				Message: err.Error(),
+			})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationResponse]{
 				Message: err.Error(),
 			})
 			return
@@ -145,8 +210,18 @@ func (ch *ConversationHandler) GetListConversation(ctx context.Context, c *app.R
 		limit = 20
 	}
 
-	listConversation, err := ch.ConversationUseCase.GetListConversationByUserID(ctx, userID, lastMessageID, limit)
-	if err != nil {
+	archived := c.Query("archived") == "true"
+	folderID := c.Query("folder_id")
+
+	listConversation, err := ch.ConversationUseCase.GetListConversationByUserID(ctx, userID, archived, folderID, lastMessageID, limit)
+	switch err {
+	case nil:
+	case domain.ErrConversationFolderNotFound:
+		c.JSON(http.StatusNotFound, presenter.BaseResponse[[]*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
 		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.GetListConversationResponse]{
 			Message: err.Error(),
 		})
@@ -159,6 +234,19 @@ func (ch *ConversationHandler) GetListConversation(ctx context.Context, c *app.R
 	})
 }
 
+// parseSeqQuery reads a message seq from the query, 0 when it is missing.
+func parseSeqQuery(c *app.RequestContext, name string) (int64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return seq, nil
+}
+
 func (ch *ConversationHandler) GetListMessage(ctx context.Context, c *app.RequestContext) {
 	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetListMessage")
 	defer span()
@@ -186,13 +274,31 @@ func (ch *ConversationHandler) GetListMessage(ctx context.Context, c *app.Reques
 		})
 		return
 	}
-	lastMessageID := c.Query("last_message_id")
 	limit, err := strconv.Atoi(c.Query("limit"))
 	if err != nil {
 		limit = 20
 	}
+	request := presenter.GetListMessageRequest{
+		ConversationID: conversationID,
+		UserID:         userID,
+		LastMessageID:  c.Query("last_message_id"),
+		Limit:          limit,
+	}
+	request.BeforeSeq, err = parseSeqQuery(c, "before_seq")
+	if err == nil {
+		request.AfterSeq, err = parseSeqQuery(c, "after_seq")
+	}
+	if err == nil {
+		err = request.Validate()
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.MessageResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
 
-	listMessage, err := ch.ConversationUseCase.GetListMessageByConversationID(ctx, userID, conversationID, lastMessageID, limit)
+	listMessage, err := ch.ConversationUseCase.GetListMessageByConversationID(ctx, &request)
 	if err != nil {
 		c.JSON(http.StatusNotFound, presenter.BaseResponse[[]*presenter.MessageResponse]{
 			Message: err.Error(),
@@ -210,9 +316,15 @@ func (ch *ConversationHandler) GetConversationByID(ctx context.Context, c *app.R
 	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetConversationByID")
 	defer span()
 
-	conversationID := c.Query("conversation_id")
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
 
-	conversation, err := ch.ConversationUseCase.GetConversationByID(ctx, conversationID)
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationResponse]{
 			Message: err.Error(),
@@ -220,12 +332,154 @@ func (ch *ConversationHandler) GetConversationByID(ctx context.Context, c *app.R
 		return
 	}
 
+	conversation, err := ch.ConversationUseCase.GetConversationDetail(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.ConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.ConversationResponse]{
 		Data:    conversation,
 		Message: "Conversation fetched successfully",
 	})
 }
 
+func (ch *ConversationHandler) GetListConversationMember(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetListConversationMember")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ConversationMemberResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ConversationMemberResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	keyword := c.Query("keyword")
+	lastUserID := c.Query("last_id")
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	members, err := ch.ConversationUseCase.GetListConversationMember(ctx, userID, c.Param("conversation_id"), keyword, lastUserID, limit)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[[]*presenter.ConversationMemberResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ConversationMemberResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ConversationMemberResponse]{
+		Data:    members,
+		Message: "List member fetched successfully",
+	})
+}
+
+func (ch *ConversationHandler) GetListMedia(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetListMedia")
+	defer span()
+
+	ch.getListAttachment(ctx, c, domain.AttachmentKindMedia)
+}
+
+func (ch *ConversationHandler) GetListFile(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetListFile")
+	defer span()
+
+	ch.getListAttachment(ctx, c, domain.AttachmentKindFile)
+}
+
+func (ch *ConversationHandler) GetListLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetListLink")
+	defer span()
+
+	ch.getListAttachment(ctx, c, domain.AttachmentKindLink)
+}
+
+// getListAttachment answers the gallery endpoints, they only differ by the kind of attachment.
+func (ch *ConversationHandler) getListAttachment(ctx context.Context, c *app.RequestContext, kind string) {
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 20
+	}
+	request := presenter.GetListAttachmentRequest{
+		ConversationID: c.Param("conversation_id"),
+		UserID:         userID,
+		Kind:           kind,
+		SenderID:       c.Query("sender_id"),
+		LastID:         c.Query("last_id"),
+		Limit:          limit,
+	}
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	attachments, err := ch.ConversationUseCase.GetListMessageAttachment(ctx, &request)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.MessageAttachmentResponse]{
+		Data:    attachments,
+		Message: "List attachment fetched successfully",
+	})
+}
+
 func (ch *ConversationHandler) SeenMessage(ctx context.Context, c *app.RequestContext) {
 	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.SeenMessage")
 	defer span()
@@ -266,3 +520,535 @@ func (ch *ConversationHandler) SeenMessage(ctx context.Context, c *app.RequestCo
 		Message: "Seen message successfully",
 	})
 }
+
+func (ch *ConversationHandler) UpdateConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.UpdateConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.UpdateConversationRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.ConversationID = c.Param("conversation_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	conversation, err := ch.ConversationUseCase.UpdateConversation(ctx, &request)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	case domain.ErrConversationNotUpdatable, domain.ErrUploadedObjectNotFound, domain.ErrUploadBucketNotAllowed, domain.ErrSendRateLimitNotAllowed:
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.GetListConversationResponse]{
+		Data:    conversation,
+		Message: "Conversation updated successfully",
+	})
+}
+
+func (ch *ConversationHandler) GetNotificationSetting(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetNotificationSetting")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	notificationSetting, err := ch.ConversationUseCase.GetNotificationSetting(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+		Data:    notificationSetting,
+		Message: "Notification setting fetched successfully",
+	})
+}
+
+func (ch *ConversationHandler) UpdateNotificationSetting(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.UpdateNotificationSetting")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.UpdateNotificationSettingRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.ConversationID = c.Param("conversation_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	notificationSetting, err := ch.ConversationUseCase.UpdateNotificationSetting(ctx, &request)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.NotificationSettingResponse]{
+		Data:    notificationSetting,
+		Message: "Notification setting updated successfully",
+	})
+}
+
+func (ch *ConversationHandler) GetUnreadCount(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.GetUnreadCount")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.UnreadCountResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.UnreadCountResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	unreadCount, err := ch.ConversationUseCase.GetUnreadCount(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.UnreadCountResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.UnreadCountResponse]{
+		Data:    unreadCount,
+		Message: "Unread count fetched successfully",
+	})
+}
+
+func (ch *ConversationHandler) ArchiveConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.ArchiveConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ch.ConversationUseCase.ArchiveConversation(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation archived successfully",
+	})
+}
+
+func (ch *ConversationHandler) UnarchiveConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.UnarchiveConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ch.ConversationUseCase.UnarchiveConversation(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation unarchived successfully",
+	})
+}
+
+func (ch *ConversationHandler) DeleteConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.DeleteConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ch.ConversationUseCase.DeleteConversationForMe(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation deleted successfully",
+	})
+}
+
+func (ch *ConversationHandler) PinConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.PinConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ch.ConversationUseCase.PinConversation(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	case domain.ErrPinnedConversationLimit:
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation pinned successfully",
+	})
+}
+
+func (ch *ConversationHandler) UnpinConversation(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.UnpinConversation")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ch.ConversationUseCase.UnpinConversation(ctx, userID, c.Param("conversation_id"))
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	case domain.ErrPinnedConversationLimit:
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Conversation unpinned successfully",
+	})
+}
+
+func (ch *ConversationHandler) AddReaction(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.AddReaction")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.ReactionRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.MessageID = c.Param("message_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reactions, err := ch.ConversationUseCase.AddReaction(ctx, &request)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	case domain.ErrMessageNotFound:
+		c.JSON(http.StatusNotFound, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+		Data:    reactions,
+		Message: "Reaction added successfully",
+	})
+}
+
+func (ch *ConversationHandler) RemoveReaction(ctx context.Context, c *app.RequestContext) {
+	ctx, span := ch.Obs.StartSpan(ctx, "ConversationHandler.RemoveReaction")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := ch.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.ReactionRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.MessageID = c.Param("message_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reactions, err := ch.ConversationUseCase.RemoveReaction(ctx, &request)
+	switch err {
+	case nil:
+	case domain.ErrNotFoundMemberOfConversation:
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	case domain.ErrMessageNotFound:
+		c.JSON(http.StatusNotFound, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.ReactionResponse]{
+		Data:    reactions,
+		Message: "Reaction removed successfully",
+	})
+}
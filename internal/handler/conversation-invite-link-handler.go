@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type ConversationInviteLinkHandler struct {
+	ConversationInviteLinkUseCase usecase.ConversationInviteLinkUseCase
+	UserUseCase                   usecase.UserUseCase
+	Obs                           *observability.Observability
+}
+
+func inviteLinkErrorStatus(err error) int {
+	switch err {
+	case domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied:
+		return http.StatusForbidden
+	case domain.ErrInviteLinkNotFound:
+		return http.StatusNotFound
+	case domain.ErrInviteLinkInvalid:
+		return http.StatusGone
+	case domain.ErrInviteLinkNotAllowed:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ConversationInviteLinkHandler) CreateInviteLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationInviteLinkHandler.CreateInviteLink")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var request presenter.CreateInviteLinkRequest
+	if err := c.BindAndValidate(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+	request.ConversationID = c.Param("conversation_id")
+	request.UserID = userID
+
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	inviteLink, err := h.ConversationInviteLinkUseCase.CreateInviteLink(ctx, &request)
+	if err != nil {
+		c.JSON(inviteLinkErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.InviteLinkResponse]{
+		Data:    inviteLink,
+		Message: "Invite link created successfully",
+	})
+}
+
+func (h *ConversationInviteLinkHandler) GetListInviteLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationInviteLinkHandler.GetListInviteLink")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	inviteLinks, err := h.ConversationInviteLinkUseCase.GetListInviteLink(ctx, userID, c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(inviteLinkErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.InviteLinkResponse]{
+		Data:    inviteLinks,
+		Message: "List invite link fetched successfully",
+	})
+}
+
+func (h *ConversationInviteLinkHandler) RevokeInviteLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationInviteLinkHandler.RevokeInviteLink")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.ConversationInviteLinkUseCase.RevokeInviteLink(ctx, userID, c.Param("conversation_id"), c.Param("invite_link_id"))
+	if err != nil {
+		c.JSON(inviteLinkErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Invite link revoked successfully",
+	})
+}
+
+func (h *ConversationInviteLinkHandler) PreviewInviteLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationInviteLinkHandler.PreviewInviteLink")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	preview, err := h.ConversationInviteLinkUseCase.PreviewInviteLink(ctx, userID, c.Param("token"))
+	if err != nil {
+		c.JSON(inviteLinkErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.InviteLinkPreviewResponse]{
+		Data:    preview,
+		Message: "Invite link fetched successfully",
+	})
+}
+
+func (h *ConversationInviteLinkHandler) JoinByInviteLink(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationInviteLinkHandler.JoinByInviteLink")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	joinResponse, err := h.ConversationInviteLinkUseCase.JoinByInviteLink(ctx, userID, c.Param("token"))
+	if err != nil {
+		c.JSON(inviteLinkErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if joinResponse.JoinRequest != nil {
+		c.JSON(http.StatusAccepted, presenter.BaseResponse[*presenter.JoinConversationResponse]{
+			Data:    joinResponse,
+			Message: "Join request sent, waiting for approval",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.JoinConversationResponse]{
+		Data:    joinResponse,
+		Message: "Joined conversation successfully",
+	})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type ConversationJoinRequestHandler struct {
+	ConversationJoinRequestUseCase usecase.ConversationJoinRequestUseCase
+	UserUseCase                    usecase.UserUseCase
+	Obs                            *observability.Observability
+}
+
+func joinRequestErrorStatus(err error) int {
+	switch err {
+	case domain.ErrNotFoundMemberOfConversation, domain.ErrPermissionDenied:
+		return http.StatusForbidden
+	case domain.ErrJoinRequestNotFound:
+		return http.StatusNotFound
+	case domain.ErrJoinRequestNotPending:
+		return http.StatusConflict
+	case domain.ErrJoinRequestNotAllowed:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ConversationJoinRequestHandler) GetListJoinRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationJoinRequestHandler.GetListJoinRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.JoinRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	joinRequests, err := h.ConversationJoinRequestUseCase.GetListJoinRequest(ctx, userID, c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(joinRequestErrorStatus(err), presenter.BaseResponse[[]*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.JoinRequestResponse]{
+		Data:    joinRequests,
+		Message: "List join request fetched successfully",
+	})
+}
+
+func (h *ConversationJoinRequestHandler) ApproveJoinRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationJoinRequestHandler.ApproveJoinRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	joinRequest, err := h.ConversationJoinRequestUseCase.ApproveJoinRequest(ctx, userID, c.Param("conversation_id"), c.Param("join_request_id"))
+	if err != nil {
+		c.JSON(joinRequestErrorStatus(err), presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+		Data:    joinRequest,
+		Message: "Join request approved successfully",
+	})
+}
+
+func (h *ConversationJoinRequestHandler) RejectJoinRequest(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "ConversationJoinRequestHandler.RejectJoinRequest")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	joinRequest, err := h.ConversationJoinRequestUseCase.RejectJoinRequest(ctx, userID, c.Param("conversation_id"), c.Param("join_request_id"))
+	if err != nil {
+		c.JSON(joinRequestErrorStatus(err), presenter.BaseResponse[*presenter.JoinRequestResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.JoinRequestResponse]{
+		Data:    joinRequest,
+		Message: "Join request rejected successfully",
+	})
+}
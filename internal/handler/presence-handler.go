@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type PresenceHandler struct {
+	PresenceUseCase usecase.PresenceUseCase
+	UserUseCase     usecase.UserUseCase
+	Obs             *observability.Observability
+}
+
+// GetListPresence returns the presence of the users in user_ids, separated by commas.
+func (h *PresenceHandler) GetListPresence(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "PresenceHandler.GetListPresence")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var userIDs []string
+	for _, id := range strings.Split(c.Query("user_ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	data, err := h.PresenceUseCase.GetListPresence(ctx, userID, userIDs)
+	if err == domain.ErrTooManyPresenceUserIDs {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*domain.Presence]{
+		Message: "Get list presence successfully",
+		Data:    data,
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type SyncHandler struct {
+	SyncUseCase usecase.SyncUseCase
+	UserUseCase usecase.UserUseCase
+	Obs         *observability.Observability
+}
+
+// Sync returns the events since the cursor of the client, or asks it to do a full resync.
+func (h *SyncHandler) Sync(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "SyncHandler.Sync")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[*presenter.SyncResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.SyncResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.SyncUseCase.Sync(ctx, userID, c.Query("since"))
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.SyncResponse]{
+			Data:    response,
+			Message: "Sync successfully",
+		})
+	case domain.ErrSyncCursorInvalid:
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.SyncResponse]{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[*presenter.SyncResponse]{
+			Message: err.Error(),
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/internal/presenter"
 	"github.com/chat-socio/backend/pkg/observability"
 	"github.com/chat-socio/backend/pkg/storage"
@@ -27,6 +28,13 @@ func (h *UploadHandler) UploadFile(ctx context.Context, c *app.RequestContext) {
 
 	bucketName := c.FormValue("bucket_name")
 	objectName := c.FormValue("object_name")
+	// the archives of the data exports and any other server bucket can not be written by a client
+	if !domain.IsUploadBucket(string(bucketName)) {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.UploadResponse]{
+			Message: domain.ErrUploadBucketNotAllowed.Error(),
+		})
+		return
+	}
 	file, err := c.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, presenter.BaseResponse[*presenter.UploadResponse]{
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/internal/usecase"
+	"github.com/chat-socio/backend/internal/utils"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+type UserBlockHandler struct {
+	UserBlockUseCase usecase.UserBlockUseCase
+	UserUseCase      usecase.UserUseCase
+	Obs              *observability.Observability
+}
+
+func userBlockErrorStatus(err error) int {
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrUserNotBlocked:
+		return http.StatusNotFound
+	case domain.ErrUserBlockSelf:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *UserBlockHandler) BlockUser(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "UserBlockHandler.BlockUser")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.UserBlockUseCase.BlockUser(ctx, userID, c.Param("user_id"))
+	if err != nil {
+		c.JSON(userBlockErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "User blocked successfully",
+	})
+}
+
+func (h *UserBlockHandler) UnblockUser(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "UserBlockHandler.UnblockUser")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.UserBlockUseCase.UnblockUser(ctx, userID, c.Param("user_id"))
+	if err != nil {
+		c.JSON(userBlockErrorStatus(err), presenter.BaseResponse[any]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "User unblocked successfully",
+	})
+}
+
+func (h *UserBlockHandler) GetListBlockedUser(ctx context.Context, c *app.RequestContext) {
+	ctx, span := h.Obs.StartSpan(ctx, "UserBlockHandler.GetListBlockedUser")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey)
+	if accountID == nil {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[[]*presenter.BlockedUserResponse]{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	userID, err := h.UserUseCase.GetUserIDByAccountID(ctx, accountID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[[]*presenter.BlockedUserResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data, err := h.UserBlockUseCase.GetListBlockedUser(ctx, userID)
+	if err != nil {
+		c.JSON(userBlockErrorStatus(err), presenter.BaseResponse[[]*presenter.BlockedUserResponse]{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[[]*presenter.BlockedUserResponse]{
+		Data:    data,
+		Message: "List blocked user fetched successfully",
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/internal/presenter"
 	"github.com/chat-socio/backend/internal/usecase"
 	"github.com/chat-socio/backend/internal/utils"
@@ -52,6 +53,15 @@ func (uh *UserHandler) Register(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
+// refreshTokenCookiePath limits the refresh token cookie to the refresh route.
+const refreshTokenCookiePath = "/user/token"
+
+// setTokenCookies sets the access token for its lifetime and the refresh token for the lifetime of the session.
+func setTokenCookies(c *app.RequestContext, response *presenter.LoginResponse) {
+	c.SetCookie("access_token", response.AccessToken, int(response.ExpiresIn), "/", configuration.ConfigInstance.Server.Origin, protocol.CookieSameSiteNoneMode, true, true)
+	c.SetCookie("refresh_token", response.RefreshToken, int(domain.SessionTTL.Seconds()), refreshTokenCookiePath, configuration.ConfigInstance.Server.Origin, protocol.CookieSameSiteNoneMode, true, true)
+}
+
 func (uh *UserHandler) Login(ctx context.Context, c *app.RequestContext) {
 	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.Login")
 	defer span()
@@ -79,6 +89,14 @@ func (uh *UserHandler) Login(ctx context.Context, c *app.RequestContext) {
 	}
 
 	response, err := uh.UserUseCase.Login(ctx, &loginRequest)
+	if err == domain.ErrEmailNotVerified {
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{Message: "Email is not verified"})
+		return
+	}
+	if err == domain.ErrAccountSuspended || err == domain.ErrAccountBanned {
+		c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
 	if err != nil && err != usecase.ErrNotFoundAccount && err != usecase.ErrWrongPassword {
 		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
 		return
@@ -94,7 +112,7 @@ func (uh *UserHandler) Login(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	c.SetCookie("access_token", response.AccessToken, 3600*24*6*30, "/", configuration.ConfigInstance.Server.Origin, protocol.CookieSameSiteNoneMode, true, true)
+	setTokenCookies(c, response)
 
 	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.LoginResponse]{
 		Message: "User logged in successfully",
@@ -102,6 +120,48 @@ func (uh *UserHandler) Login(ctx context.Context, c *app.RequestContext) {
 	})
 }
 
+// RefreshToken returns a new access token and a new refresh token, the refresh token can only be used once.
+func (uh *UserHandler) RefreshToken(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.RefreshToken")
+	defer span()
+
+	var request presenter.RefreshTokenRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+	if request.RefreshToken == "" {
+		request.RefreshToken = string(c.Cookie("refresh_token"))
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	response, err := uh.UserUseCase.RefreshToken(ctx, &request)
+	if err == domain.ErrRefreshTokenInvalid || err == domain.ErrRefreshTokenReused {
+		c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	setTokenCookies(c, response)
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.LoginResponse]{
+		Message: "Token refreshed successfully",
+		Data:    response,
+	})
+}
+
 func (uh *UserHandler) GetMyInfo(ctx context.Context, c *app.RequestContext) {
 	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.GetMyInfo")
 	defer span()
@@ -158,3 +218,399 @@ func (uh *UserHandler) GetListUser(ctx context.Context, c *app.RequestContext) {
 		Data:    response,
 	})
 }
+
+func profileErrorStatus(err error) int {
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrUploadedObjectNotFound:
+		return http.StatusNotFound
+	case domain.ErrHandleInvalid, domain.ErrHandleReserved, domain.ErrUploadBucketNotAllowed:
+		return http.StatusBadRequest
+	case domain.ErrHandleTaken:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (uh *UserHandler) UpdateProfile(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.UpdateProfile")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	var request presenter.UpdateProfileRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+	request.UserID = userID
+
+	err = request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	response, err := uh.UserUseCase.UpdateProfile(ctx, &request)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.GetUserInfoResponse]{
+		Message: "Profile updated successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) CheckHandleAvailability(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.CheckHandleAvailability")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	handle := c.Query("handle")
+	if handle == "" {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{Message: "handle is required"})
+		return
+	}
+
+	response, err := uh.UserUseCase.CheckHandleAvailability(ctx, userID, handle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.HandleAvailabilityResponse]{
+		Message: "Handle availability checked successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) GetPrivacySetting(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.GetPrivacySetting")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	response, err := uh.UserUseCase.GetPrivacySetting(ctx, userID)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.PrivacySettingResponse]{
+		Message: "Privacy setting fetched successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) UpdatePrivacySetting(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.UpdatePrivacySetting")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	var request presenter.UpdatePrivacySettingRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+	request.UserID = userID
+
+	err = request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	response, err := uh.UserUseCase.UpdatePrivacySetting(ctx, &request)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.PrivacySettingResponse]{
+		Message: "Privacy setting updated successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) SetStatus(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.SetStatus")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	var request presenter.SetUserStatusRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+	request.UserID = userID
+
+	err = request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	response, err := uh.UserUseCase.SetStatus(ctx, &request)
+	if err != nil {
+		c.JSON(profileErrorStatus(err), presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.UserStatusResponse]{
+		Message: "Status updated successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) ClearStatus(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.ClearStatus")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	userID, err := uh.UserUseCase.GetUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	err = uh.UserUseCase.ClearStatus(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Status cleared successfully",
+	})
+}
+
+func (uh *UserHandler) ChangePassword(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.ChangePassword")
+	defer span()
+
+	// the new session is bound to the user agent and the ip address like on login
+	ctx = context.WithValue(ctx, utils.UserAgentKey, c.Request.Header.Get("User-Agent"))
+	ctx = context.WithValue(ctx, utils.IpAddressKey, c.ClientIP())
+
+	var request presenter.ChangePasswordRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	response, err := uh.UserUseCase.ChangePassword(ctx, &request)
+	if err == usecase.ErrNotFoundAccount {
+		c.JSON(http.StatusNotFound, presenter.BaseResponse[any]{Message: "Account not found"})
+		return
+	}
+	if err == usecase.ErrWrongPassword {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{Message: "Wrong password"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	setTokenCookies(c, response)
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[*presenter.LoginResponse]{
+		Message: "Password changed successfully",
+		Data:    response,
+	})
+}
+
+func (uh *UserHandler) ForgotPassword(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.ForgotPassword")
+	defer span()
+
+	// the requests are limited per ip address
+	ctx = context.WithValue(ctx, utils.IpAddressKey, c.ClientIP())
+
+	var request presenter.ForgotPasswordRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	err = uh.UserUseCase.ForgotPassword(ctx, &request)
+	if err == domain.ErrPasswordResetRateLimited {
+		c.JSON(http.StatusTooManyRequests, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "If the email has an account, a reset link has been sent",
+	})
+}
+
+func (uh *UserHandler) ResetPassword(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.ResetPassword")
+	defer span()
+
+	var request presenter.ResetPasswordRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	err = uh.UserUseCase.ResetPassword(ctx, &request)
+	if err == domain.ErrPasswordResetTokenInvalid {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Password reset successfully",
+	})
+}
+
+func (uh *UserHandler) VerifyEmail(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.VerifyEmail")
+	defer span()
+
+	var request presenter.VerifyEmailRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	err = uh.UserUseCase.VerifyEmail(ctx, &request)
+	if err == domain.ErrEmailVerificationTokenInvalid {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "Email verified successfully",
+	})
+}
+
+func (uh *UserHandler) ResendVerificationEmail(ctx context.Context, c *app.RequestContext) {
+	ctx, span := uh.Obs.StartSpan(ctx, "UserHandler.ResendVerificationEmail")
+	defer span()
+
+	var request presenter.ResendVerificationEmailRequest
+	if err := c.Bind(&request); err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Invalid request: %v", err),
+		})
+		return
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, presenter.BaseResponse[any]{
+			Message: fmt.Sprintf("Validation error: %v", err),
+		})
+		return
+	}
+
+	err = uh.UserUseCase.ResendVerificationEmail(ctx, &request)
+	if err == domain.ErrEmailVerificationRateLimited {
+		c.JSON(http.StatusTooManyRequests, presenter.BaseResponse[any]{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{Message: fmt.Sprintf("Internal server error: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, presenter.BaseResponse[any]{
+		Message: "If the email has an unverified account, a verification link has been sent",
+	})
+}
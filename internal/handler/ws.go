@@ -3,8 +3,10 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/chat-socio/backend/configuration"
 	"github.com/chat-socio/backend/infrastructure/websocket"
@@ -12,15 +14,19 @@ import (
 	"github.com/chat-socio/backend/internal/usecase"
 	"github.com/chat-socio/backend/pkg/jwt"
 	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
 	"github.com/cloudwego/hertz/pkg/app"
 	ws "github.com/hertz-contrib/websocket"
 )
 
 type WebSocketHandler struct {
-	upgrader          *ws.HertzUpgrader
-	UserOnlineUsecase usecase.UserOnlineUsecase
-	UserUsecase       usecase.UserUseCase
-	obs               *observability.Observability
+	upgrader            *ws.HertzUpgrader
+	UserOnlineUsecase   usecase.UserOnlineUsecase
+	UserUsecase         usecase.UserUseCase
+	ConversationUseCase usecase.ConversationUseCase
+	PresenceUseCase     usecase.PresenceUseCase
+	BotUseCase          usecase.BotUseCase
+	obs                 *observability.Observability
 }
 
 func (wsh *WebSocketHandler) HandleWebsocket(ctx context.Context, c *app.RequestContext) {
@@ -57,58 +63,64 @@ func (wsh *WebSocketHandler) HandleWebsocket(ctx context.Context, c *app.Request
 			return
 		}
 
+		// renewAccessToken is set for the connections authenticated with an access token
+		var renewAccessToken func(token string) (time.Time, error)
 		// Handle the message based on its type
 		switch wsMessage.Type {
 		case domain.WsAuthorization:
-			// Handle authorization message
-			token, ok := wsMessage.Payload["token"]
-			if !ok {
-				wsConn.SendMessage(fmt.Appendf(nil, "Token not found in message"))
-				wsConn.Close()
-				return
-			}
-			// Validate the token and get the user ID
-			jwtToken, err := jwt.ParseHS256JWT(token.(string), configuration.ConfigInstance.JWT.SecretKey)
-			if err != nil {
-				wsConn.SendMessage(fmt.Appendf(nil, "Failed to parse token: %v", err))
-				wsConn.Close()
-				return
-			}
-
-			// validate
-			b, err := jwt.ValidateHS256JWT(jwtToken)
-			if err != nil {
-				wsConn.SendMessage(fmt.Appendf(nil, "Failed to validate token: %v", err))
-				wsConn.Close()
-				return
-			}
-
-			if !b {
-				wsConn.SendMessage(fmt.Appendf(nil, "Token is invalid"))
-				wsConn.Close()
-				return
+			// Handle authorization message, a bot authenticates with its bot token instead of a user token
+			var accountID string
+			// a connection authenticated with an access token is closed once it expires, unless the client
+			// sends a renewed one on the same socket
+			var accessTokenExpiresAt *time.Time
+			// the session of the access token, a renewed token must belong to the same one
+			var sessionToken string
+			if botToken, ok := wsMessage.Payload["bot_token"].(string); ok {
+				accountID, err = wsh.BotUseCase.GetAccountIDByBotToken(ctx, botToken)
+				if err != nil {
+					wsConn.SendMessage(fmt.Appendf(nil, "Failed to validate bot token: %v", err))
+					wsConn.Close()
+					return
+				}
+			} else {
+				token, ok := wsMessage.Payload["token"].(string)
+				if !ok {
+					wsConn.SendMessage(fmt.Appendf(nil, "Token not found in message"))
+					wsConn.Close()
+					return
+				}
+				claims, err := parseAccessToken(token)
+				if err == nil {
+					// a revoked session can not reconnect with an access token not expired yet
+					err = wsh.UserUsecase.CheckSession(ctx, claims.Sub, claims.Jit)
+				}
+				if err != nil {
+					wsConn.SendMessage(fmt.Appendf(nil, "Failed to validate token: %v", err))
+					wsConn.Close()
+					return
+				}
+				accountID = claims.Sub
+				sessionToken = claims.Jit
+				accessTokenExpiresAt = pointer.ToPtr(time.Unix(claims.Exp, 0))
 			}
-
-			// Get claims from the token
-			claims, err := jwt.ExtractClaims(jwtToken)
+			err = wsh.UserUsecase.CheckAccountRestriction(ctx, accountID)
 			if err != nil {
-				wsConn.SendMessage(fmt.Appendf(nil, "Failed to extract claims: %v", err))
+				wsConn.SendMessage(fmt.Appendf(nil, "Account is not allowed to connect: %v", err))
 				wsConn.Close()
 				return
 			}
 
-			// Get claims from the token
-			claims, err = jwt.ExtractClaims(jwtToken)
+			domain.WebSocket.AddWrapConnection(wsConn)
+			userID, err := wsh.UserUsecase.GetUserIDByAccountID(ctx, accountID)
 			if err != nil {
-				wsConn.SendMessage(fmt.Appendf(nil, "Failed to extract claims: %v", err))
+				wsConn.SendMessage(fmt.Appendf(nil, "Failed to get user ID: %v", err))
 				wsConn.Close()
 				return
 			}
-
-			domain.WebSocket.AddWrapConnection(wsConn)
-			userID, err := wsh.UserUsecase.GetUserIDByAccountID(ctx, claims.Sub)
+			wsConn.SetUserID(userID)
+			err = wsh.ConversationUseCase.SubscribeChannels(ctx, userID, wsConn.GetID())
 			if err != nil {
-				wsConn.SendMessage(fmt.Appendf(nil, "Failed to get user ID: %v", err))
+				wsConn.SendMessage(fmt.Appendf(nil, "Failed to subscribe channels: %v", err))
 				wsConn.Close()
 				return
 			}
@@ -122,10 +134,47 @@ func (wsh *WebSocketHandler) HandleWebsocket(ctx context.Context, c *app.Request
 				wsConn.Close()
 				return
 			}
+			// the user is offline on this connection once it stops reading
+			defer wsh.UserOnlineUsecase.DeleteUserOnline(ctx, userOnline.ID)
+			err = wsh.PresenceUseCase.Connect(ctx, userID, wsConn.GetID())
+			if err != nil {
+				wsConn.SendMessage(fmt.Appendf(nil, "Failed to connect presence: %v", err))
+				wsConn.Close()
+				return
+			}
+			defer wsh.PresenceUseCase.Disconnect(ctx, userID, wsConn.GetID())
+			// keep the presence of the connection until it stops reading
+			stopRefresh := make(chan struct{})
+			defer close(stopRefresh)
+			go wsh.refreshPresence(ctx, userID, wsConn.GetID(), stopRefresh)
+			if accessTokenExpiresAt != nil {
+				expiryTimer := time.AfterFunc(time.Until(*accessTokenExpiresAt), func() {
+					wsConn.SendMessage(fmt.Appendf(nil, "Token expired"))
+					wsConn.Close()
+				})
+				defer expiryTimer.Stop()
+				renewAccessToken = func(token string) (time.Time, error) {
+					claims, err := parseAccessToken(token)
+					if err != nil {
+						return time.Time{}, err
+					}
+					if claims.Sub != accountID || claims.Jit != sessionToken {
+						return time.Time{}, errors.New("token belongs to another session")
+					}
+					err = wsh.UserUsecase.CheckSession(ctx, accountID, sessionToken)
+					if err != nil {
+						return time.Time{}, err
+					}
+					expiresAt := time.Unix(claims.Exp, 0)
+					expiryTimer.Reset(time.Until(expiresAt))
+					return expiresAt, nil
+				}
+			}
 			wsResonse := domain.NewWebSocketMessage(domain.WsAuthorization, map[string]any{
-				"account_id":     claims.Sub,
+				"account_id":     accountID,
 				"user_id":        userID,
 				"user_online_id": userOnline.ID,
+				"expires_at":     accessTokenExpiresAt,
 			})
 			wsConn.SendMessage([]byte(wsResonse.String()))
 		default:
@@ -160,6 +209,31 @@ func (wsh *WebSocketHandler) HandleWebsocket(ctx context.Context, c *app.Request
 				// Send a pong message back
 				pongMessage := domain.NewWebSocketMessage(domain.WsPong, nil)
 				err = wsConn.SendMessage([]byte(pongMessage.String()))
+			case domain.WsAuthorization:
+				// Handle the access token renewed by the client, the connection keeps its subscriptions
+				token, _ := wsMessage.Payload["token"].(string)
+				var expiresAt time.Time
+				if renewAccessToken == nil {
+					err = errors.New("connection is not authenticated with an access token")
+				} else {
+					expiresAt, err = renewAccessToken(token)
+				}
+				if err != nil {
+					// the connection is closed below
+					wsConn.SendMessage(fmt.Appendf(nil, "Failed to renew token: %v", err))
+				} else {
+					authMessage := domain.NewWebSocketMessage(domain.WsAuthorization, map[string]any{
+						"expires_at": expiresAt,
+					})
+					err = wsConn.SendMessage([]byte(authMessage.String()))
+				}
+			case domain.WsPresenceUpdate:
+				// Handle the client going away, or back online
+				status, _ := wsMessage.Payload["status"].(string)
+				err = wsh.PresenceUseCase.UpdateStatus(ctx, wsConn.GetUserID(), wsConn.GetID(), status)
+				if err == domain.ErrPresenceStatusInvalid {
+					err = wsConn.SendMessage([]byte(err.Error()))
+				}
 			default:
 				// Handle other message types
 				// wsConn.SendMessage([]byte(fmt.Sprintf("Unknown message type: %s", wsMessage.Type)))
@@ -182,11 +256,46 @@ func (wsh *WebSocketHandler) HandleWebsocket(ctx context.Context, c *app.Request
 	}
 }
 
-func NewWebSocketHandler(upgrader *ws.HertzUpgrader, userOnlineUsecase usecase.UserOnlineUsecase, userUsecase usecase.UserUseCase, obs *observability.Observability) *WebSocketHandler {
+// parseAccessToken returns the claims of a valid and unexpired access token.
+func parseAccessToken(token string) (jwt.JWTClaims, error) {
+	jwtToken, err := jwt.ParseHS256JWT(token, configuration.ConfigInstance.JWT.SecretKey)
+	if err != nil {
+		return jwt.JWTClaims{}, err
+	}
+	b, err := jwt.ValidateHS256JWT(jwtToken)
+	if err != nil {
+		return jwt.JWTClaims{}, err
+	}
+	if !b {
+		return jwt.JWTClaims{}, errors.New("token is invalid")
+	}
+	return jwt.ExtractClaims(jwtToken)
+}
+
+func (wsh *WebSocketHandler) refreshPresence(ctx context.Context, userID string, connectionID string, stop <-chan struct{}) {
+	ticker := time.NewTicker(domain.PresenceRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			err := wsh.PresenceUseCase.Refresh(ctx, userID, connectionID)
+			if err != nil {
+				log.Println("Failed to refresh presence:", err)
+			}
+		}
+	}
+}
+
+func NewWebSocketHandler(upgrader *ws.HertzUpgrader, userOnlineUsecase usecase.UserOnlineUsecase, userUsecase usecase.UserUseCase, conversationUseCase usecase.ConversationUseCase, presenceUseCase usecase.PresenceUseCase, botUseCase usecase.BotUseCase, obs *observability.Observability) *WebSocketHandler {
 	return &WebSocketHandler{
-		upgrader:          upgrader,
-		UserOnlineUsecase: userOnlineUsecase,
-		UserUsecase:       userUsecase,
-		obs:               obs,
+		upgrader:            upgrader,
+		UserOnlineUsecase:   userOnlineUsecase,
+		UserUsecase:         userUsecase,
+		ConversationUseCase: conversationUseCase,
+		PresenceUseCase:     presenceUseCase,
+		BotUseCase:          botUseCase,
+		obs:                 obs,
 	}
 }
@@ -12,17 +12,22 @@ import (
 	"github.com/chat-socio/backend/internal/utils"
 	"github.com/chat-socio/backend/pkg/jwt"
 	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/jackc/pgx/v5"
 )
 
 type Middleware struct {
 	sessionCacheRepository domain.SessionCacheRepository
 	sessionRepository      domain.SessionRepository
+	accountRepository      domain.AccountRepository
+	botRepository          domain.BotRepository
 }
 
-func NewMiddleware(sessionCacheRepository domain.SessionCacheRepository, sessionRepository domain.SessionRepository) *Middleware {
+func NewMiddleware(sessionCacheRepository domain.SessionCacheRepository, sessionRepository domain.SessionRepository, accountRepository domain.AccountRepository, botRepository domain.BotRepository) *Middleware {
 	return &Middleware{
 		sessionCacheRepository: sessionCacheRepository,
 		sessionRepository:      sessionRepository,
+		accountRepository:      accountRepository,
+		botRepository:          botRepository,
 	}
 }
 
@@ -165,3 +170,93 @@ func (m *Middleware) AuthMiddleware() app.HandlerFunc {
 		c.Next(ctx)
 	}
 }
+
+// BotAuthMiddleware authenticates a bot with the "Authorization: Bot <token>" header,
+// the account of the bot is set in the context like AuthMiddleware does for the users.
+func (m *Middleware) BotAuthMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		botToken, ok := strings.CutPrefix(c.Request.Header.Get("Authorization"), "Bot ")
+		if !ok || botToken == "" {
+			c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+				Message: "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		accountID, err := m.botRepository.GetAccountIDByBotToken(ctx, domain.HashToken(botToken), time.Now())
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, presenter.BaseResponse[any]{
+				Message: "Unauthorized",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+				Message: "Internal server error",
+			})
+			c.Abort()
+			return
+		}
+
+		ctx = context.WithValue(ctx, utils.AccountIDKey, accountID)
+		c.Next(ctx)
+	}
+}
+
+// VerifiedEmailMiddleware rejects the accounts whose email is not verified, unless allowUnverified is set.
+// It runs after AuthMiddleware.
+func (m *Middleware) VerifiedEmailMiddleware(allowUnverified bool) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if allowUnverified {
+			c.Next(ctx)
+			return
+		}
+
+		accountID, _ := ctx.Value(utils.AccountIDKey).(string)
+		account, err := m.accountRepository.GetAccountByID(ctx, accountID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+				Message: "Internal server error",
+			})
+			c.Abort()
+			return
+		}
+
+		if !account.IsEmailVerified() {
+			c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+				Message: "Email is not verified",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
+
+// AdminMiddleware rejects the accounts which are not admins. It runs after AuthMiddleware.
+func (m *Middleware) AdminMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		accountID, _ := ctx.Value(utils.AccountIDKey).(string)
+		account, err := m.accountRepository.GetAccountByID(ctx, accountID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, presenter.BaseResponse[any]{
+				Message: "Internal server error",
+			})
+			c.Abort()
+			return
+		}
+
+		if !account.IsAdmin() {
+			c.JSON(http.StatusForbidden, presenter.BaseResponse[any]{
+				Message: "Forbidden",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
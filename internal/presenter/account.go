@@ -3,7 +3,11 @@ package presenter
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/chat-socio/backend/internal/domain"
 )
 
 var (
@@ -65,27 +69,234 @@ func (r LoginRequest) Validate() error {
 	return nil
 }
 
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password,omitempty"`
+	NewPassword string `json:"new_password,omitempty"`
+}
+
+func (r ChangePasswordRequest) Validate() error {
+	if r.OldPassword == "" {
+		return fmt.Errorf("old_password is required")
+	}
+	if len(r.NewPassword) < 6 {
+		return fmt.Errorf("new_password must be at least 6 characters long")
+	}
+	if r.NewPassword == r.OldPassword {
+		return fmt.Errorf("new_password must be different from old_password")
+	}
+	return nil
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email,omitempty"`
+}
+
+func (r ForgotPasswordRequest) Validate() error {
+	if !isValidEmail(r.Email) {
+		return fmt.Errorf("invalid email format")
+	}
+	return nil
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token,omitempty"`
+	NewPassword string `json:"new_password,omitempty"`
+}
+
+func (r ResetPasswordRequest) Validate() error {
+	if r.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	if len(r.NewPassword) < 6 {
+		return fmt.Errorf("new_password must be at least 6 characters long")
+	}
+	return nil
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token,omitempty"`
+}
+
+func (r VerifyEmailRequest) Validate() error {
+	if r.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	return nil
+}
+
+type ResendVerificationEmailRequest struct {
+	Email string `json:"email,omitempty"`
+}
+
+func (r ResendVerificationEmailRequest) Validate() error {
+	if !isValidEmail(r.Email) {
+		return fmt.Errorf("invalid email format")
+	}
+	return nil
+}
+
 type LoginResponse struct {
-	AccessToken string `json:"access_token,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // lifetime of the access token in seconds
+}
+
+// RefreshTokenRequest falls back on the refresh_token cookie when the body has no token.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (r RefreshTokenRequest) Validate() error {
+	if r.RefreshToken == "" {
+		return fmt.Errorf("refresh_token is required")
+	}
+	return nil
 }
 
 type GetUserInfoResponse struct {
-	AccountID      string     `json:"account_id,omitempty"`
-	UserID         string     `json:"user_id,omitempty"`
-	Type           string     `json:"type,omitempty"`
-	Email          string     `json:"email,omitempty"`
-	FullName       string     `json:"full_name,omitempty"`
-	Avatar         string     `json:"avatar,omitempty"`
-	CreatedAt      *time.Time `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
-	ConversationID *string    `json:"conversation_id,omitempty"`
+	AccountID      string              `json:"account_id,omitempty"`
+	UserID         string              `json:"user_id,omitempty"`
+	Type           string              `json:"type,omitempty"`
+	Email          string              `json:"email,omitempty"`
+	FullName       string              `json:"full_name,omitempty"`
+	Avatar         string              `json:"avatar,omitempty"`
+	Handle         string              `json:"handle,omitempty"`
+	Bio            string              `json:"bio,omitempty"`
+	Status         *UserStatusResponse `json:"status,omitempty"`
+	CreatedAt      *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time          `json:"updated_at,omitempty"`
+	ConversationID *string             `json:"conversation_id,omitempty"`
+	ContactState   string              `json:"contact_state,omitempty"`  // relation of the user with the caller in the search results
+	EmailVerified  *bool               `json:"email_verified,omitempty"` // only for the caller
+	// DeletionScheduledAt is set for the caller when their account will be deleted
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
 type UserResponse struct {
-	UserID    string     `json:"user_id,omitempty"`
-	FullName  string     `json:"full_name,omitempty"`
-	Avatar    string     `json:"avatar,omitempty"`
-	UserType  string     `json:"user_type,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserID    string              `json:"user_id,omitempty"`
+	FullName  string              `json:"full_name,omitempty"`
+	Avatar    string              `json:"avatar,omitempty"`
+	Handle    string              `json:"handle,omitempty"`
+	UserType  string              `json:"user_type,omitempty"`
+	Status    *UserStatusResponse `json:"status,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty"`
+}
+
+// UserStatusResponse is the custom status of a user, it is omitted when the user has none or it expired.
+type UserStatusResponse struct {
+	Emoji     string     `json:"emoji,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type SetUserStatusRequest struct {
+	UserID    string     `json:"-"`
+	Emoji     string     `json:"emoji,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // the status is kept until cleared when empty
+}
+
+func (r *SetUserStatusRequest) Validate() error {
+	r.Emoji = strings.TrimSpace(r.Emoji)
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Emoji == "" && r.Text == "" {
+		return fmt.Errorf("emoji or text is required")
+	}
+	if len(r.Emoji) > domain.MaxStatusEmojiLength {
+		return fmt.Errorf("emoji must be at most %d bytes long", domain.MaxStatusEmojiLength)
+	}
+	if utf8.RuneCountInString(r.Text) > domain.MaxStatusTextLength {
+		return fmt.Errorf("text must be at most %d characters long", domain.MaxStatusTextLength)
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	return nil
+}
+
+type UpdateProfileRequest struct {
+	UserID           string  `json:"-"`
+	FullName         *string `json:"full_name,omitempty"`
+	Bio              *string `json:"bio,omitempty"`
+	Handle           *string `json:"handle,omitempty"` // an empty handle removes it
+	AvatarBucketName string  `json:"avatar_bucket_name,omitempty"`
+	AvatarObjectName string  `json:"avatar_object_name,omitempty"`
+}
+
+func (r *UpdateProfileRequest) Validate() error {
+	if r.FullName == nil && r.Bio == nil && r.Handle == nil && r.AvatarObjectName == "" {
+		return fmt.Errorf("nothing to update")
+	}
+	if r.FullName != nil && strings.TrimSpace(*r.FullName) == "" {
+		return fmt.Errorf("full_name can not be empty")
+	}
+	if r.FullName != nil && len(*r.FullName) > 255 {
+		return fmt.Errorf("full_name must be at most 255 characters long")
+	}
+	if r.Bio != nil && utf8.RuneCountInString(*r.Bio) > domain.MaxBioLength {
+		return fmt.Errorf("bio must be at most %d characters long", domain.MaxBioLength)
+	}
+	if r.AvatarObjectName != "" && r.AvatarBucketName == "" {
+		return fmt.Errorf("avatar_bucket_name is required")
+	}
+	return nil
+}
+
+type HandleAvailabilityResponse struct {
+	Handle    string `json:"handle,omitempty"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"` // why the handle can not be used
+}
+
+type PrivacySettingResponse struct {
+	LastSeen      string `json:"last_seen,omitempty"`
+	Avatar        string `json:"avatar,omitempty"`
+	DirectMessage string `json:"direct_message,omitempty"`
+	GroupAdd      string `json:"group_add,omitempty"`
+}
+
+// UpdatePrivacySettingRequest only changes the settings sent, each one is everyone, contacts or nobody.
+type UpdatePrivacySettingRequest struct {
+	UserID        string  `json:"-"`
+	LastSeen      *string `json:"last_seen,omitempty"`
+	Avatar        *string `json:"avatar,omitempty"`
+	DirectMessage *string `json:"direct_message,omitempty"`
+	GroupAdd      *string `json:"group_add,omitempty"`
+}
+
+func (r *UpdatePrivacySettingRequest) Validate() error {
+	if r.LastSeen == nil && r.Avatar == nil && r.DirectMessage == nil && r.GroupAdd == nil {
+		return fmt.Errorf("nothing to update")
+	}
+	names := []string{"last_seen", "avatar", "direct_message", "group_add"}
+	for i, privacy := range []*string{r.LastSeen, r.Avatar, r.DirectMessage, r.GroupAdd} {
+		if privacy != nil && !domain.IsValidPrivacy(*privacy) {
+			return fmt.Errorf("%s must be %s, %s or %s", names[i], domain.PrivacyEveryone, domain.PrivacyContacts, domain.PrivacyNobody)
+		}
+	}
+	return nil
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password,omitempty"`
+}
+
+func (r DeleteAccountRequest) Validate() error {
+	if r.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	return nil
+}
+
+type AccountDeletionResponse struct {
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type DataExportResponse struct {
+	DataExportID string     `json:"data_export_id,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	DownloadURL  string     `json:"download_url,omitempty"` // only for the completed exports, it expires after an hour
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 }
@@ -0,0 +1,69 @@
+package presenter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+)
+
+type AdminUserResponse struct {
+	AccountID           string     `json:"account_id,omitempty"`
+	UserID              string     `json:"user_id,omitempty"`
+	Type                string     `json:"type,omitempty"`
+	Email               string     `json:"email,omitempty"`
+	FullName            string     `json:"full_name,omitempty"`
+	Avatar              string     `json:"avatar,omitempty"`
+	Handle              *string    `json:"handle,omitempty"`
+	Role                string     `json:"role,omitempty"`
+	EmailVerified       bool       `json:"email_verified"`
+	SuspendedUntil      *time.Time `json:"suspended_until,omitempty"`
+	BannedAt            *time.Time `json:"banned_at,omitempty"`
+	RestrictionReason   string     `json:"restriction_reason,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           *time.Time `json:"created_at,omitempty"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AdminSessionResponse leaves out the session token, it would let the admin act as the user.
+type AdminSessionResponse struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	IsActive  bool       `json:"is_active"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	IPAddress string     `json:"ip_address,omitempty"`
+}
+
+// AdminActionRequest is the body of an admin action on an account or a conversation,
+// the reason is written to the audit log.
+type AdminActionRequest struct {
+	AdminAccountID string `json:"-"`
+	TargetID       string `json:"-"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+func (r *AdminActionRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if len(r.Reason) > domain.MaxAdminReasonLength {
+		return fmt.Errorf("reason must be at most %d characters long", domain.MaxAdminReasonLength)
+	}
+	return nil
+}
+
+type SuspendAccountRequest struct {
+	AdminActionRequest
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+}
+
+func (r *SuspendAccountRequest) Validate() error {
+	if r.SuspendedUntil == nil {
+		return errors.New("suspended_until is required")
+	}
+	if !r.SuspendedUntil.After(time.Now()) {
+		return errors.New("suspended_until must be in the future")
+	}
+	return r.AdminActionRequest.Validate()
+}
@@ -0,0 +1,110 @@
+package presenter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+)
+
+type CreateBotRequest struct {
+	OwnerID          string `json:"-"`
+	FullName         string `json:"full_name,omitempty"`
+	AvatarBucketName string `json:"avatar_bucket_name,omitempty"`
+	AvatarObjectName string `json:"avatar_object_name,omitempty"`
+	WebhookURL       string `json:"webhook_url,omitempty"` // the bot uses a websocket connection without it
+}
+
+func (r *CreateBotRequest) Validate() error {
+	r.FullName = strings.TrimSpace(r.FullName)
+	if r.FullName == "" {
+		return errors.New("full_name is required")
+	}
+	if len(r.FullName) > 255 {
+		return errors.New("full_name must be at most 255 characters long")
+	}
+	if r.AvatarObjectName != "" && r.AvatarBucketName == "" {
+		return errors.New("avatar_bucket_name is required")
+	}
+	return validateWebhookURL(r.WebhookURL)
+}
+
+// UpdateBotRequest only changes the fields sent, an empty webhook_url removes the webhook.
+type UpdateBotRequest struct {
+	OwnerID          string  `json:"-"`
+	BotID            string  `json:"-"`
+	FullName         *string `json:"full_name,omitempty"`
+	AvatarBucketName string  `json:"avatar_bucket_name,omitempty"`
+	AvatarObjectName string  `json:"avatar_object_name,omitempty"`
+	WebhookURL       *string `json:"webhook_url,omitempty"`
+}
+
+func (r *UpdateBotRequest) Validate() error {
+	if r.FullName == nil && r.AvatarObjectName == "" && r.WebhookURL == nil {
+		return errors.New("nothing to update")
+	}
+	if r.FullName != nil && strings.TrimSpace(*r.FullName) == "" {
+		return errors.New("full_name can not be empty")
+	}
+	if r.FullName != nil && len(*r.FullName) > 255 {
+		return errors.New("full_name must be at most 255 characters long")
+	}
+	if r.AvatarObjectName != "" && r.AvatarBucketName == "" {
+		return errors.New("avatar_bucket_name is required")
+	}
+	if r.WebhookURL != nil {
+		return validateWebhookURL(*r.WebhookURL)
+	}
+	return nil
+}
+
+func validateWebhookURL(webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("webhook_url must be an http or https url")
+	}
+	return nil
+}
+
+// BotResponse is only sent to the owner of the bot, it holds the secret signing the webhook events.
+type BotResponse struct {
+	BotID         string     `json:"bot_id,omitempty"`
+	UserID        string     `json:"user_id,omitempty"`
+	FullName      string     `json:"full_name,omitempty"`
+	Avatar        string     `json:"avatar,omitempty"`
+	WebhookURL    string     `json:"webhook_url,omitempty"`
+	WebhookSecret string     `json:"webhook_secret,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+type CreateBotResponse struct {
+	Bot   *BotResponse      `json:"bot,omitempty"`
+	Token *BotTokenResponse `json:"token,omitempty"`
+}
+
+type CreateBotTokenRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (r *CreateBotTokenRequest) Validate() error {
+	if len(r.Name) > domain.MaxBotTokenNameLength {
+		return fmt.Errorf("name must be at most %d characters long", domain.MaxBotTokenNameLength)
+	}
+	return nil
+}
+
+type BotTokenResponse struct {
+	TokenID    string     `json:"token_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Token      string     `json:"token,omitempty"` // only once, when the token is created
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
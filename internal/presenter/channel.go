@@ -0,0 +1,17 @@
+package presenter
+
+import "time"
+
+type ChannelResponse struct {
+	ConversationID  string               `json:"conversation_id,omitempty"`
+	Title           string               `json:"title,omitempty"`
+	Avatar          string               `json:"avatar,omitempty"`
+	Description     string               `json:"description,omitempty"`
+	IsPublic        bool                 `json:"is_public"`
+	SubscriberCount int                  `json:"subscriber_count"`
+	IsSubscribed    bool                 `json:"is_subscribed"`
+	Role            string               `json:"role,omitempty"`
+	RequireApproval bool                 `json:"require_approval"`
+	JoinRequest     *JoinRequestResponse `json:"join_request,omitempty"` // pending request of the user
+	CreatedAt       *time.Time           `json:"created_at,omitempty"`
+}
@@ -0,0 +1,39 @@
+package presenter
+
+import (
+	"errors"
+	"time"
+)
+
+type SendContactRequestRequest struct {
+	UserID   string `json:"-"`
+	FriendID string `json:"user_id,omitempty"`
+}
+
+func (s *SendContactRequestRequest) Validate() error {
+	if s.FriendID == "" {
+		return errors.New("user_id is required")
+	}
+	return nil
+}
+
+// ContactRequestResponse is a friend request from the sender to the receiver. The lists only
+// have the user on the other side of the request, the events have both.
+type ContactRequestResponse struct {
+	ContactRequestID string        `json:"contact_request_id,omitempty"`
+	SenderID         string        `json:"sender_id,omitempty"`
+	ReceiverID       string        `json:"receiver_id,omitempty"`
+	Status           string        `json:"status,omitempty"`
+	Sender           *UserResponse `json:"sender,omitempty"`
+	Receiver         *UserResponse `json:"receiver,omitempty"`
+	CreatedAt        *time.Time    `json:"created_at,omitempty"`
+}
+
+type ContactResponse struct {
+	UserID    string     `json:"user_id,omitempty"`
+	FullName  string     `json:"full_name,omitempty"`
+	Avatar    string     `json:"avatar,omitempty"`
+	UserType  string     `json:"user_type,omitempty"`
+	IsOnline  bool       `json:"is_online"`
+	CreatedAt *time.Time `json:"created_at,omitempty"` // when the request was accepted
+}
@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+)
+
+type ConversationFolderRequest struct {
+	FolderID          string   `json:"-"`
+	UserID            string   `json:"-"`
+	Name              string   `json:"name,omitempty"`
+	ConversationTypes []string `json:"conversation_types,omitempty"` // include every conversation of these types
+	ConversationIDs   []string `json:"conversation_ids,omitempty"`
+	Position          int      `json:"position,omitempty"`
+}
+
+func (c *ConversationFolderRequest) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(c.Name) > 64 {
+		return errors.New("name must be at most 64 characters long")
+	}
+	for _, conversationType := range c.ConversationTypes {
+		if conversationType != domain.ConversationTypeGroup && conversationType != domain.ConversationTypeDM {
+			return errors.New("invalid conversation type")
+		}
+	}
+	if len(c.ConversationIDs) > domain.MaxConversationInFolder {
+		return errors.New("too many conversations in folder")
+	}
+	if len(c.ConversationTypes) == 0 && len(c.ConversationIDs) == 0 {
+		return errors.New("conversation_types or conversation_ids is required")
+	}
+	return nil
+}
+
+type ConversationFolderResponse struct {
+	FolderID          string     `json:"folder_id,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	ConversationTypes []string   `json:"conversation_types"`
+	ConversationIDs   []string   `json:"conversation_ids"`
+	Position          int        `json:"position"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,50 @@
+package presenter
+
+import (
+	"errors"
+	"time"
+)
+
+type CreateInviteLinkRequest struct {
+	ConversationID  string     `json:"-"`
+	UserID          string     `json:"-"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	MaxUses         int        `json:"max_uses,omitempty"`
+	RequireApproval bool       `json:"require_approval,omitempty"`
+}
+
+func (c *CreateInviteLinkRequest) Validate() error {
+	if c.ConversationID == "" {
+		return errors.New("conversation_id is required")
+	}
+	if c.MaxUses < 0 {
+		return errors.New("max_uses must not be negative")
+	}
+	if c.ExpiredAt != nil && !c.ExpiredAt.After(time.Now()) {
+		return errors.New("expired_at must be in the future")
+	}
+	return nil
+}
+
+type InviteLinkResponse struct {
+	InviteLinkID    string     `json:"invite_link_id,omitempty"`
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	Token           string     `json:"token,omitempty"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	MaxUses         int        `json:"max_uses"`
+	UsedCount       int        `json:"used_count"`
+	RequireApproval bool       `json:"require_approval"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+}
+
+type InviteLinkPreviewResponse struct {
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	Avatar          string     `json:"avatar,omitempty"`
+	Type            string     `json:"type,omitempty"`
+	MemberCount     int        `json:"member_count"`
+	RequireApproval bool       `json:"require_approval"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	IsMember        bool       `json:"is_member"`
+}
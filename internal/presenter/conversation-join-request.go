@@ -0,0 +1,24 @@
+package presenter
+
+import "time"
+
+type JoinRequestResponse struct {
+	JoinRequestID  string     `json:"join_request_id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	UserID         string     `json:"user_id,omitempty"`
+	FullName       string     `json:"full_name,omitempty"`
+	Avatar         string     `json:"avatar,omitempty"`
+	UserType       string     `json:"user_type,omitempty"`
+	InviteLinkID   *string    `json:"invite_link_id,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	ReviewedBy     *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+// JoinConversationResponse has the conversation when the user joined,
+// or the pending join request when an admin has to approve it first.
+type JoinConversationResponse struct {
+	Conversation *ConversationResponse `json:"conversation,omitempty"`
+	JoinRequest  *JoinRequestResponse  `json:"join_request,omitempty"`
+}
@@ -2,37 +2,78 @@ package presenter
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/chat-socio/backend/internal/domain"
 )
 
 type ConversationResponse struct {
-	ConversationID string                        `json:"conversation_id,omitempty"`
-	Title          string                        `json:"title,omitempty"`
-	Avatar         string                        `json:"avatar,omitempty"`
-	LastMessageID  string                        `json:"last_message_id,omitempty"`
-	CreatedAt      *time.Time                    `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time                    `json:"updated_at,omitempty"`
-	Type           string                        `json:"type,omitempty"`
-	Members        []*ConversationMemberResponse `json:"members,omitempty"`
+	ConversationID    string                        `json:"conversation_id,omitempty"`
+	Title             string                        `json:"title,omitempty"`
+	Avatar            string                        `json:"avatar,omitempty"`
+	LastMessageID     string                        `json:"last_message_id,omitempty"`
+	LastMessageSeq    int64                         `json:"last_message_seq"`
+	CreatedAt         *time.Time                    `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time                    `json:"updated_at,omitempty"`
+	Type              string                        `json:"type,omitempty"`
+	IsPublic          bool                          `json:"is_public,omitempty"`
+	Description       string                        `json:"description,omitempty"`
+	RequireApproval   bool                          `json:"require_approval,omitempty"`
+	SlowModeSeconds   int                           `json:"slow_mode_seconds"`
+	DailyMessageLimit int                           `json:"daily_message_limit"`
+	MemberCount       int                           `json:"member_count,omitempty"`
+	Members           []*ConversationMemberResponse `json:"members,omitempty"` // first page only, see the member list endpoint
+	Membership        *MembershipResponse           `json:"membership,omitempty"`
+}
+
+// MembershipResponse is the state of the conversation for the user fetching it.
+type MembershipResponse struct {
+	Role                string                       `json:"role,omitempty"`
+	JoinedAt            *time.Time                   `json:"joined_at,omitempty"`
+	NotificationSetting *NotificationSettingResponse `json:"notification_setting,omitempty"`
+	ArchivedAt          *time.Time                   `json:"archived_at,omitempty"`
+	PinnedAt            *time.Time                   `json:"pinned_at,omitempty"`
+	HistoryClearedAt    *time.Time                   `json:"history_cleared_at,omitempty"`
 }
 
 type ConversationMemberResponse struct {
-	UserID   string `json:"user_id,omitempty"`
-	FullName string `json:"full_name,omitempty"`
-	Avatar   string `json:"avatar,omitempty"`
-	UserType string `json:"user_type,omitempty"`
+	UserID   string              `json:"user_id,omitempty"`
+	FullName string              `json:"full_name,omitempty"`
+	Avatar   string              `json:"avatar,omitempty"`
+	UserType string              `json:"user_type,omitempty"`
+	Role     string              `json:"role,omitempty"`
+	Status   *UserStatusResponse `json:"status,omitempty"`
 }
 
 type CreateConversationRequest struct {
-	Title   string   `json:"title,omitempty"`
-	Type    string   `json:"type,omitempty"`
-	Avatar  string   `json:"avatar,omitempty"`
-	Members []string `json:"members,omitempty"`
+	UserID          string   `json:"-"` // creator of the conversation
+	Title           string   `json:"title,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	Avatar          string   `json:"avatar,omitempty"`
+	Members         []string `json:"members,omitempty"`
+	IsPublic        bool     `json:"is_public,omitempty"`   // only for channels
+	Description     string   `json:"description,omitempty"` // only for channels
+	RequireApproval bool     `json:"require_approval,omitempty"`
 }
 
 func (c *CreateConversationRequest) Validate() error {
+	if c.Type == domain.ConversationTypeChannel {
+		// a channel starts with its creator, subscribers join later
+		if strings.TrimSpace(c.Title) == "" {
+			return errors.New("title is required for channel")
+		}
+		if len(c.Description) > 1024 {
+			return errors.New("description must be at most 1024 characters long")
+		}
+		return nil
+	}
+	if c.IsPublic {
+		return errors.New("only channels can be public")
+	}
+	if c.RequireApproval && c.Type == domain.ConversationTypeDM {
+		return errors.New("only groups and channels can require approval")
+	}
 	if len(c.Members) < 2 {
 		return errors.New("at least 2 members are required")
 	}
@@ -42,12 +83,52 @@ func (c *CreateConversationRequest) Validate() error {
 	if c.Type == domain.ConversationTypeDM && len(c.Members) != 2 {
 		return errors.New("invalid number of members for DM")
 	}
+	if c.Type == domain.ConversationTypeDM && c.Members[0] == c.Members[1] {
+		return errors.New("members of DM must be different")
+	}
 	if c.Type == domain.ConversationTypeGroup && len(c.Members) < 2 {
 		return errors.New("invalid number of members for group")
 	}
 	return nil
 }
 
+type UpdateConversationRequest struct {
+	ConversationID   string  `json:"-"`
+	UserID           string  `json:"-"`
+	Title            *string `json:"title,omitempty"`
+	AvatarBucketName string  `json:"avatar_bucket_name,omitempty"`
+	AvatarObjectName string  `json:"avatar_object_name,omitempty"`
+	RequireApproval  *bool   `json:"require_approval,omitempty"`
+	// SlowModeSeconds and DailyMessageLimit only apply to groups, 0 disables them
+	SlowModeSeconds   *int `json:"slow_mode_seconds,omitempty"`
+	DailyMessageLimit *int `json:"daily_message_limit,omitempty"`
+}
+
+func (u *UpdateConversationRequest) Validate() error {
+	if u.ConversationID == "" {
+		return errors.New("conversation_id is required")
+	}
+	if u.Title == nil && u.AvatarObjectName == "" && u.RequireApproval == nil && u.SlowModeSeconds == nil && u.DailyMessageLimit == nil {
+		return errors.New("nothing to update")
+	}
+	if u.SlowModeSeconds != nil && (*u.SlowModeSeconds < 0 || *u.SlowModeSeconds > 3600) {
+		return errors.New("slow_mode_seconds must be between 0 and 3600")
+	}
+	if u.DailyMessageLimit != nil && (*u.DailyMessageLimit < 0 || *u.DailyMessageLimit > 10000) {
+		return errors.New("daily_message_limit must be between 0 and 10000")
+	}
+	if u.Title != nil && strings.TrimSpace(*u.Title) == "" {
+		return errors.New("title can not be empty")
+	}
+	if u.Title != nil && len(*u.Title) > 255 {
+		return errors.New("title must be at most 255 characters long")
+	}
+	if u.AvatarObjectName != "" && u.AvatarBucketName == "" {
+		return errors.New("avatar_bucket_name is required")
+	}
+	return nil
+}
+
 type SendMessageRequest struct {
 	ConversationID string `json:"conversation_id,omitempty"`
 	UserID         string `json:"user_id,omitempty"`
@@ -77,31 +158,44 @@ func (s *SendMessageRequest) Validate() error {
 }
 
 type MessageResponse struct {
-	MessageID      string        `json:"message_id,omitempty"`
-	Body           string        `json:"body,omitempty"`
-	CreatedAt      *time.Time    `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time    `json:"updated_at,omitempty"`
-	ConversationID string        `json:"conversation_id,omitempty"`
-	User           *UserResponse `json:"user,omitempty"`
-	Type           string        `json:"type,omitempty"`
-	DeletedAt      *time.Time    `json:"deleted_at,omitempty"`
-	ReplyTo        string        `json:"reply_to,omitempty"`
+	MessageID      string              `json:"message_id,omitempty"`
+	Seq            int64               `json:"seq"`
+	Body           string              `json:"body,omitempty"`
+	CreatedAt      *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time          `json:"updated_at,omitempty"`
+	ConversationID string              `json:"conversation_id,omitempty"`
+	User           *UserResponse       `json:"user,omitempty"`
+	Type           string              `json:"type,omitempty"`
+	DeletedAt      *time.Time          `json:"deleted_at,omitempty"`
+	ReplyTo        string              `json:"reply_to,omitempty"`
+	Reactions      []*ReactionResponse `json:"reactions,omitempty"`
+	ViewCount      *int64              `json:"view_count,omitempty"`     // only for channel posts
+	IsBot          bool                `json:"is_bot,omitempty"`         // sent by a bot
+	SenderBlocked  bool                `json:"sender_blocked,omitempty"` // the caller blocked the sender, clients hide the message
 }
 
 type GetListConversationResponse struct {
-	ConversationID string                        `json:"conversation_id,omitempty"`
-	Title          string                        `json:"title,omitempty"`
-	Avatar         string                        `json:"avatar,omitempty"`
-	LastMessageID  string                        `json:"last_message_id,omitempty"`
-	CreatedAt      *time.Time                    `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time                    `json:"updated_at,omitempty"`
-	Type           string                        `json:"type,omitempty"`
-	LastMessage    *MessageResponse              `json:"last_message,omitempty"`
-	Members        []*ConversationMemberResponse `json:"members,omitempty"`
+	ConversationID      string                        `json:"conversation_id,omitempty"`
+	Title               string                        `json:"title,omitempty"`
+	Avatar              string                        `json:"avatar,omitempty"`
+	LastMessageID       string                        `json:"last_message_id,omitempty"`
+	LastMessageSeq      int64                         `json:"last_message_seq"`
+	CreatedAt           *time.Time                    `json:"created_at,omitempty"`
+	UpdatedAt           *time.Time                    `json:"updated_at,omitempty"`
+	Type                string                        `json:"type,omitempty"`
+	LastMessage         *MessageResponse              `json:"last_message,omitempty"`
+	Members             []*ConversationMemberResponse `json:"members,omitempty"`
+	UnreadCount         int                           `json:"unread_count"`
+	NotificationSetting *NotificationSettingResponse  `json:"notification_setting,omitempty"`
+	ArchivedAt          *time.Time                    `json:"archived_at,omitempty"`
+	PinnedAt            *time.Time                    `json:"pinned_at,omitempty"`
+	SlowModeSeconds     int                           `json:"slow_mode_seconds"`
+	DailyMessageLimit   int                           `json:"daily_message_limit"`
 }
 
 type SeenMessageResponse struct {
 	MessageID      string     `json:"message_id,omitempty"`
+	MessageSeq     int64      `json:"message_seq"`
 	UserID         string     `json:"user_id,omitempty"`
 	ConversationID string     `json:"conversation_id,omitempty"`
 	CreatedAt      *time.Time `json:"created_at,omitempty"`
@@ -112,3 +206,132 @@ type SeenMessageRequest struct {
 	ConversationID string `json:"conversation_id,omitempty"`
 	MessageID      string `json:"message_id,omitempty"`
 }
+
+type NotificationSettingResponse struct {
+	ConversationID    string     `json:"conversation_id,omitempty"`
+	NotificationLevel string     `json:"notification_level,omitempty"`
+	MutedUntil        *time.Time `json:"muted_until,omitempty"`
+	MutedForever      bool       `json:"muted_forever"`
+	IsMuted           bool       `json:"is_muted"`
+}
+
+type UpdateNotificationSettingRequest struct {
+	ConversationID    string     `json:"-"`
+	UserID            string     `json:"-"`
+	NotificationLevel string     `json:"notification_level,omitempty"`
+	MutedUntil        *time.Time `json:"muted_until,omitempty"`
+	MutedForever      bool       `json:"muted_forever,omitempty"`
+}
+
+func (u *UpdateNotificationSettingRequest) Validate() error {
+	if u.ConversationID == "" {
+		return errors.New("conversation_id is required")
+	}
+	if u.NotificationLevel != domain.NotificationLevelAll && u.NotificationLevel != domain.NotificationLevelMentions {
+		return errors.New("invalid notification level")
+	}
+	if u.MutedForever && u.MutedUntil != nil {
+		return errors.New("muted_until and muted_forever can not be set together")
+	}
+	if u.MutedUntil != nil && !u.MutedUntil.After(time.Now()) {
+		return errors.New("muted_until must be in the future")
+	}
+	return nil
+}
+
+type UnreadCountResponse struct {
+	UnreadMessageCount      int `json:"unread_message_count"`
+	UnreadConversationCount int `json:"unread_conversation_count"`
+}
+
+type ReactionRequest struct {
+	MessageID string `json:"-"`
+	UserID    string `json:"-"`
+	Emoji     string `json:"emoji,omitempty" query:"emoji"`
+}
+
+func (r *ReactionRequest) Validate() error {
+	if r.MessageID == "" {
+		return errors.New("message_id is required")
+	}
+	if strings.TrimSpace(r.Emoji) == "" {
+		return errors.New("emoji is required")
+	}
+	if len(r.Emoji) > 32 {
+		return errors.New("emoji must be at most 32 characters long")
+	}
+	return nil
+}
+
+type ReactionResponse struct {
+	Emoji       string `json:"emoji,omitempty"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// RateLimitResponse tells the client when it can send again.
+type RateLimitResponse struct {
+	Reason     string `json:"reason,omitempty"`
+	RetryAfter int    `json:"retry_after"` // seconds
+}
+
+// PrivacyRefusedResponse lists the users whose privacy settings refused the action.
+type PrivacyRefusedResponse struct {
+	Reason  string   `json:"reason,omitempty"`
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+type GetListMessageRequest struct {
+	ConversationID string
+	UserID         string
+	LastMessageID  string // same as the seq of this message in BeforeSeq
+	BeforeSeq      int64  // page of the messages before this seq, newest first
+	AfterSeq       int64  // page of the messages after this seq, oldest first, to fill a gap
+	Limit          int
+}
+
+func (g *GetListMessageRequest) Validate() error {
+	if g.ConversationID == "" {
+		return errors.New("conversation_id is required")
+	}
+	if g.BeforeSeq < 0 || g.AfterSeq < 0 {
+		return errors.New("before_seq and after_seq must be positive")
+	}
+	if g.AfterSeq > 0 && (g.BeforeSeq > 0 || g.LastMessageID != "") {
+		return errors.New("after_seq cannot be used with before_seq or last_message_id")
+	}
+	if g.Limit <= 0 || g.Limit > 100 {
+		return errors.New("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+type GetListAttachmentRequest struct {
+	ConversationID string
+	UserID         string
+	Kind           string
+	SenderID       string // only the attachments of this sender when set
+	LastID         string
+	Limit          int
+}
+
+func (g *GetListAttachmentRequest) Validate() error {
+	if g.ConversationID == "" {
+		return errors.New("conversation_id is required")
+	}
+	if g.Limit <= 0 || g.Limit > 100 {
+		return errors.New("limit must be between 1 and 100")
+	}
+	return nil
+}
+
+type MessageAttachmentResponse struct {
+	AttachmentID   string     `json:"attachment_id,omitempty"`
+	MessageID      string     `json:"message_id,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	SenderID       string     `json:"sender_id,omitempty"`
+	Kind           string     `json:"kind,omitempty"`
+	MessageType    string     `json:"message_type,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
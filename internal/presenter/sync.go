@@ -0,0 +1,17 @@
+package presenter
+
+import "time"
+
+type SyncEventResponse struct {
+	Type      string         `json:"type,omitempty"`
+	Payload   map[string]any `json:"payload,omitempty"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+}
+
+// SyncResponse is the delta since the cursor sent by the client. When ResyncRequired is set the events are
+// empty, the client reloads its state then syncs from the returned cursor.
+type SyncResponse struct {
+	Cursor         string               `json:"cursor"`
+	ResyncRequired bool                 `json:"resync_required"`
+	Events         []*SyncEventResponse `json:"events"`
+}
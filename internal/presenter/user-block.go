@@ -0,0 +1,11 @@
+package presenter
+
+import "time"
+
+type BlockedUserResponse struct {
+	UserID    string     `json:"user_id,omitempty"`
+	FullName  string     `json:"full_name,omitempty"`
+	Avatar    string     `json:"avatar,omitempty"`
+	UserType  string     `json:"user_type,omitempty"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"`
+}
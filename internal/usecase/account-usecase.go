@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/hash"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/storage"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	dataExportMessagePageSize = 1000
+	// maxAccountDeletionPerRun bounds the accounts anonymized by one run of the purge
+	maxAccountDeletionPerRun = 1000
+)
+
+type AccountUseCase interface {
+	RequestDataExport(ctx context.Context, accountID string) (*presenter.DataExportResponse, error)
+	GetListDataExport(ctx context.Context, accountID string) ([]*presenter.DataExportResponse, error)
+	HandleDataExport(ctx context.Context, dataExport *domain.DataExport) error
+	ScheduleAccountDeletion(ctx context.Context, accountID string, request *presenter.DeleteAccountRequest) (*presenter.AccountDeletionResponse, error)
+	CancelAccountDeletion(ctx context.Context, accountID string) error
+	// DeleteScheduledAccount anonymizes the accounts whose grace period is over and returns how many were.
+	DeleteScheduledAccount(ctx context.Context, now time.Time) (int, error)
+}
+
+type accountUseCase struct {
+	accountRepository      domain.AccountRepository
+	userRepository         domain.UserRepository
+	sessionRepository      domain.SessionRepository
+	sessionCacheRepository domain.SessionCacheRepository
+	userCacheRepository    domain.UserCacheRepository
+	conversationRepository domain.ConversationRepository
+	messageRepository      domain.MessageRepository
+	dataExportRepository   domain.DataExportRepository
+	objectStorage          storage.ObjectStorage
+	messagePublisher       pubsub.Publisher
+	obs                    *observability.Observability
+}
+
+func NewAccountUseCase(accountRepository domain.AccountRepository, userRepository domain.UserRepository, sessionRepository domain.SessionRepository, sessionCacheRepository domain.SessionCacheRepository, userCacheRepository domain.UserCacheRepository, conversationRepository domain.ConversationRepository, messageRepository domain.MessageRepository, dataExportRepository domain.DataExportRepository, objectStorage storage.ObjectStorage, messagePublisher pubsub.Publisher, obs *observability.Observability) AccountUseCase {
+	return &accountUseCase{
+		accountRepository:      accountRepository,
+		userRepository:         userRepository,
+		sessionRepository:      sessionRepository,
+		sessionCacheRepository: sessionCacheRepository,
+		userCacheRepository:    userCacheRepository,
+		conversationRepository: conversationRepository,
+		messageRepository:      messageRepository,
+		dataExportRepository:   dataExportRepository,
+		objectStorage:          objectStorage,
+		messagePublisher:       messagePublisher,
+		obs:                    obs,
+	}
+}
+
+func (a *accountUseCase) newDataExportResponse(ctx context.Context, dataExport *domain.DataExport) (*presenter.DataExportResponse, error) {
+	response := &presenter.DataExportResponse{
+		DataExportID: dataExport.ID,
+		Status:       dataExport.Status,
+		CreatedAt:    dataExport.CreatedAt,
+		CompletedAt:  dataExport.CompletedAt,
+	}
+	if dataExport.Status == domain.DataExportStatusCompleted {
+		url, err := a.objectStorage.GetObjectURL(ctx, dataExport.BucketName, dataExport.ObjectName, domain.DataExportURLExpiration)
+		if err != nil {
+			return nil, err
+		}
+		response.DownloadURL = url
+	}
+	return response, nil
+}
+
+// RequestDataExport implements AccountUseCase.
+func (a *accountUseCase) RequestDataExport(ctx context.Context, accountID string) (*presenter.DataExportResponse, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AccountUsecase.RequestDataExport")
+	defer span()
+
+	dataExports, err := a.dataExportRepository.GetListDataExportByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// the list is newest first, a failed export can be retried right away
+	if len(dataExports) > 0 && dataExports[0].Status != domain.DataExportStatusFailed &&
+		dataExports[0].CreatedAt != nil && now.Sub(*dataExports[0].CreatedAt) < domain.DataExportInterval {
+		return nil, domain.ErrDataExportRateLimited
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	dataExport := &domain.DataExport{
+		ID:        id,
+		AccountID: accountID,
+		Status:    domain.DataExportStatusPending,
+		CreatedAt: &now,
+	}
+	err = a.dataExportRepository.CreateDataExport(ctx, dataExport)
+	if err != nil {
+		return nil, err
+	}
+
+	// the archive is built by a worker, the user is told once it is ready
+	err = a.messagePublisher.Publish(ctx, domain.SUBJECT_DATA_EXPORT, dataExport)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.newDataExportResponse(ctx, dataExport)
+}
+
+// GetListDataExport implements AccountUseCase.
+func (a *accountUseCase) GetListDataExport(ctx context.Context, accountID string) ([]*presenter.DataExportResponse, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AccountUsecase.GetListDataExport")
+	defer span()
+
+	dataExports, err := a.dataExportRepository.GetListDataExportByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*presenter.DataExportResponse, 0, len(dataExports))
+	for _, dataExport := range dataExports {
+		response, err := a.newDataExportResponse(ctx, dataExport)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// HandleDataExport implements AccountUseCase.
+func (a *accountUseCase) HandleDataExport(ctx context.Context, dataExport *domain.DataExport) error {
+	logger := a.obs.Logger.WithContext(ctx)
+
+	user, err := a.userRepository.GetUserByAccountID(ctx, dataExport.AccountID)
+	if err != nil {
+		logger.Error("error get user by account id", err, dataExport)
+		return err
+	}
+
+	// the archive holds every message of the user, it is written to a temporary file rather than kept in memory
+	archive, err := os.CreateTemp("", "data-export-*.zip")
+	if err == nil {
+		defer os.Remove(archive.Name())
+		defer archive.Close()
+		err = a.buildDataExportArchive(ctx, dataExport.AccountID, user, archive)
+	}
+	if err == nil {
+		dataExport.BucketName = domain.DataExportBucketName
+		dataExport.ObjectName = fmt.Sprintf("%s/%s.zip", dataExport.AccountID, dataExport.ID)
+		err = a.putDataExportArchive(ctx, dataExport, archive)
+	}
+	dataExport.CompletedAt = pointer.ToPtr(time.Now())
+	if err != nil {
+		// the job is not retried, the user can ask for another export
+		logger.Error("error build data export", err, dataExport)
+		dataExport.Status = domain.DataExportStatusFailed
+		dataExport.Error = err.Error()
+		dataExport.BucketName = ""
+		dataExport.ObjectName = ""
+	} else {
+		dataExport.Status = domain.DataExportStatusCompleted
+	}
+
+	err = a.dataExportRepository.UpdateDataExport(ctx, dataExport)
+	if err != nil {
+		logger.Error("error update data export", err, dataExport)
+		return err
+	}
+
+	err = publishUserEvent(ctx, a.messagePublisher, domain.WsDataExportUpdated, map[string]any{
+		"data_export_id": dataExport.ID,
+		"status":         dataExport.Status,
+		"completed_at":   dataExport.CompletedAt,
+	}, user.ID)
+	if err != nil {
+		logger.Error("error publish data export updated", err, dataExport)
+	}
+	return nil
+}
+
+func (a *accountUseCase) putDataExportArchive(ctx context.Context, dataExport *domain.DataExport, archive *os.File) error {
+	size, err := archive.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	exists, err := a.objectStorage.BucketExists(ctx, dataExport.BucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = a.objectStorage.MakeBucket(ctx, dataExport.BucketName)
+		if err != nil {
+			return err
+		}
+	}
+	return a.objectStorage.PutObject(ctx, dataExport.BucketName, dataExport.ObjectName, archive, size)
+}
+
+// buildDataExportArchive writes the data of the account as JSON files in a zip archive.
+func (a *accountUseCase) buildDataExportArchive(ctx context.Context, accountID string, user *domain.UserInfo, archive io.Writer) error {
+	account, err := a.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	sessions, err := a.sessionRepository.GetListSessionByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	// the tokens of the sessions are credentials, they are left out
+	sessionData := make([]map[string]any, 0, len(sessions))
+	for _, session := range sessions {
+		sessionData = append(sessionData, map[string]any{
+			"created_at": session.CreatedAt,
+			"expired_at": session.ExpiredAt,
+			"user_agent": session.UserAgent,
+			"ip_address": session.IPAddress,
+		})
+	}
+	conversations, err := a.conversationRepository.GetListJoinedConversation(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	zipWriter := zip.NewWriter(archive)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"account.json", account},
+		{"profile.json", user},
+		{"sessions.json", sessionData},
+		{"conversations.json", conversations},
+	}
+	for _, file := range files {
+		w, err := zipWriter.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	// the messages are written page by page, one JSON object per line
+	w, err := zipWriter.Create("messages.jsonl")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	lastID := ""
+	for {
+		messages, err := a.messageRepository.GetListMessageByUserID(ctx, user.ID, lastID, dataExportMessagePageSize)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if err := encoder.Encode(message); err != nil {
+				return err
+			}
+		}
+		if len(messages) < dataExportMessagePageSize {
+			break
+		}
+		lastID = messages[len(messages)-1].ID
+	}
+
+	return zipWriter.Close()
+}
+
+// ScheduleAccountDeletion implements AccountUseCase.
+func (a *accountUseCase) ScheduleAccountDeletion(ctx context.Context, accountID string, request *presenter.DeleteAccountRequest) (*presenter.AccountDeletionResponse, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AccountUsecase.ScheduleAccountDeletion")
+	defer span()
+
+	account, err := a.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFoundAccount
+	}
+	if !hash.CheckPasswordHash(request.Password, account.Password) {
+		return nil, ErrWrongPassword
+	}
+
+	// asking again keeps the first date
+	if account.DeletionScheduledAt != nil {
+		return &presenter.AccountDeletionResponse{DeletionScheduledAt: account.DeletionScheduledAt}, nil
+	}
+
+	deletionScheduledAt := time.Now().Add(domain.AccountDeletionGracePeriod)
+	err = a.accountRepository.ScheduleAccountDeletion(ctx, account.ID, &deletionScheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	return &presenter.AccountDeletionResponse{DeletionScheduledAt: &deletionScheduledAt}, nil
+}
+
+// CancelAccountDeletion implements AccountUseCase.
+func (a *accountUseCase) CancelAccountDeletion(ctx context.Context, accountID string) error {
+	ctx, span := a.obs.StartSpan(ctx, "AccountUsecase.CancelAccountDeletion")
+	defer span()
+
+	account, err := a.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows {
+		return ErrNotFoundAccount
+	}
+	if account.DeletionScheduledAt == nil {
+		return domain.ErrAccountDeletionNotScheduled
+	}
+	return a.accountRepository.ScheduleAccountDeletion(ctx, account.ID, nil)
+}
+
+// DeleteScheduledAccount implements AccountUseCase.
+func (a *accountUseCase) DeleteScheduledAccount(ctx context.Context, now time.Time) (int, error) {
+	logger := a.obs.Logger.WithContext(ctx)
+
+	accountIDs, err := a.accountRepository.GetListAccountIDToDelete(ctx, now, maxAccountDeletionPerRun)
+	if err != nil {
+		return 0, err
+	}
+
+	// an account failing is left for the next run
+	deleted := 0
+	for _, accountID := range accountIDs {
+		err := a.deleteAccount(ctx, accountID, now)
+		if err != nil {
+			logger.Error("error delete account", err, accountID)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (a *accountUseCase) deleteAccount(ctx context.Context, accountID string, now time.Time) error {
+	logger := a.obs.Logger.WithContext(ctx)
+
+	user, err := a.userRepository.GetUserByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	// read before the anonymization drops the contacts
+	relatedUserIDs, err := a.userRepository.GetListRelatedUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	sessions, err := a.sessionRepository.GetListSessionByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	dataExports, err := a.dataExportRepository.GetListDataExportByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	for _, dataExport := range dataExports {
+		if dataExport.ObjectName == "" {
+			continue
+		}
+		err = a.objectStorage.DeleteObject(ctx, dataExport.BucketName, dataExport.ObjectName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = a.accountRepository.AnonymizeAccount(ctx, accountID, now)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		err = a.sessionCacheRepository.DeleteSession(ctx, session.SessionToken)
+		if err != nil {
+			return err
+		}
+	}
+	err = a.userCacheRepository.DeleteUserIDByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	if len(relatedUserIDs) == 0 {
+		return nil
+	}
+	// the other participants refresh the user they cached
+	err = publishUserEvent(ctx, a.messagePublisher, domain.WsUserProfileUpdated, map[string]any{
+		"user_id":    user.ID,
+		"full_name":  domain.DeletedUserFullName,
+		"avatar":     "",
+		"handle":     "",
+		"bio":        "",
+		"updated_at": now,
+	}, relatedUserIDs...)
+	if err != nil {
+		logger.Error("error publish profile updated", err, user.ID)
+	}
+	return nil
+}
+
+var _ AccountUseCase = (*accountUseCase)(nil)
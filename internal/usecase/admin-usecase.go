@@ -0,0 +1,369 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/mailer"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/random"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+// AdminUseCase is the moderation of the platform, every action is written to the audit log.
+type AdminUseCase interface {
+	GetListUser(ctx context.Context, adminAccountID string, keyword string, lastID string, limit int) ([]*presenter.AdminUserResponse, error)
+	GetListSession(ctx context.Context, adminAccountID string, accountID string) ([]*presenter.AdminSessionResponse, error)
+	// SuspendAccount, BanAccount and ResetPassword revoke the sessions of the account and close its websockets.
+	SuspendAccount(ctx context.Context, request *presenter.SuspendAccountRequest) error
+	BanAccount(ctx context.Context, request *presenter.AdminActionRequest) error
+	// ReinstateAccount lifts the suspension and the ban of the account.
+	ReinstateAccount(ctx context.Context, request *presenter.AdminActionRequest) error
+	// ResetPassword removes the password of the account and emails the owner a link to choose a new one.
+	ResetPassword(ctx context.Context, request *presenter.AdminActionRequest) error
+	DeleteConversation(ctx context.Context, request *presenter.AdminActionRequest) error
+	GetListAuditLog(ctx context.Context, targetType string, targetID string, lastID string, limit int) ([]*domain.AdminAuditLog, error)
+}
+
+type adminUseCase struct {
+	adminRepository              domain.AdminRepository
+	accountRepository            domain.AccountRepository
+	userRepository               domain.UserRepository
+	sessionRepository            domain.SessionRepository
+	sessionCacheRepository       domain.SessionCacheRepository
+	passwordResetTokenRepository domain.PasswordResetTokenRepository
+	messagePublisher             pubsub.Publisher
+	mailer                       mailer.Mailer
+	obs                          *observability.Observability
+}
+
+func NewAdminUseCase(adminRepository domain.AdminRepository, accountRepository domain.AccountRepository, userRepository domain.UserRepository, sessionRepository domain.SessionRepository, sessionCacheRepository domain.SessionCacheRepository, passwordResetTokenRepository domain.PasswordResetTokenRepository, messagePublisher pubsub.Publisher, mailer mailer.Mailer, obs *observability.Observability) AdminUseCase {
+	return &adminUseCase{
+		adminRepository:              adminRepository,
+		accountRepository:            accountRepository,
+		userRepository:               userRepository,
+		sessionRepository:            sessionRepository,
+		sessionCacheRepository:       sessionCacheRepository,
+		passwordResetTokenRepository: passwordResetTokenRepository,
+		messagePublisher:             messagePublisher,
+		mailer:                       mailer,
+		obs:                          obs,
+	}
+}
+
+func newAdminAuditLog(adminAccountID string, action string, targetType string, targetID string, detail map[string]any) (*domain.AdminAuditLog, error) {
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AdminAuditLog{
+		ID:             id,
+		AdminAccountID: adminAccountID,
+		Action:         action,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Detail:         detail,
+		CreatedAt:      pointer.ToPtr(time.Now()),
+	}, nil
+}
+
+// GetListUser implements AdminUseCase.
+func (a *adminUseCase) GetListUser(ctx context.Context, adminAccountID string, keyword string, lastID string, limit int) ([]*presenter.AdminUserResponse, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.GetListUser")
+	defer span()
+
+	auditLog, err := newAdminAuditLog(adminAccountID, domain.AdminActionListUser, domain.AdminTargetUser, "", map[string]any{
+		"keyword": keyword,
+		"last_id": lastID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = a.adminRepository.CreateAdminAuditLog(ctx, auditLog)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := a.adminRepository.GetListAccountWithUser(ctx, keyword, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*presenter.AdminUserResponse, 0, len(accounts))
+	for _, account := range accounts {
+		responses = append(responses, &presenter.AdminUserResponse{
+			AccountID:           account.Account.ID,
+			UserID:              account.User.ID,
+			Type:                account.User.Type,
+			Email:               account.User.Email,
+			FullName:            account.User.FullName,
+			Avatar:              account.User.Avatar,
+			Handle:              account.User.Handle,
+			Role:                account.Account.Role,
+			EmailVerified:       account.Account.IsEmailVerified(),
+			SuspendedUntil:      account.Account.SuspendedUntil,
+			BannedAt:            account.Account.BannedAt,
+			RestrictionReason:   account.Account.RestrictionReason,
+			DeletionScheduledAt: account.Account.DeletionScheduledAt,
+			CreatedAt:           account.Account.CreatedAt,
+			DeletedAt:           account.Account.DeletedAt,
+		})
+	}
+	return responses, nil
+}
+
+// GetListSession implements AdminUseCase.
+func (a *adminUseCase) GetListSession(ctx context.Context, adminAccountID string, accountID string) ([]*presenter.AdminSessionResponse, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.GetListSession")
+	defer span()
+
+	_, err := a.getAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	auditLog, err := newAdminAuditLog(adminAccountID, domain.AdminActionListSession, domain.AdminTargetAccount, accountID, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = a.adminRepository.CreateAdminAuditLog(ctx, auditLog)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := a.sessionRepository.GetListSessionByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*presenter.AdminSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, &presenter.AdminSessionResponse{
+			CreatedAt: session.CreatedAt,
+			UpdatedAt: session.UpdatedAt,
+			ExpiredAt: session.ExpiredAt,
+			IsActive:  session.IsActive != nil && *session.IsActive,
+			UserAgent: session.UserAgent,
+			IPAddress: session.IPAddress,
+		})
+	}
+	return responses, nil
+}
+
+// getAccount returns ErrAccountNotFound for the unknown and the deleted accounts.
+func (a *adminUseCase) getAccount(ctx context.Context, accountID string) (*domain.Account, error) {
+	account, err := a.accountRepository.GetAccountByID(ctx, accountID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if account.DeletedAt != nil {
+		return nil, domain.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// SuspendAccount implements AdminUseCase.
+func (a *adminUseCase) SuspendAccount(ctx context.Context, request *presenter.SuspendAccountRequest) error {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.SuspendAccount")
+	defer span()
+
+	account, err := a.getAccount(ctx, request.TargetID)
+	if err != nil {
+		return err
+	}
+	if account.IsAdmin() {
+		return domain.ErrAdminTargetAdmin
+	}
+	account.SuspendedUntil = request.SuspendedUntil
+	return a.updateAccountRestriction(ctx, account, domain.AdminActionSuspendAccount, &request.AdminActionRequest)
+}
+
+// BanAccount implements AdminUseCase.
+func (a *adminUseCase) BanAccount(ctx context.Context, request *presenter.AdminActionRequest) error {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.BanAccount")
+	defer span()
+
+	account, err := a.getAccount(ctx, request.TargetID)
+	if err != nil {
+		return err
+	}
+	if account.IsAdmin() {
+		return domain.ErrAdminTargetAdmin
+	}
+	account.BannedAt = pointer.ToPtr(time.Now())
+	return a.updateAccountRestriction(ctx, account, domain.AdminActionBanAccount, request)
+}
+
+// ReinstateAccount implements AdminUseCase.
+func (a *adminUseCase) ReinstateAccount(ctx context.Context, request *presenter.AdminActionRequest) error {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.ReinstateAccount")
+	defer span()
+
+	account, err := a.getAccount(ctx, request.TargetID)
+	if err != nil {
+		return err
+	}
+	if account.SuspendedUntil == nil && account.BannedAt == nil {
+		return domain.ErrAccountNotRestricted
+	}
+	account.SuspendedUntil = nil
+	account.BannedAt = nil
+	return a.updateAccountRestriction(ctx, account, domain.AdminActionReinstateAccount, request)
+}
+
+// updateAccountRestriction saves the restriction with its audit log, and disconnects the account when it is restricted.
+func (a *adminUseCase) updateAccountRestriction(ctx context.Context, account *domain.Account, action string, request *presenter.AdminActionRequest) error {
+	account.RestrictionReason = request.Reason
+	account.UpdatedAt = pointer.ToPtr(time.Now())
+	auditLog, err := newAdminAuditLog(request.AdminAccountID, action, domain.AdminTargetAccount, account.ID, map[string]any{
+		"reason":          request.Reason,
+		"suspended_until": account.SuspendedUntil,
+		"banned_at":       account.BannedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the sessions are listed before they are deactivated to drop them from the cache
+	sessions, err := a.sessionRepository.GetListSessionByAccountID(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	err = a.adminRepository.UpdateAccountRestriction(ctx, account, auditLog)
+	if err != nil {
+		return err
+	}
+	if action == domain.AdminActionReinstateAccount {
+		return nil
+	}
+	return a.revokeSessions(ctx, account.ID, sessions, action)
+}
+
+// revokeSessions drops the deactivated sessions from the cache and closes the websockets of the account.
+func (a *adminUseCase) revokeSessions(ctx context.Context, accountID string, sessions []*domain.Session, action string) error {
+	for _, session := range sessions {
+		err := a.sessionCacheRepository.DeleteSession(ctx, session.SessionToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	user, err := a.userRepository.GetUserByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	err = publishUserEvent(ctx, a.messagePublisher, domain.WsSessionRevoked, map[string]any{
+		"reason": action,
+	}, user.ID)
+	if err != nil {
+		a.obs.Logger.WithContext(ctx).Error("failed to publish session revoked", err, accountID)
+	}
+	return nil
+}
+
+// ResetPassword implements AdminUseCase.
+func (a *adminUseCase) ResetPassword(ctx context.Context, request *presenter.AdminActionRequest) error {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.ResetPassword")
+	defer span()
+
+	account, err := a.getAccount(ctx, request.TargetID)
+	if err != nil {
+		return err
+	}
+	auditLog, err := newAdminAuditLog(request.AdminAccountID, domain.AdminActionResetPassword, domain.AdminTargetAccount, account.ID, map[string]any{
+		"reason": request.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	sessions, err := a.sessionRepository.GetListSessionByAccountID(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	err = a.adminRepository.ResetPassword(ctx, account.ID, auditLog)
+	if err != nil {
+		return err
+	}
+	err = a.revokeSessions(ctx, account.ID, sessions, domain.AdminActionResetPassword)
+	if err != nil {
+		return err
+	}
+
+	token, err := random.NewToken(passwordResetTokenLength)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewID()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = a.passwordResetTokenRepository.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		ID:        id,
+		AccountID: account.ID,
+		TokenHash: domain.HashToken(token),
+		ExpiredAt: pointer.ToPtr(now.Add(domain.PasswordResetTokenTTL)),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      []string{account.Username},
+		Subject: "Your password was reset",
+		Body: fmt.Sprintf("An administrator reset the password of your account and signed out your devices.\n\n"+
+			"Open the link below within %d minutes to choose a new password:\n%s?token=%s\n\n"+
+			"Once it expired, use \"Forgot password\" to get a new link.",
+			int(domain.PasswordResetTokenTTL.Minutes()), mailerConfig().ResetPasswordURL, token),
+	})
+}
+
+// DeleteConversation implements AdminUseCase.
+func (a *adminUseCase) DeleteConversation(ctx context.Context, request *presenter.AdminActionRequest) error {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.DeleteConversation")
+	defer span()
+
+	now := time.Now()
+	auditLog, err := newAdminAuditLog(request.AdminAccountID, domain.AdminActionDeleteConversation, domain.AdminTargetConversation, request.TargetID, map[string]any{
+		"reason": request.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	deleted, err := a.adminRepository.SoftDeleteConversation(ctx, request.TargetID, now, auditLog)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domain.ErrConversationNotFound
+	}
+
+	err = a.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, &domain.WebSocketMessage{
+		Type: domain.WsConversationDeleted,
+		Payload: map[string]any{
+			"conversation_id": request.TargetID,
+			"deleted_at":      now,
+		},
+	})
+	if err != nil {
+		a.obs.Logger.WithContext(ctx).Error("failed to publish conversation deleted", err, request)
+	}
+	return nil
+}
+
+// GetListAuditLog implements AdminUseCase.
+func (a *adminUseCase) GetListAuditLog(ctx context.Context, targetType string, targetID string, lastID string, limit int) ([]*domain.AdminAuditLog, error) {
+	ctx, span := a.obs.StartSpan(ctx, "AdminUsecase.GetListAuditLog")
+	defer span()
+
+	return a.adminRepository.GetListAdminAuditLog(ctx, targetType, targetID, lastID, limit)
+}
+
+var _ AdminUseCase = &adminUseCase{}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+)
+
+// userAvatars collects the avatars sent to a viewer by the user they belong to,
+// so the avatar privacy of all of them is checked with a couple of queries.
+type userAvatars map[string][]*string
+
+func (a userAvatars) add(userID string, avatar *string) {
+	if userID == "" || *avatar == "" {
+		return
+	}
+	a[userID] = append(a[userID], avatar)
+}
+
+// hideAvatars blanks the avatars the viewer is not allowed to see by the avatar privacy of their users,
+// the viewer always sees their own avatar.
+func hideAvatars(ctx context.Context, userRepository domain.UserRepository, contactRepository domain.ContactRepository, viewerID string, avatars userAvatars) error {
+	delete(avatars, viewerID)
+	if len(avatars) == 0 {
+		return nil
+	}
+	userIDs := make([]string, 0, len(avatars))
+	for userID := range avatars {
+		userIDs = append(userIDs, userID)
+	}
+	users, err := userRepository.GetListUserByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	var contactsOnlyUserIDs []string
+	for _, user := range users {
+		if user.AvatarPrivacy == domain.PrivacyContacts {
+			contactsOnlyUserIDs = append(contactsOnlyUserIDs, user.ID)
+		}
+	}
+	var friendIDs []string
+	if len(contactsOnlyUserIDs) > 0 {
+		friendIDs, err = contactRepository.GetListFriendID(ctx, viewerID, contactsOnlyUserIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, user := range users {
+		if domain.PrivacyAllows(user.AvatarPrivacy, slices.Contains(friendIDs, user.ID)) {
+			continue
+		}
+		for _, avatar := range avatars[user.ID] {
+			*avatar = ""
+		}
+	}
+	return nil
+}
+
+// publicAvatar returns the avatar of the user when anybody may see it,
+// it is used for the payloads shared by viewers who are not all contacts of the user.
+func publicAvatar(user *domain.UserInfo) string {
+	if !domain.PrivacyAllows(user.AvatarPrivacy, false) {
+		return ""
+	}
+	return user.Avatar
+}
+
+// hideMemberAvatars blanks the avatars of the members the viewer is not allowed to see.
+func hideMemberAvatars(ctx context.Context, userRepository domain.UserRepository, contactRepository domain.ContactRepository, viewerID string, members []*presenter.ConversationMemberResponse) error {
+	avatars := userAvatars{}
+	for _, member := range members {
+		avatars.add(member.UserID, &member.Avatar)
+	}
+	return hideAvatars(ctx, userRepository, contactRepository, viewerID, avatars)
+}
+
+// hideUserAvatars blanks the avatars of the users the viewer is not allowed to see.
+func hideUserAvatars(ctx context.Context, userRepository domain.UserRepository, contactRepository domain.ContactRepository, viewerID string, users ...*presenter.UserResponse) error {
+	avatars := userAvatars{}
+	for _, user := range users {
+		if user != nil {
+			avatars.add(user.UserID, &user.Avatar)
+		}
+	}
+	return hideAvatars(ctx, userRepository, contactRepository, viewerID, avatars)
+}
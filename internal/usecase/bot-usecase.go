@@ -0,0 +1,464 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/random"
+	"github.com/chat-socio/backend/pkg/storage"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pkg/webhook"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	botTokenLength         = 32
+	botWebhookSecretLength = 32
+)
+
+type BotUseCase interface {
+	// CreateBot creates the bot with a first token, the token is only returned once.
+	CreateBot(ctx context.Context, request *presenter.CreateBotRequest) (*presenter.CreateBotResponse, error)
+	GetListBot(ctx context.Context, ownerID string) ([]*presenter.BotResponse, error)
+	UpdateBot(ctx context.Context, request *presenter.UpdateBotRequest) (*presenter.BotResponse, error)
+	DeleteBot(ctx context.Context, ownerID string, botID string) error
+	CreateBotToken(ctx context.Context, ownerID string, botID string, request *presenter.CreateBotTokenRequest) (*presenter.BotTokenResponse, error)
+	GetListBotToken(ctx context.Context, ownerID string, botID string) ([]*presenter.BotTokenResponse, error)
+	RevokeBotToken(ctx context.Context, ownerID string, botID string, tokenID string) error
+	// GetAccountIDByBotToken returns the account of the bot authenticated by the token, or ErrBotTokenInvalid.
+	GetAccountIDByBotToken(ctx context.Context, token string) (string, error)
+	// HandleBotWebhook posts the websocket events of the bots to their webhook.
+	HandleBotWebhook(ctx context.Context, message *domain.WebSocketMessage) error
+}
+
+type botUseCase struct {
+	botRepository    domain.BotRepository
+	userRepository   domain.UserRepository
+	webhookSender    webhook.Sender
+	objectStorage    storage.ObjectStorage
+	messagePublisher pubsub.Publisher
+	obs              *observability.Observability
+}
+
+func NewBotUseCase(botRepository domain.BotRepository, userRepository domain.UserRepository, webhookSender webhook.Sender, objectStorage storage.ObjectStorage, messagePublisher pubsub.Publisher, obs *observability.Observability) BotUseCase {
+	return &botUseCase{
+		botRepository:    botRepository,
+		userRepository:   userRepository,
+		webhookSender:    webhookSender,
+		objectStorage:    objectStorage,
+		messagePublisher: messagePublisher,
+		obs:              obs,
+	}
+}
+
+func newBotResponse(bot *domain.Bot, user *domain.UserInfo) *presenter.BotResponse {
+	return &presenter.BotResponse{
+		BotID:         bot.ID,
+		UserID:        bot.UserID,
+		FullName:      user.FullName,
+		Avatar:        user.Avatar,
+		WebhookURL:    bot.WebhookURL,
+		WebhookSecret: bot.WebhookSecret,
+		CreatedAt:     bot.CreatedAt,
+		UpdatedAt:     bot.UpdatedAt,
+	}
+}
+
+func newBotTokenResponse(botToken *domain.BotToken) *presenter.BotTokenResponse {
+	return &presenter.BotTokenResponse{
+		TokenID:    botToken.ID,
+		Name:       botToken.Name,
+		CreatedAt:  botToken.CreatedAt,
+		LastUsedAt: botToken.LastUsedAt,
+		RevokedAt:  botToken.RevokedAt,
+	}
+}
+
+// checkWebhookURL resolves the host of the webhook, which must not point inside the network of the server.
+// The sender checks the address again when it connects, the host may resolve differently by then.
+func checkWebhookURL(ctx context.Context, webhookURL string) error {
+	if webhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, domain.BotWebhookTimeout)
+	defer cancel()
+	err = webhook.CheckHost(ctx, u.Hostname())
+	if err != nil {
+		// a host which can not be resolved gets no event either
+		return domain.ErrBotWebhookNotPublic
+	}
+	return nil
+}
+
+// CreateBot implements BotUseCase.
+func (b *botUseCase) CreateBot(ctx context.Context, request *presenter.CreateBotRequest) (*presenter.CreateBotResponse, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.CreateBot")
+	defer span()
+
+	count, err := b.botRepository.CountBotByOwnerID(ctx, request.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxBotPerOwner {
+		return nil, domain.ErrBotLimitReached
+	}
+	err = checkWebhookURL(ctx, request.WebhookURL)
+	if err != nil {
+		return nil, err
+	}
+	var avatar string
+	if request.AvatarObjectName != "" {
+		avatar, err = getUploadedObjectURL(ctx, b.objectStorage, request.AvatarBucketName, request.AvatarObjectName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	botID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	webhookSecret, err := random.NewToken(botWebhookSecretLength)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// the account has no password, so nobody can log in as the bot
+	account := &domain.Account{
+		ID:              accountID,
+		Username:        "bot:" + botID,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
+		EmailVerifiedAt: &now,
+	}
+	user := &domain.UserInfo{
+		ID:                   userID,
+		AccountID:            accountID,
+		Type:                 domain.InternalUserType,
+		Email:                "bot:" + botID,
+		FullName:             request.FullName,
+		Avatar:               avatar,
+		LastSeenPrivacy:      domain.PrivacyEveryone,
+		AvatarPrivacy:        domain.PrivacyEveryone,
+		DirectMessagePrivacy: domain.PrivacyEveryone,
+		GroupAddPrivacy:      domain.PrivacyEveryone,
+		CreatedAt:            &now,
+		UpdatedAt:            &now,
+	}
+	bot := &domain.Bot{
+		ID:            botID,
+		UserID:        userID,
+		OwnerID:       request.OwnerID,
+		WebhookURL:    request.WebhookURL,
+		WebhookSecret: webhookSecret,
+		CreatedAt:     &now,
+		UpdatedAt:     &now,
+	}
+	err = b.botRepository.CreateBot(ctx, account, user, bot)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := b.createBotToken(ctx, botID, "default")
+	if err != nil {
+		return nil, err
+	}
+	return &presenter.CreateBotResponse{
+		Bot:   newBotResponse(bot, user),
+		Token: token,
+	}, nil
+}
+
+func (b *botUseCase) createBotToken(ctx context.Context, botID string, name string) (*presenter.BotTokenResponse, error) {
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	token, err := random.NewToken(botTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	botToken := &domain.BotToken{
+		ID:        id,
+		BotID:     botID,
+		Name:      name,
+		TokenHash: domain.HashToken(token),
+		CreatedAt: pointer.ToPtr(time.Now()),
+	}
+	err = b.botRepository.CreateBotToken(ctx, botToken)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newBotTokenResponse(botToken)
+	response.Token = token
+	return response, nil
+}
+
+// getBotOfOwner returns ErrBotNotFound when the bot does not exist or belongs to another user.
+func (b *botUseCase) getBotOfOwner(ctx context.Context, ownerID string, botID string) (*domain.Bot, error) {
+	bot, err := b.botRepository.GetBotByID(ctx, botID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrBotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bot.OwnerID != ownerID {
+		return nil, domain.ErrBotNotFound
+	}
+	return bot, nil
+}
+
+// GetListBot implements BotUseCase.
+func (b *botUseCase) GetListBot(ctx context.Context, ownerID string) ([]*presenter.BotResponse, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.GetListBot")
+	defer span()
+
+	bots, err := b.botRepository.GetListBotByOwnerID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	botResponses := make([]*presenter.BotResponse, 0, len(bots))
+	if len(bots) == 0 {
+		return botResponses, nil
+	}
+
+	userIDs := make([]string, 0, len(bots))
+	for _, bot := range bots {
+		userIDs = append(userIDs, bot.UserID)
+	}
+	users, err := b.userRepository.GetListUserByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	mapUser := make(map[string]*domain.UserInfo, len(users))
+	for _, user := range users {
+		mapUser[user.ID] = user
+	}
+	for _, bot := range bots {
+		user, ok := mapUser[bot.UserID]
+		if !ok {
+			continue
+		}
+		botResponses = append(botResponses, newBotResponse(bot, user))
+	}
+	return botResponses, nil
+}
+
+// UpdateBot implements BotUseCase.
+func (b *botUseCase) UpdateBot(ctx context.Context, request *presenter.UpdateBotRequest) (*presenter.BotResponse, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.UpdateBot")
+	defer span()
+
+	bot, err := b.getBotOfOwner(ctx, request.OwnerID, request.BotID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := b.userRepository.GetUserByID(ctx, bot.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if request.WebhookURL != nil {
+		err = checkWebhookURL(ctx, *request.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if request.FullName != nil || request.AvatarObjectName != "" {
+		if request.FullName != nil {
+			user.FullName = strings.TrimSpace(*request.FullName)
+		}
+		if request.AvatarObjectName != "" {
+			user.Avatar, err = getUploadedObjectURL(ctx, b.objectStorage, request.AvatarBucketName, request.AvatarObjectName)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = b.userRepository.UpdateUser(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if request.WebhookURL != nil {
+		bot.WebhookURL = *request.WebhookURL
+		bot.UpdatedAt = pointer.ToPtr(time.Now())
+		err = b.botRepository.UpdateBot(ctx, bot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newBotResponse(bot, user), nil
+}
+
+// DeleteBot implements BotUseCase.
+func (b *botUseCase) DeleteBot(ctx context.Context, ownerID string, botID string) error {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.DeleteBot")
+	defer span()
+
+	bot, err := b.getBotOfOwner(ctx, ownerID, botID)
+	if err != nil {
+		return err
+	}
+	err = b.botRepository.DeleteBot(ctx, bot, time.Now())
+	if err != nil {
+		return err
+	}
+	b.closeBotConnections(ctx, bot, domain.BotSessionRevokedReasonDeleted)
+	return nil
+}
+
+// closeBotConnections closes the open websockets of the bot, the bot reconnects with a token still valid.
+func (b *botUseCase) closeBotConnections(ctx context.Context, bot *domain.Bot, reason string) {
+	err := publishUserEvent(ctx, b.messagePublisher, domain.WsSessionRevoked, map[string]any{
+		"reason": reason,
+	}, bot.UserID)
+	if err != nil {
+		b.obs.Logger.WithContext(ctx).Error("failed to publish session revoked", err, bot.ID)
+	}
+}
+
+// CreateBotToken implements BotUseCase.
+func (b *botUseCase) CreateBotToken(ctx context.Context, ownerID string, botID string, request *presenter.CreateBotTokenRequest) (*presenter.BotTokenResponse, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.CreateBotToken")
+	defer span()
+
+	bot, err := b.getBotOfOwner(ctx, ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := b.botRepository.CountActiveBotToken(ctx, bot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxBotTokenPerBot {
+		return nil, domain.ErrBotTokenLimitReached
+	}
+	return b.createBotToken(ctx, bot.ID, request.Name)
+}
+
+// GetListBotToken implements BotUseCase.
+func (b *botUseCase) GetListBotToken(ctx context.Context, ownerID string, botID string) ([]*presenter.BotTokenResponse, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.GetListBotToken")
+	defer span()
+
+	bot, err := b.getBotOfOwner(ctx, ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+	botTokens, err := b.botRepository.GetListBotTokenByBotID(ctx, bot.ID)
+	if err != nil {
+		return nil, err
+	}
+	botTokenResponses := make([]*presenter.BotTokenResponse, 0, len(botTokens))
+	for _, botToken := range botTokens {
+		botTokenResponses = append(botTokenResponses, newBotTokenResponse(botToken))
+	}
+	return botTokenResponses, nil
+}
+
+// RevokeBotToken implements BotUseCase.
+func (b *botUseCase) RevokeBotToken(ctx context.Context, ownerID string, botID string, tokenID string) error {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.RevokeBotToken")
+	defer span()
+
+	bot, err := b.getBotOfOwner(ctx, ownerID, botID)
+	if err != nil {
+		return err
+	}
+	revoked, err := b.botRepository.RevokeBotToken(ctx, bot.ID, tokenID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrBotTokenNotFound
+	}
+	// the connections do not keep the token they authenticated with, they are all closed
+	b.closeBotConnections(ctx, bot, domain.BotSessionRevokedReasonTokenRevoked)
+	return nil
+}
+
+// GetAccountIDByBotToken implements BotUseCase.
+func (b *botUseCase) GetAccountIDByBotToken(ctx context.Context, token string) (string, error) {
+	ctx, span := b.obs.StartSpan(ctx, "BotUsecase.GetAccountIDByBotToken")
+	defer span()
+
+	accountID, err := b.botRepository.GetAccountIDByBotToken(ctx, domain.HashToken(token), time.Now())
+	if err == pgx.ErrNoRows {
+		return "", domain.ErrBotTokenInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	return accountID, nil
+}
+
+// HandleBotWebhook implements BotUseCase. A failed delivery is only logged, the event is not retried.
+func (b *botUseCase) HandleBotWebhook(ctx context.Context, message *domain.WebSocketMessage) error {
+	logger := b.obs.Logger.WithContext(ctx)
+
+	var bots []*domain.Bot
+	var err error
+	if len(message.UserIDs) > 0 {
+		bots, err = b.botRepository.GetListWebhookBotByUserIDs(ctx, message.UserIDs)
+	} else if conversationID, ok := message.Payload["conversation_id"].(string); ok {
+		bots, err = b.botRepository.GetListWebhookBotByConversationID(ctx, conversationID)
+	}
+	if err != nil {
+		logger.Error("error get list webhook bot", err, message)
+		return err
+	}
+	if len(bots) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(&domain.WebSocketMessage{
+		Type:    message.Type,
+		Payload: message.Payload,
+	})
+	if err != nil {
+		logger.Error("failed to marshal message to json", err, message)
+		return err
+	}
+	senderID, _ := message.Payload["user_id"].(string)
+	for _, bot := range bots {
+		// a bot does not get its own messages back
+		if message.Type == domain.WsMessage && bot.UserID == senderID {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, domain.BotWebhookTimeout)
+		err = b.webhookSender.Send(sendCtx, &webhook.Request{
+			URL:    bot.WebhookURL,
+			Secret: bot.WebhookSecret,
+			Event:  string(message.Type),
+			Body:   body,
+		})
+		cancel()
+		if err != nil {
+			logger.Error("error send bot webhook", err, bot.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+type ChannelUseCase interface {
+	SearchPublicChannel(ctx context.Context, keyword string, lastConversationID string, limit int) ([]*presenter.ChannelResponse, error)
+	GetChannel(ctx context.Context, userID string, conversationID string) (*presenter.ChannelResponse, error)
+	// JoinChannel subscribes the user, or creates a join request when the channel requires approval.
+	JoinChannel(ctx context.Context, userID string, conversationID string) (*presenter.ChannelResponse, error)
+	LeaveChannel(ctx context.Context, userID string, conversationID string) error
+}
+
+type channelUseCase struct {
+	conversationRepository domain.ConversationRepository
+	messagePublisher       pubsub.Publisher
+	joinRequester          *joinRequester
+	obs                    *observability.Observability
+}
+
+func NewChannelUseCase(conversationRepository domain.ConversationRepository, joinRequestRepository domain.ConversationJoinRequestRepository, userRepository domain.UserRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) ChannelUseCase {
+	return &channelUseCase{
+		conversationRepository: conversationRepository,
+		messagePublisher:       messagePublisher,
+		joinRequester:          newJoinRequester(conversationRepository, joinRequestRepository, userRepository, messagePublisher, obs),
+		obs:                    obs,
+	}
+}
+
+func newChannelResponse(conversation *domain.Conversation, conversationMember *domain.ConversationMember) *presenter.ChannelResponse {
+	response := &presenter.ChannelResponse{
+		ConversationID:  conversation.ID,
+		Title:           conversation.Title,
+		Avatar:          conversation.Avatar,
+		Description:     conversation.Description,
+		IsPublic:        conversation.IsPublic,
+		RequireApproval: conversation.RequireApproval,
+		SubscriberCount: conversation.MemberCount,
+		CreatedAt:       conversation.CreatedAt,
+	}
+	if conversationMember != nil {
+		response.IsSubscribed = true
+		response.Role = conversationMember.Role
+	}
+	return response
+}
+
+// getChannel returns the channel with its subscriber count and the membership of the user if any.
+func (c *channelUseCase) getChannel(ctx context.Context, userID string, conversationID string) (*domain.Conversation, *domain.ConversationMember, error) {
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, nil, err
+	}
+	if err == pgx.ErrNoRows || conversation.Type != domain.ConversationTypeChannel || conversation.DeletedAt != nil {
+		return nil, nil, domain.ErrChannelNotFound
+	}
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, nil, err
+	}
+	if err == pgx.ErrNoRows {
+		conversationMember = nil
+	}
+
+	conversation.MemberCount, err = c.conversationRepository.CountConversationMember(ctx, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conversation, conversationMember, nil
+}
+
+// SearchPublicChannel implements ChannelUseCase.
+func (c *channelUseCase) SearchPublicChannel(ctx context.Context, keyword string, lastConversationID string, limit int) ([]*presenter.ChannelResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ChannelUsecase.SearchPublicChannel")
+	defer span()
+
+	conversations, err := c.conversationRepository.SearchPublicChannel(ctx, keyword, lastConversationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	channelResponses := make([]*presenter.ChannelResponse, 0)
+	for _, conversation := range conversations {
+		channelResponses = append(channelResponses, newChannelResponse(conversation, nil))
+	}
+	return channelResponses, nil
+}
+
+// GetChannel implements ChannelUseCase.
+func (c *channelUseCase) GetChannel(ctx context.Context, userID string, conversationID string) (*presenter.ChannelResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ChannelUsecase.GetChannel")
+	defer span()
+
+	conversation, conversationMember, err := c.getChannel(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	// a private channel is only visible to its subscribers
+	if !conversation.IsPublic && conversationMember == nil {
+		return nil, domain.ErrChannelNotFound
+	}
+	response := newChannelResponse(conversation, conversationMember)
+	if conversationMember == nil && conversation.RequireApproval {
+		response.JoinRequest, err = c.joinRequester.getPendingJoinRequest(ctx, conversationID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// JoinChannel implements ChannelUseCase.
+func (c *channelUseCase) JoinChannel(ctx context.Context, userID string, conversationID string) (*presenter.ChannelResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ChannelUsecase.JoinChannel")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	conversation, conversationMember, err := c.getChannel(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversationMember != nil {
+		return newChannelResponse(conversation, conversationMember), nil
+	}
+	if !conversation.IsPublic {
+		return nil, domain.ErrChannelNotPublic
+	}
+	if conversation.RequireApproval {
+		response := newChannelResponse(conversation, nil)
+		response.JoinRequest, err = c.joinRequester.requestToJoin(ctx, conversationID, userID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	conversationMemberID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	conversationMember = &domain.ConversationMember{
+		ID:             conversationMemberID,
+		ConversationID: conversationID,
+		UserID:         userID,
+		Role:           domain.ConversationMemberRoleMember,
+		CreatedAt:      pointer.ToPtr(time.Now()),
+		UpdatedAt:      pointer.ToPtr(time.Now()),
+	}
+	err = c.conversationRepository.AddConversationMember(ctx, conversationMember)
+	if err != nil {
+		return nil, err
+	}
+	conversation.MemberCount++
+
+	err = publishUserEvent(ctx, c.messagePublisher, domain.WsChannelSubscribed, map[string]any{
+		"conversation_id": conversationID,
+	}, userID)
+	if err != nil {
+		logger.Error("error publish channel subscribed", err, conversationID)
+	}
+	return newChannelResponse(conversation, conversationMember), nil
+}
+
+// LeaveChannel implements ChannelUseCase.
+func (c *channelUseCase) LeaveChannel(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ChannelUsecase.LeaveChannel")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	_, conversationMember, err := c.getChannel(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if conversationMember == nil {
+		return domain.ErrNotFoundMemberOfConversation
+	}
+	// the owner can not leave the channel without anybody to manage it
+	if conversationMember.Role == domain.ConversationMemberRoleOwner {
+		return domain.ErrPermissionDenied
+	}
+
+	err = c.conversationRepository.DeleteConversationMember(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = publishUserEvent(ctx, c.messagePublisher, domain.WsChannelUnsubscribed, map[string]any{
+		"conversation_id": conversationID,
+	}, userID)
+	if err != nil {
+		logger.Error("error publish channel unsubscribed", err, conversationID)
+	}
+	return nil
+}
+
+var _ ChannelUseCase = &channelUseCase{}
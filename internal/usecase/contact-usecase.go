@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+type ContactUseCase interface {
+	SendContactRequest(ctx context.Context, request *presenter.SendContactRequestRequest) (*presenter.ContactRequestResponse, error)
+	AcceptContactRequest(ctx context.Context, userID string, contactRequestID string) (*presenter.ContactRequestResponse, error)
+	DeclineContactRequest(ctx context.Context, userID string, contactRequestID string) error
+	CancelContactRequest(ctx context.Context, userID string, contactRequestID string) error
+	GetListContactRequest(ctx context.Context, userID string, outgoing bool) ([]*presenter.ContactRequestResponse, error)
+	GetListContact(ctx context.Context, userID string, keyword string, lastID string, limit int) ([]*presenter.ContactResponse, error)
+	RemoveContact(ctx context.Context, userID string, friendID string) error
+}
+
+type contactUseCase struct {
+	contactRepository   domain.ContactRepository
+	userRepository      domain.UserRepository
+	userBlockRepository domain.UserBlockRepository
+	messagePublisher    pubsub.Publisher
+	obs                 *observability.Observability
+}
+
+func NewContactUseCase(contactRepository domain.ContactRepository, userRepository domain.UserRepository, userBlockRepository domain.UserBlockRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) ContactUseCase {
+	return &contactUseCase{
+		contactRepository:   contactRepository,
+		userRepository:      userRepository,
+		userBlockRepository: userBlockRepository,
+		messagePublisher:    messagePublisher,
+		obs:                 obs,
+	}
+}
+
+func newUserResponse(user *domain.UserInfo) *presenter.UserResponse {
+	if user == nil {
+		return nil
+	}
+	return &presenter.UserResponse{
+		UserID:   user.ID,
+		FullName: user.FullName,
+		Avatar:   user.Avatar,
+		Handle:   pointer.FromPtr(user.Handle),
+		UserType: user.Type,
+		Status:   newUserStatusResponse(user.StatusEmoji, user.StatusText, user.StatusExpiresAt),
+	}
+}
+
+func newContactRequestResponse(contact *domain.Contact) *presenter.ContactRequestResponse {
+	return &presenter.ContactRequestResponse{
+		ContactRequestID: contact.ID,
+		SenderID:         contact.UserID,
+		ReceiverID:       contact.FriendID,
+		Status:           contact.Status,
+		CreatedAt:        contact.CreatedAt,
+	}
+}
+
+// publishContactEvent sends the event to the devices of both users of the request.
+func (c *contactUseCase) publishContactEvent(ctx context.Context, messageType domain.WebSocketMessageType, contact *domain.Contact) {
+	logger := c.obs.Logger.WithContext(ctx)
+	response := newContactRequestResponse(contact)
+	sender, err := c.userRepository.GetUserByID(ctx, contact.UserID)
+	if err != nil {
+		logger.Error("error get sender of contact request", err, contact)
+		return
+	}
+	receiver, err := c.userRepository.GetUserByID(ctx, contact.FriendID)
+	if err != nil {
+		logger.Error("error get receiver of contact request", err, contact)
+		return
+	}
+	response.Sender = newUserResponse(sender)
+	response.Receiver = newUserResponse(receiver)
+	// each user sees the avatar of the other one by its privacy
+	err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, contact.FriendID, response.Sender)
+	if err != nil {
+		logger.Error("error hide avatar of sender", err, contact)
+		return
+	}
+	err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, contact.UserID, response.Receiver)
+	if err != nil {
+		logger.Error("error hide avatar of receiver", err, contact)
+		return
+	}
+	responseMap, err := pointer.ToMap(response)
+	if err != nil {
+		logger.Error("error convert contact request to map", err, response)
+		return
+	}
+	err = publishUserEvent(ctx, c.messagePublisher, messageType, responseMap, contact.UserID, contact.FriendID)
+	if err != nil {
+		logger.Error("error publish contact event", err, messageType, contact)
+	}
+}
+
+// SendContactRequest implements ContactUseCase.
+func (c *contactUseCase) SendContactRequest(ctx context.Context, request *presenter.SendContactRequestRequest) (*presenter.ContactRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.SendContactRequest")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	if request.FriendID == request.UserID {
+		return nil, domain.ErrContactSelf
+	}
+	friend, err := c.userRepository.GetUserByID(ctx, request.FriendID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := c.userBlockRepository.IsBlockedBetween(ctx, request.UserID, request.FriendID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, domain.ErrUserBlocked
+	}
+
+	contacts, err := c.contactRepository.GetListContactBetween(ctx, request.UserID, request.FriendID)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		switch {
+		case contact.Status == domain.ContactStatusAccepted:
+			return nil, domain.ErrContactAlreadyExists
+		case contact.UserID == request.UserID:
+			// sending the request again returns the pending one
+			response := newContactRequestResponse(contact)
+			response.Receiver = newUserResponse(friend)
+			err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, request.UserID, response.Receiver)
+			if err != nil {
+				return nil, err
+			}
+			return response, nil
+		default:
+			// both users asked, the request of the other one is accepted
+			return c.AcceptContactRequest(ctx, request.UserID, contact.ID)
+		}
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	contact := &domain.Contact{
+		ID:        id,
+		UserID:    request.UserID,
+		FriendID:  request.FriendID,
+		Status:    domain.ContactStatusPending,
+		CreatedAt: pointer.ToPtr(time.Now()),
+		UpdatedAt: pointer.ToPtr(time.Now()),
+	}
+	err = c.contactRepository.CreateContactRequest(ctx, contact)
+	if err != nil {
+		logger.Error("error create contact request", err, contact)
+		return nil, err
+	}
+	c.publishContactEvent(ctx, domain.WsContactRequestCreated, contact)
+
+	response := newContactRequestResponse(contact)
+	response.Receiver = newUserResponse(friend)
+	err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, request.UserID, response.Receiver)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// getPendingContactRequest returns the pending request if the user is the sender, or the receiver when received is set.
+func (c *contactUseCase) getPendingContactRequest(ctx context.Context, userID string, contactRequestID string, received bool) (*domain.Contact, error) {
+	contact, err := c.contactRepository.GetContactByID(ctx, contactRequestID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows || contact.Status != domain.ContactStatusPending {
+		return nil, domain.ErrContactRequestNotFound
+	}
+	if (received && contact.FriendID != userID) || (!received && contact.UserID != userID) {
+		return nil, domain.ErrContactRequestNotFound
+	}
+	return contact, nil
+}
+
+// AcceptContactRequest implements ContactUseCase.
+func (c *contactUseCase) AcceptContactRequest(ctx context.Context, userID string, contactRequestID string) (*presenter.ContactRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.AcceptContactRequest")
+	defer span()
+
+	contact, err := c.getPendingContactRequest(ctx, userID, contactRequestID, true)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	contact.Status = domain.ContactStatusAccepted
+	contact.UpdatedAt = pointer.ToPtr(time.Now())
+	reverseContact := &domain.Contact{
+		ID:        id,
+		UserID:    contact.FriendID,
+		FriendID:  contact.UserID,
+		Status:    domain.ContactStatusAccepted,
+		CreatedAt: contact.UpdatedAt,
+		UpdatedAt: contact.UpdatedAt,
+	}
+	err = c.contactRepository.AcceptContactRequest(ctx, contact, reverseContact)
+	if err != nil {
+		return nil, err
+	}
+	c.publishContactEvent(ctx, domain.WsContactRequestAccepted, contact)
+
+	sender, err := c.userRepository.GetUserByID(ctx, contact.UserID)
+	if err != nil {
+		return nil, err
+	}
+	response := newContactRequestResponse(contact)
+	response.Sender = newUserResponse(sender)
+	err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, userID, response.Sender)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeclineContactRequest implements ContactUseCase.
+func (c *contactUseCase) DeclineContactRequest(ctx context.Context, userID string, contactRequestID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.DeclineContactRequest")
+	defer span()
+
+	contact, err := c.getPendingContactRequest(ctx, userID, contactRequestID, true)
+	if err != nil {
+		return err
+	}
+	err = c.contactRepository.DeleteContactRequest(ctx, contact.ID)
+	if err != nil {
+		return err
+	}
+	c.publishContactEvent(ctx, domain.WsContactRequestDeclined, contact)
+	return nil
+}
+
+// CancelContactRequest implements ContactUseCase.
+func (c *contactUseCase) CancelContactRequest(ctx context.Context, userID string, contactRequestID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.CancelContactRequest")
+	defer span()
+
+	contact, err := c.getPendingContactRequest(ctx, userID, contactRequestID, false)
+	if err != nil {
+		return err
+	}
+	err = c.contactRepository.DeleteContactRequest(ctx, contact.ID)
+	if err != nil {
+		return err
+	}
+	c.publishContactEvent(ctx, domain.WsContactRequestCancelled, contact)
+	return nil
+}
+
+// GetListContactRequest implements ContactUseCase.
+func (c *contactUseCase) GetListContactRequest(ctx context.Context, userID string, outgoing bool) ([]*presenter.ContactRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.GetListContactRequest")
+	defer span()
+
+	contacts, err := c.contactRepository.GetListContactRequestWithUser(ctx, userID, outgoing)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*presenter.ContactRequestResponse, 0, len(contacts))
+	users := make([]*presenter.UserResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		response := newContactRequestResponse(&contact.Contact)
+		user := newUserResponse(contact.User)
+		if outgoing {
+			response.Receiver = user
+		} else {
+			response.Sender = user
+		}
+		responses = append(responses, response)
+		users = append(users, user)
+	}
+	err = hideUserAvatars(ctx, c.userRepository, c.contactRepository, userID, users...)
+	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// GetListContact implements ContactUseCase.
+func (c *contactUseCase) GetListContact(ctx context.Context, userID string, keyword string, lastID string, limit int) ([]*presenter.ContactResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.GetListContact")
+	defer span()
+
+	contacts, err := c.contactRepository.GetListContactWithUser(ctx, userID, keyword, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*presenter.ContactResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		responses = append(responses, &presenter.ContactResponse{
+			UserID:    contact.User.ID,
+			FullName:  contact.User.FullName,
+			Avatar:    contact.User.Avatar,
+			UserType:  contact.User.Type,
+			IsOnline:  contact.IsOnline,
+			CreatedAt: contact.CreatedAt,
+		})
+	}
+	avatars := userAvatars{}
+	for _, response := range responses {
+		avatars.add(response.UserID, &response.Avatar)
+	}
+	err = hideAvatars(ctx, c.userRepository, c.contactRepository, userID, avatars)
+	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// RemoveContact implements ContactUseCase.
+func (c *contactUseCase) RemoveContact(ctx context.Context, userID string, friendID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ContactUsecase.RemoveContact")
+	defer span()
+
+	removed, err := c.contactRepository.DeleteContact(ctx, userID, friendID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return domain.ErrContactNotFound
+	}
+	err = publishUserEvent(ctx, c.messagePublisher, domain.WsContactRemoved, map[string]any{
+		"user_id":   userID,
+		"friend_id": friendID,
+	}, userID, friendID)
+	if err != nil {
+		c.obs.Logger.WithContext(ctx).Error("error publish contact removed", err, userID, friendID)
+	}
+	return nil
+}
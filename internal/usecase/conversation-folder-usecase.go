@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConversationFolderUseCase interface {
+	CreateFolder(ctx context.Context, request *presenter.ConversationFolderRequest) (*presenter.ConversationFolderResponse, error)
+	UpdateFolder(ctx context.Context, request *presenter.ConversationFolderRequest) (*presenter.ConversationFolderResponse, error)
+	DeleteFolder(ctx context.Context, userID string, folderID string) error
+	GetListFolder(ctx context.Context, userID string) ([]*presenter.ConversationFolderResponse, error)
+}
+
+type conversationFolderUseCase struct {
+	conversationFolderRepository domain.ConversationFolderRepository
+	messagePublisher             pubsub.Publisher
+	obs                          *observability.Observability
+}
+
+func NewConversationFolderUseCase(conversationFolderRepository domain.ConversationFolderRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) ConversationFolderUseCase {
+	return &conversationFolderUseCase{
+		conversationFolderRepository: conversationFolderRepository,
+		messagePublisher:             messagePublisher,
+		obs:                          obs,
+	}
+}
+
+func newConversationFolderResponse(folder *domain.ConversationFolder) *presenter.ConversationFolderResponse {
+	response := &presenter.ConversationFolderResponse{
+		FolderID:          folder.ID,
+		Name:              folder.Name,
+		ConversationTypes: folder.ConversationTypes,
+		ConversationIDs:   folder.ConversationIDs,
+		Position:          folder.Position,
+		CreatedAt:         folder.CreatedAt,
+		UpdatedAt:         folder.UpdatedAt,
+	}
+	if response.ConversationTypes == nil {
+		response.ConversationTypes = []string{}
+	}
+	if response.ConversationIDs == nil {
+		response.ConversationIDs = []string{}
+	}
+	return response
+}
+
+// folderConversationTypes returns the types of the request, a folder made only of conversations
+// has no type but its column does not take NULL.
+func folderConversationTypes(request *presenter.ConversationFolderRequest) []string {
+	if request.ConversationTypes == nil {
+		return []string{}
+	}
+	return request.ConversationTypes
+}
+
+// getFolderOfUser returns the folder only if it belongs to the user.
+func (c *conversationFolderUseCase) getFolderOfUser(ctx context.Context, userID string, folderID string) (*domain.ConversationFolder, error) {
+	folder, err := c.conversationFolderRepository.GetFolderByID(ctx, folderID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows || folder.UserID != userID {
+		return nil, domain.ErrConversationFolderNotFound
+	}
+	return folder, nil
+}
+
+// publishFolderUpdated syncs the folder to the other devices of the user.
+func (c *conversationFolderUseCase) publishFolderUpdated(ctx context.Context, folder *domain.ConversationFolder) {
+	logger := c.obs.Logger.WithContext(ctx)
+	folderMap, err := pointer.ToMap(newConversationFolderResponse(folder))
+	if err != nil {
+		logger.Error("error convert folder to map", err, folder)
+		return
+	}
+	err = publishUserEvent(ctx, c.messagePublisher, domain.WsFolderUpdated, folderMap, folder.UserID)
+	if err != nil {
+		logger.Error("error publish folder updated", err, folder)
+	}
+}
+
+// CreateFolder implements ConversationFolderUseCase.
+func (c *conversationFolderUseCase) CreateFolder(ctx context.Context, request *presenter.ConversationFolderRequest) (*presenter.ConversationFolderResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationFolderUsecase.CreateFolder")
+	defer span()
+
+	count, err := c.conversationFolderRepository.CountFolderByUserID(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxConversationFolder {
+		return nil, domain.ErrConversationFolderLimit
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	folder := &domain.ConversationFolder{
+		ID:                id,
+		UserID:            request.UserID,
+		Name:              request.Name,
+		ConversationTypes: folderConversationTypes(request),
+		ConversationIDs:   request.ConversationIDs,
+		Position:          request.Position,
+		CreatedAt:         pointer.ToPtr(time.Now()),
+		UpdatedAt:         pointer.ToPtr(time.Now()),
+	}
+	err = c.conversationFolderRepository.CreateFolder(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	// reload to drop the conversations the user is not a member of
+	folder, err = c.conversationFolderRepository.GetFolderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.publishFolderUpdated(ctx, folder)
+	return newConversationFolderResponse(folder), nil
+}
+
+// UpdateFolder implements ConversationFolderUseCase.
+func (c *conversationFolderUseCase) UpdateFolder(ctx context.Context, request *presenter.ConversationFolderRequest) (*presenter.ConversationFolderResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationFolderUsecase.UpdateFolder")
+	defer span()
+
+	folder, err := c.getFolderOfUser(ctx, request.UserID, request.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Name = request.Name
+	folder.ConversationTypes = folderConversationTypes(request)
+	folder.ConversationIDs = request.ConversationIDs
+	folder.Position = request.Position
+	folder.UpdatedAt = pointer.ToPtr(time.Now())
+	err = c.conversationFolderRepository.UpdateFolder(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err = c.conversationFolderRepository.GetFolderByID(ctx, folder.ID)
+	if err != nil {
+		return nil, err
+	}
+	c.publishFolderUpdated(ctx, folder)
+	return newConversationFolderResponse(folder), nil
+}
+
+// DeleteFolder implements ConversationFolderUseCase.
+func (c *conversationFolderUseCase) DeleteFolder(ctx context.Context, userID string, folderID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationFolderUsecase.DeleteFolder")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	folder, err := c.getFolderOfUser(ctx, userID, folderID)
+	if err != nil {
+		return err
+	}
+	err = c.conversationFolderRepository.DeleteFolder(ctx, folder.ID)
+	if err != nil {
+		return err
+	}
+
+	err = publishUserEvent(ctx, c.messagePublisher, domain.WsFolderDeleted, map[string]any{
+		"folder_id": folder.ID,
+	}, userID)
+	if err != nil {
+		logger.Error("error publish folder deleted", err, folder)
+	}
+	return nil
+}
+
+// GetListFolder implements ConversationFolderUseCase.
+func (c *conversationFolderUseCase) GetListFolder(ctx context.Context, userID string) ([]*presenter.ConversationFolderResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationFolderUsecase.GetListFolder")
+	defer span()
+
+	folders, err := c.conversationFolderRepository.GetListFolderByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	folderResponses := make([]*presenter.ConversationFolderResponse, 0)
+	for _, folder := range folders {
+		folderResponses = append(folderResponses, newConversationFolderResponse(folder))
+	}
+	return folderResponses, nil
+}
+
+var _ ConversationFolderUseCase = &conversationFolderUseCase{}
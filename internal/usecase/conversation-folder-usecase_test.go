@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeConversationFolderRepository keeps the folders in memory and refuses the NULL
+// conversation types the conversation_folder table refuses.
+type fakeConversationFolderRepository struct {
+	folders map[string]*domain.ConversationFolder
+}
+
+func (f *fakeConversationFolderRepository) save(folder *domain.ConversationFolder) error {
+	if folder.ConversationTypes == nil {
+		return errors.New("null value in column \"conversation_types\"")
+	}
+	saved := *folder
+	f.folders[folder.ID] = &saved
+	return nil
+}
+
+func (f *fakeConversationFolderRepository) CreateFolder(ctx context.Context, folder *domain.ConversationFolder) error {
+	return f.save(folder)
+}
+
+func (f *fakeConversationFolderRepository) UpdateFolder(ctx context.Context, folder *domain.ConversationFolder) error {
+	return f.save(folder)
+}
+
+func (f *fakeConversationFolderRepository) DeleteFolder(ctx context.Context, id string) error {
+	delete(f.folders, id)
+	return nil
+}
+
+func (f *fakeConversationFolderRepository) GetFolderByID(ctx context.Context, id string) (*domain.ConversationFolder, error) {
+	folder, ok := f.folders[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	found := *folder
+	return &found, nil
+}
+
+func (f *fakeConversationFolderRepository) GetListFolderByUserID(ctx context.Context, userID string) ([]*domain.ConversationFolder, error) {
+	var folders []*domain.ConversationFolder
+	for _, folder := range f.folders {
+		if folder.UserID == userID {
+			folders = append(folders, folder)
+		}
+	}
+	return folders, nil
+}
+
+func (f *fakeConversationFolderRepository) CountFolderByUserID(ctx context.Context, userID string) (int, error) {
+	folders, _ := f.GetListFolderByUserID(ctx, userID)
+	return len(folders), nil
+}
+
+type fakePublisher struct{}
+
+func (fakePublisher) Publish(ctx context.Context, subject string, data interface{}) error {
+	return nil
+}
+
+func TestConversationFolderWithOnlyConversationIDs(t *testing.T) {
+	ctx := context.Background()
+	folderUseCase := NewConversationFolderUseCase(&fakeConversationFolderRepository{
+		folders: map[string]*domain.ConversationFolder{},
+	}, fakePublisher{}, &observability.Observability{Logger: observability.NewLogger()})
+
+	request := &presenter.ConversationFolderRequest{
+		UserID:          "user-1",
+		Name:            "Work",
+		ConversationIDs: []string{"conversation-1"},
+	}
+	if err := request.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	created, err := folderUseCase.CreateFolder(ctx, request)
+	if err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if created.ConversationTypes == nil || len(created.ConversationTypes) != 0 {
+		t.Fatalf("conversation types = %v, want empty", created.ConversationTypes)
+	}
+
+	request.FolderID = created.FolderID
+	request.ConversationIDs = []string{"conversation-1", "conversation-2"}
+	updated, err := folderUseCase.UpdateFolder(ctx, request)
+	if err != nil {
+		t.Fatalf("update folder: %v", err)
+	}
+	if len(updated.ConversationIDs) != 2 {
+		t.Fatalf("conversation ids = %v, want 2", updated.ConversationIDs)
+	}
+}
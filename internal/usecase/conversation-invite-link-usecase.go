@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/random"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+const inviteLinkTokenLength = 24
+
+type ConversationInviteLinkUseCase interface {
+	CreateInviteLink(ctx context.Context, request *presenter.CreateInviteLinkRequest) (*presenter.InviteLinkResponse, error)
+	GetListInviteLink(ctx context.Context, userID string, conversationID string) ([]*presenter.InviteLinkResponse, error)
+	RevokeInviteLink(ctx context.Context, userID string, conversationID string, inviteLinkID string) error
+	PreviewInviteLink(ctx context.Context, userID string, token string) (*presenter.InviteLinkPreviewResponse, error)
+	JoinByInviteLink(ctx context.Context, userID string, token string) (*presenter.JoinConversationResponse, error)
+}
+
+type conversationInviteLinkUseCase struct {
+	conversationRepository           domain.ConversationRepository
+	conversationInviteLinkRepository domain.ConversationInviteLinkRepository
+	userRepository                   domain.UserRepository
+	contactRepository                domain.ContactRepository
+	messagePublisher                 pubsub.Publisher
+	messageSender                    *messageSender
+	joinRequester                    *joinRequester
+	obs                              *observability.Observability
+}
+
+func NewConversationInviteLinkUseCase(conversationRepository domain.ConversationRepository, conversationInviteLinkRepository domain.ConversationInviteLinkRepository, joinRequestRepository domain.ConversationJoinRequestRepository, messageRepository domain.MessageRepository, userRepository domain.UserRepository, contactRepository domain.ContactRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) ConversationInviteLinkUseCase {
+	return &conversationInviteLinkUseCase{
+		conversationRepository:           conversationRepository,
+		conversationInviteLinkRepository: conversationInviteLinkRepository,
+		userRepository:                   userRepository,
+		contactRepository:                contactRepository,
+		messagePublisher:                 messagePublisher,
+		messageSender:                    newMessageSender(messageRepository, userRepository, messagePublisher, obs),
+		joinRequester:                    newJoinRequester(conversationRepository, joinRequestRepository, userRepository, messagePublisher, obs),
+		obs:                              obs,
+	}
+}
+
+// checkGroupAdmin makes sure the user manages the group the links belong to.
+func (c *conversationInviteLinkUseCase) checkGroupAdmin(ctx context.Context, userID string, conversationID string) error {
+	member, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows {
+		return domain.ErrNotFoundMemberOfConversation
+	}
+
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if conversation.Type != domain.ConversationTypeGroup {
+		return domain.ErrInviteLinkNotAllowed
+	}
+	if !member.IsAdmin() {
+		return domain.ErrPermissionDenied
+	}
+	return nil
+}
+
+func newInviteLinkResponse(inviteLink *domain.ConversationInviteLink) *presenter.InviteLinkResponse {
+	return &presenter.InviteLinkResponse{
+		InviteLinkID:    inviteLink.ID,
+		ConversationID:  inviteLink.ConversationID,
+		Token:           inviteLink.Token,
+		CreatedBy:       inviteLink.CreatedBy,
+		ExpiredAt:       inviteLink.ExpiredAt,
+		MaxUses:         inviteLink.MaxUses,
+		UsedCount:       inviteLink.UsedCount,
+		RequireApproval: inviteLink.RequireApproval,
+		CreatedAt:       inviteLink.CreatedAt,
+	}
+}
+
+// CreateInviteLink implements ConversationInviteLinkUseCase.
+func (c *conversationInviteLinkUseCase) CreateInviteLink(ctx context.Context, request *presenter.CreateInviteLinkRequest) (*presenter.InviteLinkResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationInviteLinkUsecase.CreateInviteLink")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	err := c.checkGroupAdmin(ctx, request.UserID, request.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	token, err := random.NewToken(inviteLinkTokenLength)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteLink := &domain.ConversationInviteLink{
+		ID:              id,
+		ConversationID:  request.ConversationID,
+		Token:           token,
+		CreatedBy:       request.UserID,
+		ExpiredAt:       request.ExpiredAt,
+		MaxUses:         request.MaxUses,
+		RequireApproval: request.RequireApproval,
+		CreatedAt:       pointer.ToPtr(time.Now()),
+		UpdatedAt:       pointer.ToPtr(time.Now()),
+	}
+	err = c.conversationInviteLinkRepository.CreateInviteLink(ctx, inviteLink)
+	if err != nil {
+		logger.Error("error create invite link", err, request)
+		return nil, err
+	}
+
+	return newInviteLinkResponse(inviteLink), nil
+}
+
+// GetListInviteLink implements ConversationInviteLinkUseCase.
+func (c *conversationInviteLinkUseCase) GetListInviteLink(ctx context.Context, userID string, conversationID string) ([]*presenter.InviteLinkResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationInviteLinkUsecase.GetListInviteLink")
+	defer span()
+
+	err := c.checkGroupAdmin(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteLinks, err := c.conversationInviteLinkRepository.GetListInviteLinkByConversationID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteLinkResponses := make([]*presenter.InviteLinkResponse, 0)
+	for _, inviteLink := range inviteLinks {
+		inviteLinkResponses = append(inviteLinkResponses, newInviteLinkResponse(inviteLink))
+	}
+	return inviteLinkResponses, nil
+}
+
+// RevokeInviteLink implements ConversationInviteLinkUseCase.
+func (c *conversationInviteLinkUseCase) RevokeInviteLink(ctx context.Context, userID string, conversationID string, inviteLinkID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationInviteLinkUsecase.RevokeInviteLink")
+	defer span()
+
+	err := c.checkGroupAdmin(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	inviteLink, err := c.conversationInviteLinkRepository.GetInviteLinkByID(ctx, inviteLinkID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows || inviteLink.ConversationID != conversationID {
+		return domain.ErrInviteLinkNotFound
+	}
+
+	return c.conversationInviteLinkRepository.RevokeInviteLink(ctx, inviteLinkID)
+}
+
+// getUsableInviteLink returns the link behind the token and its conversation if it can still be used.
+func (c *conversationInviteLinkUseCase) getUsableInviteLink(ctx context.Context, token string) (*domain.ConversationInviteLink, *domain.Conversation, error) {
+	inviteLink, err := c.conversationInviteLinkRepository.GetInviteLinkByToken(ctx, token)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, nil, domain.ErrInviteLinkNotFound
+	}
+	if !inviteLink.IsUsable(time.Now()) {
+		return nil, nil, domain.ErrInviteLinkInvalid
+	}
+
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, inviteLink.ConversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conversation.DeletedAt != nil {
+		return nil, nil, domain.ErrInviteLinkInvalid
+	}
+	return inviteLink, conversation, nil
+}
+
+// PreviewInviteLink implements ConversationInviteLinkUseCase.
+func (c *conversationInviteLinkUseCase) PreviewInviteLink(ctx context.Context, userID string, token string) (*presenter.InviteLinkPreviewResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationInviteLinkUsecase.PreviewInviteLink")
+	defer span()
+
+	inviteLink, conversation, err := c.getUsableInviteLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCount, err := c.conversationRepository.CountConversationMember(ctx, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := c.conversationRepository.CheckIsMemberOfConversation(ctx, userID, conversation.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	return &presenter.InviteLinkPreviewResponse{
+		ConversationID:  conversation.ID,
+		Title:           conversation.Title,
+		Avatar:          conversation.Avatar,
+		Type:            conversation.Type,
+		MemberCount:     memberCount,
+		RequireApproval: inviteLink.RequireApproval || conversation.RequireApproval,
+		ExpiredAt:       inviteLink.ExpiredAt,
+		IsMember:        isMember,
+	}, nil
+}
+
+// JoinByInviteLink implements ConversationInviteLinkUseCase.
+func (c *conversationInviteLinkUseCase) JoinByInviteLink(ctx context.Context, userID string, token string) (*presenter.JoinConversationResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationInviteLinkUsecase.JoinByInviteLink")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	inviteLink, conversation, err := c.getUsableInviteLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := c.conversationRepository.CheckIsMemberOfConversation(ctx, userID, conversation.ID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if isMember {
+		conversationResponse, err := c.getConversationResponse(ctx, userID, conversation)
+		if err != nil {
+			return nil, err
+		}
+		return &presenter.JoinConversationResponse{
+			Conversation: conversationResponse,
+		}, nil
+	}
+
+	if inviteLink.RequireApproval || conversation.RequireApproval {
+		joinRequest, err := c.joinRequester.requestToJoin(ctx, conversation.ID, userID, &inviteLink.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &presenter.JoinConversationResponse{
+			JoinRequest: joinRequest,
+		}, nil
+	}
+
+	conversationMemberID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	conversationMember := &domain.ConversationMember{
+		ID:             conversationMemberID,
+		ConversationID: conversation.ID,
+		UserID:         userID,
+		Role:           domain.ConversationMemberRoleMember,
+		CreatedAt:      pointer.ToPtr(time.Now()),
+		UpdatedAt:      pointer.ToPtr(time.Now()),
+	}
+	joined, err := c.conversationInviteLinkRepository.JoinByInviteLink(ctx, inviteLink.ID, conversationMember)
+	if err != nil {
+		logger.Error("error join by invite link", err, inviteLink.ID, userID)
+		return nil, err
+	}
+
+	// nothing is announced when the user joined concurrently through another request
+	if joined {
+		err = c.messageSender.publishMemberJoined(ctx, conversationMember, map[string]any{
+			"invite_link_id": inviteLink.ID,
+		})
+		if err != nil {
+			logger.Error("error publish member joined", err, conversationMember)
+		}
+	}
+
+	conversationResponse, err := c.getConversationResponse(ctx, userID, conversation)
+	if err != nil {
+		return nil, err
+	}
+	return &presenter.JoinConversationResponse{
+		Conversation: conversationResponse,
+	}, nil
+}
+
+// getConversationResponse returns the conversation the user joined, with the avatars the user may see.
+func (c *conversationInviteLinkUseCase) getConversationResponse(ctx context.Context, userID string, conversation *domain.Conversation) (*presenter.ConversationResponse, error) {
+	conversationResponse, err := getConversationResponse(ctx, c.conversationRepository, conversation)
+	if err != nil {
+		return nil, err
+	}
+	err = hideMemberAvatars(ctx, c.userRepository, c.contactRepository, userID, conversationResponse.Members)
+	if err != nil {
+		return nil, err
+	}
+	return conversationResponse, nil
+}
+
+var _ ConversationInviteLinkUseCase = &conversationInviteLinkUseCase{}
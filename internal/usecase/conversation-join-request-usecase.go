@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConversationJoinRequestUseCase interface {
+	GetListJoinRequest(ctx context.Context, userID string, conversationID string) ([]*presenter.JoinRequestResponse, error)
+	ApproveJoinRequest(ctx context.Context, userID string, conversationID string, joinRequestID string) (*presenter.JoinRequestResponse, error)
+	RejectJoinRequest(ctx context.Context, userID string, conversationID string, joinRequestID string) (*presenter.JoinRequestResponse, error)
+}
+
+type conversationJoinRequestUseCase struct {
+	conversationRepository domain.ConversationRepository
+	joinRequestRepository  domain.ConversationJoinRequestRepository
+	userRepository         domain.UserRepository
+	contactRepository      domain.ContactRepository
+	messagePublisher       pubsub.Publisher
+	messageSender          *messageSender
+	obs                    *observability.Observability
+}
+
+func NewConversationJoinRequestUseCase(conversationRepository domain.ConversationRepository, joinRequestRepository domain.ConversationJoinRequestRepository, messageRepository domain.MessageRepository, userRepository domain.UserRepository, contactRepository domain.ContactRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) ConversationJoinRequestUseCase {
+	return &conversationJoinRequestUseCase{
+		conversationRepository: conversationRepository,
+		joinRequestRepository:  joinRequestRepository,
+		userRepository:         userRepository,
+		contactRepository:      contactRepository,
+		messagePublisher:       messagePublisher,
+		messageSender:          newMessageSender(messageRepository, userRepository, messagePublisher, obs),
+		obs:                    obs,
+	}
+}
+
+func newJoinRequestResponse(joinRequest *domain.ConversationJoinRequest, user *domain.UserInfo) *presenter.JoinRequestResponse {
+	response := &presenter.JoinRequestResponse{
+		JoinRequestID:  joinRequest.ID,
+		ConversationID: joinRequest.ConversationID,
+		UserID:         joinRequest.UserID,
+		InviteLinkID:   joinRequest.InviteLinkID,
+		Status:         joinRequest.Status,
+		ReviewedBy:     joinRequest.ReviewedBy,
+		ReviewedAt:     joinRequest.ReviewedAt,
+		CreatedAt:      joinRequest.CreatedAt,
+	}
+	if user != nil {
+		response.FullName = user.FullName
+		response.Avatar = user.Avatar
+		response.UserType = user.Type
+	}
+	return response
+}
+
+// joinRequester is shared by the use cases a user can join a conversation from.
+type joinRequester struct {
+	conversationRepository domain.ConversationRepository
+	joinRequestRepository  domain.ConversationJoinRequestRepository
+	userRepository         domain.UserRepository
+	messagePublisher       pubsub.Publisher
+	obs                    *observability.Observability
+}
+
+func newJoinRequester(conversationRepository domain.ConversationRepository, joinRequestRepository domain.ConversationJoinRequestRepository, userRepository domain.UserRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) *joinRequester {
+	return &joinRequester{
+		conversationRepository: conversationRepository,
+		joinRequestRepository:  joinRequestRepository,
+		userRepository:         userRepository,
+		messagePublisher:       messagePublisher,
+		obs:                    obs,
+	}
+}
+
+// requestToJoin records a pending join request and notifies the admins of the conversation,
+// asking again while a request is pending returns that request.
+func (j *joinRequester) requestToJoin(ctx context.Context, conversationID string, userID string, inviteLinkID *string) (*presenter.JoinRequestResponse, error) {
+	logger := j.obs.Logger.WithContext(ctx)
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	joinRequest := &domain.ConversationJoinRequest{
+		ID:             id,
+		ConversationID: conversationID,
+		UserID:         userID,
+		InviteLinkID:   inviteLinkID,
+		Status:         domain.JoinRequestStatusPending,
+		CreatedAt:      pointer.ToPtr(time.Now()),
+		UpdatedAt:      pointer.ToPtr(time.Now()),
+	}
+	createdJoinRequest, err := j.joinRequestRepository.CreateJoinRequest(ctx, joinRequest)
+	if err != nil {
+		logger.Error("error create join request", err, joinRequest)
+		return nil, err
+	}
+
+	user, err := j.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := newJoinRequestResponse(createdJoinRequest, user)
+	if createdJoinRequest.ID != joinRequest.ID {
+		return response, nil
+	}
+
+	adminIDs, err := j.conversationRepository.GetListConversationAdminIDs(ctx, conversationID)
+	if err != nil {
+		logger.Error("error get admins of conversation", err, conversationID)
+		return response, nil
+	}
+	responseMap, err := pointer.ToMap(response)
+	if err != nil {
+		logger.Error("error convert join request to map", err, response)
+		return response, nil
+	}
+	// the admins are not all contacts of the user
+	responseMap["avatar"] = publicAvatar(user)
+	err = publishUserEvent(ctx, j.messagePublisher, domain.WsJoinRequestCreated, responseMap, adminIDs...)
+	if err != nil {
+		logger.Error("error publish join request created", err, response)
+	}
+	return response, nil
+}
+
+// getPendingJoinRequest returns the pending request of the user if any.
+func (j *joinRequester) getPendingJoinRequest(ctx context.Context, conversationID string, userID string) (*presenter.JoinRequestResponse, error) {
+	joinRequest, err := j.joinRequestRepository.GetPendingJoinRequest(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newJoinRequestResponse(joinRequest, nil), nil
+}
+
+// checkConversationAdmin makes sure the user manages the conversation the requests belong to.
+func (c *conversationJoinRequestUseCase) checkConversationAdmin(ctx context.Context, userID string, conversationID string) (*domain.Conversation, error) {
+	member, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Type == domain.ConversationTypeDM {
+		return nil, domain.ErrJoinRequestNotAllowed
+	}
+	if !member.IsAdmin() {
+		return nil, domain.ErrPermissionDenied
+	}
+	return conversation, nil
+}
+
+// getPendingJoinRequest returns the request if it belongs to the conversation and waits for a review.
+func (c *conversationJoinRequestUseCase) getPendingJoinRequest(ctx context.Context, conversationID string, joinRequestID string) (*domain.ConversationJoinRequest, error) {
+	joinRequest, err := c.joinRequestRepository.GetJoinRequestByID(ctx, joinRequestID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows || joinRequest.ConversationID != conversationID {
+		return nil, domain.ErrJoinRequestNotFound
+	}
+	if joinRequest.Status != domain.JoinRequestStatusPending {
+		return nil, domain.ErrJoinRequestNotPending
+	}
+	return joinRequest, nil
+}
+
+// publishJoinRequestReviewed tells the requester the outcome and lets the other admins drop the request.
+func (c *conversationJoinRequestUseCase) publishJoinRequestReviewed(ctx context.Context, joinRequest *domain.ConversationJoinRequest) error {
+	adminIDs, err := c.conversationRepository.GetListConversationAdminIDs(ctx, joinRequest.ConversationID)
+	if err != nil {
+		return err
+	}
+	return publishUserEvent(ctx, c.messagePublisher, domain.WsJoinRequestReviewed, map[string]any{
+		"join_request_id": joinRequest.ID,
+		"conversation_id": joinRequest.ConversationID,
+		"user_id":         joinRequest.UserID,
+		"status":          joinRequest.Status,
+		"reviewed_by":     joinRequest.ReviewedBy,
+	}, append(adminIDs, joinRequest.UserID)...)
+}
+
+// GetListJoinRequest implements ConversationJoinRequestUseCase.
+func (c *conversationJoinRequestUseCase) GetListJoinRequest(ctx context.Context, userID string, conversationID string) ([]*presenter.JoinRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationJoinRequestUsecase.GetListJoinRequest")
+	defer span()
+
+	_, err := c.checkConversationAdmin(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinRequests, err := c.joinRequestRepository.GetListPendingJoinRequestWithUser(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinRequestResponses := make([]*presenter.JoinRequestResponse, 0)
+	for _, joinRequest := range joinRequests {
+		joinRequestResponses = append(joinRequestResponses, newJoinRequestResponse(&joinRequest.ConversationJoinRequest, &domain.UserInfo{
+			FullName: joinRequest.FullName,
+			Avatar:   joinRequest.Avatar,
+			Type:     joinRequest.UserType,
+		}))
+	}
+	avatars := userAvatars{}
+	for _, joinRequestResponse := range joinRequestResponses {
+		avatars.add(joinRequestResponse.UserID, &joinRequestResponse.Avatar)
+	}
+	err = hideAvatars(ctx, c.userRepository, c.contactRepository, userID, avatars)
+	if err != nil {
+		return nil, err
+	}
+	return joinRequestResponses, nil
+}
+
+// ApproveJoinRequest implements ConversationJoinRequestUseCase.
+func (c *conversationJoinRequestUseCase) ApproveJoinRequest(ctx context.Context, userID string, conversationID string, joinRequestID string) (*presenter.JoinRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationJoinRequestUsecase.ApproveJoinRequest")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	conversation, err := c.checkConversationAdmin(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	joinRequest, err := c.getPendingJoinRequest(ctx, conversationID, joinRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the requester may have been added by an admin in the meantime
+	isMember, err := c.conversationRepository.CheckIsMemberOfConversation(ctx, joinRequest.UserID, conversationID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	joinRequest.Status = domain.JoinRequestStatusApproved
+	joinRequest.ReviewedBy = &userID
+	joinRequest.ReviewedAt = pointer.ToPtr(time.Now())
+	var conversationMember *domain.ConversationMember
+	if !isMember {
+		conversationMemberID, err := uuid.NewID()
+		if err != nil {
+			return nil, err
+		}
+		conversationMember = &domain.ConversationMember{
+			ID:             conversationMemberID,
+			ConversationID: conversationID,
+			UserID:         joinRequest.UserID,
+			Role:           domain.ConversationMemberRoleMember,
+			CreatedAt:      pointer.ToPtr(time.Now()),
+			UpdatedAt:      pointer.ToPtr(time.Now()),
+		}
+	}
+	err = c.joinRequestRepository.ApproveJoinRequest(ctx, joinRequest, conversationMember)
+	if err != nil {
+		logger.Error("error approve join request", err, joinRequest)
+		return nil, err
+	}
+
+	if conversationMember != nil {
+		// channels do not announce their new subscribers
+		if conversation.Type == domain.ConversationTypeChannel {
+			err = publishUserEvent(ctx, c.messagePublisher, domain.WsChannelSubscribed, map[string]any{
+				"conversation_id": conversationID,
+			}, joinRequest.UserID)
+		} else {
+			err = c.messageSender.publishMemberJoined(ctx, conversationMember, map[string]any{
+				"join_request_id": joinRequest.ID,
+				"approved_by":     userID,
+			})
+		}
+		if err != nil {
+			logger.Error("error publish member joined", err, conversationMember)
+		}
+	}
+
+	err = c.publishJoinRequestReviewed(ctx, joinRequest)
+	if err != nil {
+		logger.Error("error publish join request reviewed", err, joinRequest)
+	}
+	return newJoinRequestResponse(joinRequest, nil), nil
+}
+
+// RejectJoinRequest implements ConversationJoinRequestUseCase.
+func (c *conversationJoinRequestUseCase) RejectJoinRequest(ctx context.Context, userID string, conversationID string, joinRequestID string) (*presenter.JoinRequestResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationJoinRequestUsecase.RejectJoinRequest")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	_, err := c.checkConversationAdmin(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	joinRequest, err := c.getPendingJoinRequest(ctx, conversationID, joinRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	joinRequest.Status = domain.JoinRequestStatusRejected
+	joinRequest.ReviewedBy = &userID
+	joinRequest.ReviewedAt = pointer.ToPtr(time.Now())
+	err = c.joinRequestRepository.RejectJoinRequest(ctx, joinRequest)
+	if err != nil {
+		logger.Error("error reject join request", err, joinRequest)
+		return nil, err
+	}
+
+	err = c.publishJoinRequestReviewed(ctx, joinRequest)
+	if err != nil {
+		logger.Error("error publish join request reviewed", err, joinRequest)
+	}
+	return newJoinRequestResponse(joinRequest, nil), nil
+}
+
+var _ ConversationJoinRequestUseCase = &conversationJoinRequestUseCase{}
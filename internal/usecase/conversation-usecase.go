@@ -3,28 +3,49 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/chat-socio/backend/internal/domain"
 	"github.com/chat-socio/backend/internal/presenter"
 	"github.com/chat-socio/backend/pkg/observability"
 	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/storage"
 	"github.com/chat-socio/backend/pkg/uuid"
 	"github.com/chat-socio/backend/pubsub"
 	"github.com/jackc/pgx/v5"
 )
 
 type ConversationUseCase interface {
-	GetListConversationByUserID(ctx context.Context, userID string, lastMessageID string, limit int) ([]*presenter.GetListConversationResponse, error)
+	GetListConversationByUserID(ctx context.Context, userID string, archived bool, folderID string, lastMessageID string, limit int) ([]*presenter.GetListConversationResponse, error)
 	GetConversationByID(ctx context.Context, conversationID string) (*presenter.ConversationResponse, error)
-	GetListMessageByConversationID(ctx context.Context, userID string, conversationID string, lastMessageID string, limit int) ([]*presenter.MessageResponse, error)
+	// GetConversationDetail returns the conversation with the membership of the user, who must be a member.
+	GetConversationDetail(ctx context.Context, userID string, conversationID string) (*presenter.ConversationResponse, error)
+	GetListConversationMember(ctx context.Context, userID string, conversationID string, keyword string, lastUserID string, limit int) ([]*presenter.ConversationMemberResponse, error)
+	GetListMessageAttachment(ctx context.Context, request *presenter.GetListAttachmentRequest) ([]*presenter.MessageAttachmentResponse, error)
+	GetListMessageByConversationID(ctx context.Context, request *presenter.GetListMessageRequest) ([]*presenter.MessageResponse, error)
 	CreateConversation(ctx context.Context, conversation *presenter.CreateConversationRequest) (*presenter.ConversationResponse, error)
+	UpdateConversation(ctx context.Context, request *presenter.UpdateConversationRequest) (*presenter.GetListConversationResponse, error)
 	SendMessage(ctx context.Context, message *presenter.SendMessageRequest) (*presenter.MessageResponse, error)
 	HandleNewMessage(ctx context.Context, message *domain.WebSocketMessage) error
 	HandleUpdateLastMessageID(ctx context.Context, data domain.UpdateLastMessageID) error
 	SeenMessage(ctx context.Context, messageID string, userID string, conversationID string) error
 	GetListSeenMessageByConversationID(ctx context.Context, conversationID string) ([]*presenter.SeenMessageResponse, error)
 	HandleSeenMessage(ctx context.Context, message *domain.SeenMessage) error
+	GetNotificationSetting(ctx context.Context, userID string, conversationID string) (*presenter.NotificationSettingResponse, error)
+	UpdateNotificationSetting(ctx context.Context, request *presenter.UpdateNotificationSettingRequest) (*presenter.NotificationSettingResponse, error)
+	GetUnreadCount(ctx context.Context, userID string) (*presenter.UnreadCountResponse, error)
+	ArchiveConversation(ctx context.Context, userID string, conversationID string) error
+	UnarchiveConversation(ctx context.Context, userID string, conversationID string) error
+	DeleteConversationForMe(ctx context.Context, userID string, conversationID string) error
+	PinConversation(ctx context.Context, userID string, conversationID string) error
+	UnpinConversation(ctx context.Context, userID string, conversationID string) error
+	AddReaction(ctx context.Context, request *presenter.ReactionRequest) ([]*presenter.ReactionResponse, error)
+	RemoveReaction(ctx context.Context, request *presenter.ReactionRequest) ([]*presenter.ReactionResponse, error)
+	SubscribeChannels(ctx context.Context, userID string, connectionID string) error
 }
 
 type conversationUseCase struct {
@@ -34,7 +55,16 @@ type conversationUseCase struct {
 	userOnlineRepository   domain.UserOnlineRepository
 	userRepository         domain.UserRepository
 	seenMessageRepository  domain.SeenMessageRepository
+	folderRepository       domain.ConversationFolderRepository
+	reactionRepository     domain.MessageReactionRepository
+	messageViewRepository  domain.MessageViewRepository
+	rateLimitRepository    domain.MessageRateLimitRepository
+	userBlockRepository    domain.UserBlockRepository
+	contactRepository      domain.ContactRepository
+	objectStorage          storage.ObjectStorage
+	messageSender          *messageSender
 	obs                    *observability.Observability
+	conversationTypes      sync.Map // conversation id -> type, the type of a conversation never changes
 }
 
 func (c *conversationUseCase) HandleSeenMessage(ctx context.Context, message *domain.SeenMessage) error {
@@ -46,6 +76,10 @@ func (c *conversationUseCase) HandleSeenMessage(ctx context.Context, message *do
 	}
 	message.ID = id
 	err = c.seenMessageRepository.CreateSeenMessage(ctx, message)
+	if err == pgx.ErrNoRows {
+		// the pointer is already past this message
+		return nil
+	}
 	if err != nil {
 		logger.Error("failed to upsert seen message", err, message)
 		return err
@@ -55,6 +89,7 @@ func (c *conversationUseCase) HandleSeenMessage(ctx context.Context, message *do
 		Payload: map[string]any{
 			"conversation_id": message.ConversationID,
 			"message_id":      message.MessageID,
+			"message_seq":     message.MessageSeq,
 			"user_id":         message.UserID,
 		},
 	}
@@ -80,6 +115,7 @@ func (c *conversationUseCase) GetListSeenMessageByConversationID(ctx context.Con
 	for _, seenMessage := range seenMessages {
 		seenMessageResponses = append(seenMessageResponses, &presenter.SeenMessageResponse{
 			MessageID:      seenMessage.MessageID,
+			MessageSeq:     seenMessage.MessageSeq,
 			UserID:         seenMessage.UserID,
 			ConversationID: seenMessage.ConversationID,
 			CreatedAt:      seenMessage.CreatedAt,
@@ -114,7 +150,12 @@ func (c *conversationUseCase) HandleUpdateLastMessageID(ctx context.Context, dat
 		logger.Error("error update last message id", err, data)
 		return err
 	}
-	conversation, _, err := c.conversationRepository.GetConversationByID(ctx, data.ConversationID)
+	err = c.conversationRepository.UnarchiveConversation(ctx, data.ConversationID, time.Now())
+	if err != nil {
+		logger.Error("error unarchive conversation", err, data)
+		return err
+	}
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, data.ConversationID)
 	if err != nil {
 		logger.Error("error get conversation by id", err, data)
 		return err
@@ -131,6 +172,8 @@ func (c *conversationUseCase) HandleUpdateLastMessageID(ctx context.Context, dat
 		logger.Error("error convert message to map", err, message)
 		return err
 	}
+	// the members are not all contacts of the sender
+	userMap["avatar"] = publicAvatar(message.User)
 	messageMap, err := pointer.ToMap(message)
 	if err != nil {
 		logger.Error("error convert message to map", err, message)
@@ -155,8 +198,39 @@ func (c *conversationUseCase) HandleUpdateLastMessageID(ctx context.Context, dat
 	return c.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, wsMessage)
 }
 
+func (c *conversationUseCase) getConversationType(ctx context.Context, conversationID string) (string, error) {
+	if conversationType, ok := c.conversationTypes.Load(conversationID); ok {
+		return conversationType.(string), nil
+	}
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return "", err
+	}
+	c.conversationTypes.Store(conversationID, conversation.Type)
+	return conversation.Type, nil
+}
+
+// getUserOnlineByConversationID returns the connections receiving the events of the conversation.
+// The subscribers of a channel are taken from the subscriptions of the local connections,
+// so a post does not load every subscriber of the channel.
 func (c *conversationUseCase) getUserOnlineByConversationID(ctx context.Context, conversationID string) ([]*domain.UserOnline, error) {
-	return c.userOnlineRepository.GetUserOnlineByConversationID(ctx, conversationID)
+	conversationType, err := c.getConversationType(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversationType != domain.ConversationTypeChannel {
+		return c.userOnlineRepository.GetUserOnlineByConversationID(ctx, conversationID)
+	}
+
+	connections := domain.WebSocket.GetTopicConnections(conversationID)
+	userOnlines := make([]*domain.UserOnline, 0, len(connections))
+	for _, connection := range connections {
+		userOnlines = append(userOnlines, &domain.UserOnline{
+			UserID:       connection.GetUserID(),
+			ConnectionID: connection.GetID(),
+		})
+	}
+	return userOnlines, nil
 }
 
 func (c *conversationUseCase) handleSendEventNewMessage(ctx context.Context, message *domain.WebSocketMessage) error {
@@ -173,6 +247,15 @@ func (c *conversationUseCase) handleSendEventNewMessage(ctx context.Context, mes
 		mapIgnoreUserOnlines[uo] = true
 	}
 
+	var mapNotify, mapSenderBlocked map[string]bool
+	if message.Type == domain.WsMessage {
+		mapNotify, mapSenderBlocked, err = c.getNotifyByUserID(ctx, message, userOnlines)
+		if err != nil {
+			logger.Error("error get notification setting of members", err, message)
+			return err
+		}
+	}
+
 	// send message to websocket
 	for _, userOnline := range userOnlines {
 		// exclude user who send message
@@ -184,7 +267,19 @@ func (c *conversationUseCase) handleSendEventNewMessage(ctx context.Context, mes
 			continue
 		}
 
-		b, err := json.Marshal(message)
+		recipientMessage := message
+		if mapNotify != nil {
+			recipientMessage = &domain.WebSocketMessage{
+				Type:    message.Type,
+				Payload: maps.Clone(message.Payload),
+			}
+			recipientMessage.Payload["notify"] = mapNotify[userOnline.UserID]
+			if mapSenderBlocked[userOnline.UserID] {
+				recipientMessage.Payload["sender_blocked"] = true
+			}
+		}
+
+		b, err := json.Marshal(recipientMessage)
 		if err != nil {
 			logger.Error("failed to marshal message to json", err, message)
 			continue
@@ -198,6 +293,86 @@ func (c *conversationUseCase) handleSendEventNewMessage(ctx context.Context, mes
 	return nil
 }
 
+// getNotifyByUserID tells for each online member whether the new message must alert them,
+// according to their notification settings, and which of them blocked the sender.
+func (c *conversationUseCase) getNotifyByUserID(ctx context.Context, message *domain.WebSocketMessage, userOnlines []*domain.UserOnline) (map[string]bool, map[string]bool, error) {
+	b, err := json.Marshal(message.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	var messageDomain domain.Message
+	err = json.Unmarshal(b, &messageDomain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userIDs := make([]string, 0, len(userOnlines))
+	for _, userOnline := range userOnlines {
+		if !slices.Contains(userIDs, userOnline.UserID) {
+			userIDs = append(userIDs, userOnline.UserID)
+		}
+	}
+	conversationMembers, err := c.conversationRepository.GetListConversationMemberByUserIDs(ctx, messageDomain.ConversationID, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockingUserIDs, err := c.userBlockRepository.GetListUserIDBlocking(ctx, messageDomain.UserID, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapSenderBlocked := make(map[string]bool, len(blockingUserIDs))
+	for _, userID := range blockingUserIDs {
+		mapSenderBlocked[userID] = true
+	}
+
+	now := time.Now()
+	mapNotify := make(map[string]bool, len(conversationMembers))
+	for _, conversationMember := range conversationMembers {
+		mapNotify[conversationMember.UserID] = conversationMember.ShouldNotify(&messageDomain, mapSenderBlocked[conversationMember.UserID], now)
+	}
+	return mapNotify, mapSenderBlocked, nil
+}
+
+// handleSendEventToUsers sends the event to every connection of the targeted users.
+func (c *conversationUseCase) handleSendEventToUsers(ctx context.Context, message *domain.WebSocketMessage) error {
+	logger := c.obs.Logger.WithContext(ctx)
+	userOnlines, err := c.userOnlineRepository.GetUserOnlineByUserIDs(ctx, message.UserIDs)
+	if err != nil {
+		logger.Error("error get user online by user ids", err, message)
+		return err
+	}
+
+	b, err := json.Marshal(&domain.WebSocketMessage{
+		Type:    message.Type,
+		Payload: message.Payload,
+	})
+	if err != nil {
+		logger.Error("failed to marshal message to json", err, message)
+		return err
+	}
+	for _, userOnline := range userOnlines {
+		wsConn, ok := domain.WebSocket.GetConnection(userOnline.ConnectionID)
+		if !ok {
+			continue
+		}
+		switch message.Type {
+		case domain.WsChannelSubscribed:
+			domain.WebSocket.Subscribe(message.Payload["conversation_id"].(string), wsConn.GetID())
+		case domain.WsChannelUnsubscribed:
+			domain.WebSocket.Unsubscribe(message.Payload["conversation_id"].(string), wsConn.GetID())
+		}
+		err = wsConn.SendMessage(b)
+		if err != nil {
+			logger.Error("failed to send message to websocket", err, message)
+		}
+		// the read loop of the connection stops and cleans it up
+		if message.Type == domain.WsSessionRevoked {
+			wsConn.Close()
+		}
+	}
+	return nil
+}
+
 func (c *conversationUseCase) handleSendEventUpdateLastMessageID(ctx context.Context, message *domain.WebSocketMessage) error {
 	// get user online by conversation id
 	logger := c.obs.Logger.WithContext(ctx)
@@ -237,6 +412,9 @@ func (c *conversationUseCase) handleSendEventUpdateLastMessageID(ctx context.Con
 
 // HandleNewMessage implements ConversationUseCase.
 func (c *conversationUseCase) HandleNewMessage(ctx context.Context, message *domain.WebSocketMessage) error {
+	if len(message.UserIDs) > 0 {
+		return c.handleSendEventToUsers(ctx, message)
+	}
 	switch message.Type {
 	case domain.WsMessage:
 		return c.handleSendEventNewMessage(ctx, message)
@@ -244,11 +422,13 @@ func (c *conversationUseCase) HandleNewMessage(ctx context.Context, message *dom
 		return c.handleSendEventUpdateLastMessageID(ctx, message)
 	case domain.WsSeenMessage:
 		return c.handleSendEventNewMessage(ctx, message)
+	case domain.WsConversationUpdated, domain.WsMemberJoined, domain.WsReactionUpdated, domain.WsConversationDeleted:
+		return c.handleSendEventNewMessage(ctx, message)
 	}
 	return nil
 }
 
-func NewConversationUseCase(conversationRepository domain.ConversationRepository, messageRepository domain.MessageRepository, messagePublisher pubsub.Publisher, userOnlineRepository domain.UserOnlineRepository, userRepository domain.UserRepository, seenMessageRepository domain.SeenMessageRepository, obs *observability.Observability) ConversationUseCase {
+func NewConversationUseCase(conversationRepository domain.ConversationRepository, messageRepository domain.MessageRepository, messagePublisher pubsub.Publisher, userOnlineRepository domain.UserOnlineRepository, userRepository domain.UserRepository, seenMessageRepository domain.SeenMessageRepository, folderRepository domain.ConversationFolderRepository, reactionRepository domain.MessageReactionRepository, messageViewRepository domain.MessageViewRepository, rateLimitRepository domain.MessageRateLimitRepository, userBlockRepository domain.UserBlockRepository, contactRepository domain.ContactRepository, objectStorage storage.ObjectStorage, obs *observability.Observability) ConversationUseCase {
 	return &conversationUseCase{
 		conversationRepository: conversationRepository,
 		messageRepository:      messageRepository,
@@ -256,61 +436,277 @@ func NewConversationUseCase(conversationRepository domain.ConversationRepository
 		userOnlineRepository:   userOnlineRepository,
 		userRepository:         userRepository,
 		seenMessageRepository:  seenMessageRepository,
+		folderRepository:       folderRepository,
+		reactionRepository:     reactionRepository,
+		messageViewRepository:  messageViewRepository,
+		rateLimitRepository:    rateLimitRepository,
+		userBlockRepository:    userBlockRepository,
+		contactRepository:      contactRepository,
+		objectStorage:          objectStorage,
+		messageSender:          newMessageSender(messageRepository, userRepository, messagePublisher, obs),
 		obs:                    obs,
 	}
 }
 
 // CreateConversation implements ConversationUseCase.
 func (c *conversationUseCase) CreateConversation(ctx context.Context, conversation *presenter.CreateConversationRequest) (*presenter.ConversationResponse, error) {
+	var dmKey *string
+	if conversation.Type == domain.ConversationTypeDM {
+		// a user only opens their own DMs, the DM of two other users is neither returned nor created
+		if !slices.Contains(conversation.Members, conversation.UserID) {
+			return nil, domain.ErrPermissionDenied
+		}
+		blocked, err := c.userBlockRepository.IsBlockedBetween(ctx, conversation.Members[0], conversation.Members[1])
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, domain.ErrUserBlocked
+		}
+		dmKey = pointer.ToPtr(domain.NewDMKey(conversation.Members[0], conversation.Members[1]))
+		// a DM between two users is unique, return it instead of creating another one
+		existingConversation, err := c.getConversationByDMKey(ctx, conversation.UserID, *dmKey)
+		if err != nil || existingConversation != nil {
+			return existingConversation, err
+		}
+	}
+	err := c.checkMemberPrivacy(ctx, conversation.UserID, conversation.Type, conversation.Members)
+	if err != nil {
+		return nil, err
+	}
+
 	conversationID, err := uuid.NewID()
 	if err != nil {
 		return nil, err
 	}
 	conversationDomain := &domain.Conversation{
-		ID:        conversationID,
-		Type:      conversation.Type,
-		Title:     conversation.Title,
-		Avatar:    conversation.Avatar,
-		CreatedAt: pointer.ToPtr(time.Now()),
-		UpdatedAt: pointer.ToPtr(time.Now()),
+		ID:              conversationID,
+		Type:            conversation.Type,
+		Title:           conversation.Title,
+		Avatar:          conversation.Avatar,
+		DMKey:           dmKey,
+		IsPublic:        conversation.IsPublic,
+		Description:     conversation.Description,
+		RequireApproval: conversation.RequireApproval,
+		CreatedAt:       pointer.ToPtr(time.Now()),
+		UpdatedAt:       pointer.ToPtr(time.Now()),
+	}
+	isOwned := conversation.Type == domain.ConversationTypeGroup || conversation.Type == domain.ConversationTypeChannel
+	memberIDs := conversation.Members
+	if isOwned && conversation.UserID != "" && !slices.Contains(memberIDs, conversation.UserID) {
+		memberIDs = append(memberIDs, conversation.UserID)
 	}
 	conversationMembers := make([]*domain.ConversationMember, 0)
 	conversationMemberResponses := make([]*presenter.ConversationMemberResponse, 0)
-	for _, userID := range conversation.Members {
+	for _, userID := range memberIDs {
 		conversationMemberID, err := uuid.NewID()
 		if err != nil {
 			return nil, err
 		}
+		// the creator owns a group or a channel, everybody else joins as a plain member
+		role := domain.ConversationMemberRoleMember
+		if isOwned && userID == conversation.UserID {
+			role = domain.ConversationMemberRoleOwner
+		}
 		conversationMembers = append(conversationMembers, &domain.ConversationMember{
 			ID:             conversationMemberID,
 			ConversationID: conversationID,
 			UserID:         userID,
+			Role:           role,
 			CreatedAt:      pointer.ToPtr(time.Now()),
 			UpdatedAt:      pointer.ToPtr(time.Now()),
 		})
 		conversationMemberResponses = append(conversationMemberResponses, &presenter.ConversationMemberResponse{
 			UserID: userID,
+			Role:   role,
 		})
 	}
 	conversationDomain, err = c.conversationRepository.CreateConversation(ctx, conversationDomain, conversationMembers)
+	if err == domain.ErrConversationAlreadyExists && dmKey != nil {
+		// the same DM was created concurrently
+		return c.getConversationByDMKey(ctx, conversation.UserID, *dmKey)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if conversationDomain.Type == domain.ConversationTypeChannel {
+		err = publishUserEvent(ctx, c.messagePublisher, domain.WsChannelSubscribed, map[string]any{
+			"conversation_id": conversationDomain.ID,
+		}, memberIDs...)
+		if err != nil {
+			c.obs.Logger.WithContext(ctx).Error("error publish channel subscribed", err, conversationDomain)
+		}
+	}
 	return &presenter.ConversationResponse{
 		ConversationID: conversationDomain.ID,
 		Type:           conversationDomain.Type,
 		Title:          conversationDomain.Title,
 		Avatar:         conversationDomain.Avatar,
+		IsPublic:       conversationDomain.IsPublic,
+		Description:    conversationDomain.Description,
 		Members:        conversationMemberResponses,
 	}, nil
 }
 
+// checkMemberPrivacy returns a *domain.PrivacyError listing the members who do not let the user
+// start a DM with them, or add them to a group or a channel.
+func (c *conversationUseCase) checkMemberPrivacy(ctx context.Context, userID string, conversationType string, memberIDs []string) error {
+	if userID == "" {
+		return nil
+	}
+	otherMemberIDs := slices.DeleteFunc(slices.Clone(memberIDs), func(memberID string) bool {
+		return memberID == userID
+	})
+	if len(otherMemberIDs) == 0 {
+		return nil
+	}
+	users, err := c.userRepository.GetListUserByIDs(ctx, otherMemberIDs)
+	if err != nil {
+		return err
+	}
+	friendIDs, err := c.contactRepository.GetListFriendID(ctx, userID, otherMemberIDs)
+	if err != nil {
+		return err
+	}
+
+	reason := domain.PrivacyReasonGroupAdd
+	if conversationType == domain.ConversationTypeDM {
+		reason = domain.PrivacyReasonDirectMessage
+	}
+	var refusedUserIDs []string
+	for _, user := range users {
+		privacy := user.GroupAddPrivacy
+		if conversationType == domain.ConversationTypeDM {
+			privacy = user.DirectMessagePrivacy
+		}
+		if !domain.PrivacyAllows(privacy, slices.Contains(friendIDs, user.ID)) {
+			refusedUserIDs = append(refusedUserIDs, user.ID)
+		}
+	}
+	if len(refusedUserIDs) > 0 {
+		return &domain.PrivacyError{Reason: reason, UserIDs: refusedUserIDs}
+	}
+	return nil
+}
+
+// getConversationByDMKey returns nil when there is no DM for the key yet.
+func (c *conversationUseCase) getConversationByDMKey(ctx context.Context, userID string, dmKey string) (*presenter.ConversationResponse, error) {
+	conversation, err := c.conversationRepository.GetConversationByDMKey(ctx, dmKey)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	conversationResponse, err := c.GetConversationByID(ctx, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = hideMemberAvatars(ctx, c.userRepository, c.contactRepository, userID, conversationResponse.Members)
+	if err != nil {
+		return nil, err
+	}
+	return conversationResponse, nil
+}
+
+// conversationMemberPageSize is the number of members sent with a conversation,
+// the others are fetched page by page.
+const conversationMemberPageSize = 50
+
+// getConversationResponse returns the conversation with its member count and the first page of its members,
+// the subscribers of a channel are not listed.
+func getConversationResponse(ctx context.Context, conversationRepository domain.ConversationRepository, conversation *domain.Conversation) (*presenter.ConversationResponse, error) {
+	memberCount, err := conversationRepository.CountConversationMember(ctx, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+	var conversationMembers []*domain.ConversationMemberWithUser
+	if conversation.Type != domain.ConversationTypeChannel {
+		conversationMembers, err = conversationRepository.GetListConversationMemberWithUser(ctx, conversation.ID, "", "", conversationMemberPageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	conversationResponse := newConversationResponse(conversation, conversationMembers)
+	conversationResponse.MemberCount = memberCount
+	return conversationResponse, nil
+}
+
 // GetConversationByID implements ConversationUseCase.
 func (c *conversationUseCase) GetConversationByID(ctx context.Context, conversationID string) (*presenter.ConversationResponse, error) {
-	conversation, conversationMembers, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return getConversationResponse(ctx, c.conversationRepository, conversation)
+}
+
+// GetConversationDetail implements ConversationUseCase.
+func (c *conversationUseCase) GetConversationDetail(ctx context.Context, userID string, conversationID string) (*presenter.ConversationResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetConversationDetail")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conversationResponse, err := c.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	err = hideMemberAvatars(ctx, c.userRepository, c.contactRepository, userID, conversationResponse.Members)
+	if err != nil {
+		return nil, err
+	}
+	conversationResponse.Membership = &presenter.MembershipResponse{
+		Role:                conversationMember.Role,
+		JoinedAt:            conversationMember.CreatedAt,
+		NotificationSetting: newNotificationSettingResponse(conversationMember),
+		ArchivedAt:          conversationMember.ArchivedAt,
+		PinnedAt:            conversationMember.PinnedAt,
+		HistoryClearedAt:    conversationMember.HistoryClearedAt,
+	}
+	return conversationResponse, nil
+}
+
+// GetListConversationMember implements ConversationUseCase.
+func (c *conversationUseCase) GetListConversationMember(ctx context.Context, userID string, conversationID string, keyword string, lastUserID string, limit int) ([]*presenter.ConversationMemberResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetListConversationMember")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return nil, err
+	}
+	conversationType, err := c.getConversationType(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	// only the admins of a channel see who subscribed
+	if conversationType == domain.ConversationTypeChannel && !conversationMember.IsAdmin() {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	conversationMembers, err := c.conversationRepository.GetListConversationMemberWithUser(ctx, conversationID, keyword, lastUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	conversationMemberResponses := newConversationMemberResponses(conversationMembers)
+	err = hideMemberAvatars(ctx, c.userRepository, c.contactRepository, userID, conversationMemberResponses)
 	if err != nil {
 		return nil, err
 	}
+	return conversationMemberResponses, nil
+}
+
+func newConversationMemberResponses(conversationMembers []*domain.ConversationMemberWithUser) []*presenter.ConversationMemberResponse {
 	conversationMemberResponses := make([]*presenter.ConversationMemberResponse, 0)
 	for _, conversationMember := range conversationMembers {
 		conversationMemberResponses = append(conversationMemberResponses, &presenter.ConversationMemberResponse{
@@ -318,38 +714,90 @@ func (c *conversationUseCase) GetConversationByID(ctx context.Context, conversat
 			FullName: conversationMember.FullName,
 			Avatar:   conversationMember.Avatar,
 			UserType: conversationMember.UserType,
+			Role:     conversationMember.Role,
+			Status:   newUserStatusResponse(conversationMember.StatusEmoji, conversationMember.StatusText, conversationMember.StatusExpiresAt),
 		})
 	}
+	return conversationMemberResponses
+}
+
+func newConversationResponse(conversation *domain.Conversation, conversationMembers []*domain.ConversationMemberWithUser) *presenter.ConversationResponse {
+	conversationMemberResponses := newConversationMemberResponses(conversationMembers)
 	return &presenter.ConversationResponse{
-		ConversationID: conversation.ID,
-		Type:           conversation.Type,
-		Title:          conversation.Title,
-		Avatar:         conversation.Avatar,
-		Members:        conversationMemberResponses,
-	}, nil
+		ConversationID:    conversation.ID,
+		Type:              conversation.Type,
+		Title:             conversation.Title,
+		Avatar:            conversation.Avatar,
+		LastMessageSeq:    conversation.LastMessageSeq,
+		IsPublic:          conversation.IsPublic,
+		Description:       conversation.Description,
+		RequireApproval:   conversation.RequireApproval,
+		SlowModeSeconds:   conversation.SlowModeSeconds,
+		DailyMessageLimit: conversation.DailyMessageLimit,
+		Members:           conversationMemberResponses,
+	}
 }
 
 // GetListConversationByUserID implements ConversationUseCase.
-func (c *conversationUseCase) GetListConversationByUserID(ctx context.Context, userID string, lastMessageID string, limit int) ([]*presenter.GetListConversationResponse, error) {
-	conversations, err := c.conversationRepository.GetListConversationByUserID(ctx, userID, lastMessageID, limit)
+func (c *conversationUseCase) GetListConversationByUserID(ctx context.Context, userID string, archived bool, folderID string, lastMessageID string, limit int) ([]*presenter.GetListConversationResponse, error) {
+	filter := &domain.ConversationFilter{Archived: archived}
+	if folderID != "" {
+		folder, err := c.folderRepository.GetFolderByID(ctx, folderID)
+		if err != nil && err != pgx.ErrNoRows {
+			return nil, err
+		}
+		if err == pgx.ErrNoRows || folder.UserID != userID {
+			return nil, domain.ErrConversationFolderNotFound
+		}
+		filter.Folder = folder
+	}
+
+	conversations, err := c.conversationRepository.GetListConversationByUserID(ctx, userID, filter, lastMessageID, limit)
+	if err != nil {
+		return nil, err
+	}
+	// pinned conversations come first on the first page only,
+	// the other pages keep paginating on the last message id
+	if !archived && lastMessageID == "" {
+		pinnedConversations, err := c.conversationRepository.GetListConversationByUserID(ctx, userID, &domain.ConversationFilter{
+			Pinned: true,
+			Folder: filter.Folder,
+		}, "", domain.MaxPinnedConversation)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(pinnedConversations, conversations...)
+	}
+	blockedUserIDs, err := c.userBlockRepository.GetListBlockedUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	conversationResponses := make([]*presenter.GetListConversationResponse, 0)
+	avatars := userAvatars{}
 	for _, conversation := range conversations {
 		conversationResponse := &presenter.GetListConversationResponse{
-			ConversationID: conversation.ID,
-			Type:           conversation.Type,
-			Title:          conversation.Title,
-			Avatar:         conversation.Avatar,
-			LastMessageID:  conversation.LastMessageID,
-			CreatedAt:      conversation.CreatedAt,
-			UpdatedAt:      conversation.UpdatedAt,
+			ConversationID:    conversation.ID,
+			Type:              conversation.Type,
+			Title:             conversation.Title,
+			Avatar:            conversation.Avatar,
+			LastMessageID:     conversation.LastMessageID,
+			LastMessageSeq:    conversation.LastMessageSeq,
+			CreatedAt:         conversation.CreatedAt,
+			UpdatedAt:         conversation.UpdatedAt,
+			UnreadCount:       conversation.UnreadCount,
+			SlowModeSeconds:   conversation.SlowModeSeconds,
+			DailyMessageLimit: conversation.DailyMessageLimit,
+		}
+		if conversation.Membership != nil {
+			conversationResponse.NotificationSetting = newNotificationSettingResponse(conversation.Membership)
+			conversationResponse.ArchivedAt = conversation.Membership.ArchivedAt
+			conversationResponse.PinnedAt = conversation.Membership.PinnedAt
 		}
 
 		if conversation.LastMessage != nil {
 			conversationResponse.LastMessage = &presenter.MessageResponse{
 				MessageID:      conversation.LastMessage.ID,
+				Seq:            conversation.LastMessage.Seq,
 				Body:           conversation.LastMessage.Body,
 				CreatedAt:      conversation.LastMessage.CreatedAt,
 				UpdatedAt:      conversation.LastMessage.UpdatedAt,
@@ -363,7 +811,10 @@ func (c *conversationUseCase) GetListConversationByUserID(ctx context.Context, u
 					Avatar:   conversation.LastMessage.User.Avatar,
 					UserType: conversation.LastMessage.User.Type,
 				},
+				IsBot:         conversation.LastMessage.User.Type == domain.InternalUserType,
+				SenderBlocked: slices.Contains(blockedUserIDs, conversation.LastMessage.UserID),
 			}
+			avatars.add(conversationResponse.LastMessage.User.UserID, &conversationResponse.LastMessage.User.Avatar)
 		}
 		if len(conversation.Members) > 0 {
 			for _, member := range conversation.Members {
@@ -374,36 +825,85 @@ func (c *conversationUseCase) GetListConversationByUserID(ctx context.Context, u
 					UserType: member.Type,
 				})
 			}
+			for _, member := range conversationResponse.Members {
+				avatars.add(member.UserID, &member.Avatar)
+			}
 		}
 		conversationResponses = append(conversationResponses, conversationResponse)
 	}
+	err = hideAvatars(ctx, c.userRepository, c.contactRepository, userID, avatars)
+	if err != nil {
+		return nil, err
+	}
 	return conversationResponses, nil
 }
 
 // GetListMessageByConversationID implements ConversationUseCase.
-func (c *conversationUseCase) GetListMessageByConversationID(ctx context.Context, userID string, conversationID string, lastMessageID string, limit int) ([]*presenter.MessageResponse, error) {
+func (c *conversationUseCase) GetListMessageByConversationID(ctx context.Context, request *presenter.GetListMessageRequest) ([]*presenter.MessageResponse, error) {
 	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetListMessageByConversationID")
 	defer span()
+	userID, conversationID := request.UserID, request.ConversationID
 	// check is member of conversation
-	isMember, err := c.conversationRepository.CheckIsMemberOfConversation(ctx, userID, conversationID)
-	if err != nil && err != pgx.ErrNoRows {
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
 		return nil, err
 	}
-	if !isMember {
-		return nil, domain.ErrNotFoundMemberOfConversation
+	beforeSeq := request.BeforeSeq
+	if beforeSeq == 0 && request.LastMessageID != "" {
+		lastMessage, err := c.messageRepository.GetMessageByID(ctx, request.LastMessageID)
+		if err == pgx.ErrNoRows || (err == nil && lastMessage.ConversationID != conversationID) {
+			return nil, domain.ErrMessageNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		beforeSeq = lastMessage.Seq
 	}
-	// get list message by conversation id
-	messages, err := c.messageRepository.GetListMessageByConversationID(ctx, conversationID, lastMessageID, limit)
+	// get list message by conversation id, without the history the member deleted
+	messages, err := c.messageRepository.GetListMessageByConversationID(ctx, &domain.MessageFilter{
+		ConversationID: conversationID,
+		BeforeSeq:      beforeSeq,
+		AfterSeq:       request.AfterSeq,
+		Since:          conversationMember.HistoryClearedAt,
+		Limit:          request.Limit,
+	})
 	if err != nil {
 		return nil, err
 	}
 	if err == pgx.ErrNoRows {
 		return []*presenter.MessageResponse{}, nil
 	}
+
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+	mapReactions, err := c.getReactionsByMessageIDs(ctx, messageIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	mapViews, err := c.viewMessages(ctx, userID, conversationID, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	blockedUserIDs, err := c.userBlockRepository.GetListBlockedUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	messageResponses := make([]*presenter.MessageResponse, 0)
+	avatars := userAvatars{}
 	for _, message := range messages {
+		var viewCount *int64
+		if views, ok := mapViews[message.ID]; ok {
+			viewCount = pointer.ToPtr(views)
+		}
 		messageResponses = append(messageResponses, &presenter.MessageResponse{
 			MessageID:      message.ID,
+			Seq:            message.Seq,
 			Body:           message.Body,
 			CreatedAt:      message.CreatedAt,
 			UpdatedAt:      message.UpdatedAt,
@@ -417,16 +917,97 @@ func (c *conversationUseCase) GetListMessageByConversationID(ctx context.Context
 				Avatar:   message.User.Avatar,
 				UserType: message.User.Type,
 			},
+			Reactions:     mapReactions[message.ID],
+			ViewCount:     viewCount,
+			IsBot:         message.User.Type == domain.InternalUserType,
+			SenderBlocked: slices.Contains(blockedUserIDs, message.UserID),
 		})
 	}
+	for _, messageResponse := range messageResponses {
+		avatars.add(messageResponse.User.UserID, &messageResponse.User.Avatar)
+	}
+	err = hideAvatars(ctx, c.userRepository, c.contactRepository, userID, avatars)
+	if err != nil {
+		return nil, err
+	}
 	return messageResponses, nil
 }
 
+// viewMessages counts the view of the user on the posts of a channel and returns the view counts,
+// nothing is counted for the other conversations.
+func (c *conversationUseCase) viewMessages(ctx context.Context, userID string, conversationID string, messageIDs []string) (map[string]int64, error) {
+	conversationType, err := c.getConversationType(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversationType != domain.ConversationTypeChannel || len(messageIDs) == 0 {
+		return nil, nil
+	}
+	err = c.messageViewRepository.AddMessageViews(ctx, userID, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	return c.messageViewRepository.CountMessageViews(ctx, messageIDs)
+}
+
+func (c *conversationUseCase) getReactionsByMessageIDs(ctx context.Context, messageIDs []string, userID string) (map[string][]*presenter.ReactionResponse, error) {
+	mapReactions := make(map[string][]*presenter.ReactionResponse)
+	if len(messageIDs) == 0 {
+		return mapReactions, nil
+	}
+	summaries, err := c.reactionRepository.GetListReactionSummaryByMessageIDs(ctx, messageIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range summaries {
+		mapReactions[summary.MessageID] = append(mapReactions[summary.MessageID], &presenter.ReactionResponse{
+			Emoji:       summary.Emoji,
+			Count:       summary.Count,
+			ReactedByMe: summary.ReactedByMe,
+		})
+	}
+	return mapReactions, nil
+}
+
 // SendMessage implements ConversationUseCase.
 func (c *conversationUseCase) SendMessage(ctx context.Context, message *presenter.SendMessageRequest) (*presenter.MessageResponse, error) {
 	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.SendMessage")
 	defer span()
 	logger := c.obs.Logger.WithContext(ctx)
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, message.ConversationID, message.UserID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return nil, err
+	}
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	// subscribers of a channel only read and react
+	if conversation.Type == domain.ConversationTypeChannel && !conversationMember.IsAdmin() {
+		return nil, domain.ErrPermissionDenied
+	}
+	// a DM is read-only for both users once one blocked the other
+	if conversation.Type == domain.ConversationTypeDM {
+		blocked, err := c.userBlockRepository.IsBlockedInConversation(ctx, conversation.ID, message.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, domain.ErrUserBlocked
+		}
+	}
+	if conversation.HasSendRateLimit() && !conversationMember.IsAdmin() {
+		slowMode := time.Duration(conversation.SlowModeSeconds) * time.Second
+		err = c.rateLimitRepository.AllowSendMessage(ctx, conversation.ID, message.UserID, slowMode, conversation.DailyMessageLimit, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	messageID, err := uuid.NewID()
 	if err != nil {
 		return nil, err
@@ -441,61 +1022,458 @@ func (c *conversationUseCase) SendMessage(ctx context.Context, message *presente
 		UpdatedAt:      pointer.ToPtr(time.Now()),
 		ReplyTo:        message.ReplyTo,
 	}
+	messageDomain.Attachments = messageDomain.ExtractAttachments()
 	messageDomain, err = c.messageRepository.CreateMessage(ctx, messageDomain)
 	if err != nil {
 		logger.Error("error create message", err, message)
 		return nil, err
 	}
 
-	user, err := c.userRepository.GetUserByID(ctx, message.UserID)
+	err = c.messageSender.publishNewMessage(ctx, messageDomain, []string{message.UserID})
 	if err != nil {
-		logger.Error("error get user by id", err, message)
 		return nil, err
 	}
-	userMap, err := pointer.ToMap(user)
+
+	return &presenter.MessageResponse{
+		MessageID:      messageDomain.ID,
+		Seq:            messageDomain.Seq,
+		Body:           messageDomain.Body,
+		CreatedAt:      messageDomain.CreatedAt,
+		UpdatedAt:      messageDomain.UpdatedAt,
+		Type:           messageDomain.Type,
+		DeletedAt:      messageDomain.DeletedAt,
+		ReplyTo:        messageDomain.ReplyTo,
+		ConversationID: messageDomain.ConversationID,
+	}, nil
+}
+
+// GetListMessageAttachment implements ConversationUseCase.
+func (c *conversationUseCase) GetListMessageAttachment(ctx context.Context, request *presenter.GetListAttachmentRequest) ([]*presenter.MessageAttachmentResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetListMessageAttachment")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, request.ConversationID, request.UserID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
 	if err != nil {
-		logger.Error("error convert user to map", err, user)
 		return nil, err
 	}
 
-	messageMap, err := pointer.ToMap(messageDomain)
+	// what was shared before the user cleared the history is hidden as well
+	attachments, err := c.messageRepository.GetListMessageAttachment(ctx, &domain.MessageAttachmentFilter{
+		ConversationID: request.ConversationID,
+		Kind:           request.Kind,
+		UserID:         request.SenderID,
+		Since:          conversationMember.HistoryClearedAt,
+		LastID:         request.LastID,
+		Limit:          request.Limit,
+	})
 	if err != nil {
-		logger.Error("error convert message to map", err, message)
 		return nil, err
 	}
-	messageMap["user"] = userMap
-	wsMessage := &domain.WebSocketMessage{
-		Type:              domain.WsMessage,
-		Payload:           messageMap,
-		IgnoreUserOnlines: []string{message.UserID},
+
+	attachmentResponses := make([]*presenter.MessageAttachmentResponse, 0)
+	for _, attachment := range attachments {
+		attachmentResponses = append(attachmentResponses, &presenter.MessageAttachmentResponse{
+			AttachmentID:   attachment.ID,
+			MessageID:      attachment.MessageID,
+			ConversationID: attachment.ConversationID,
+			SenderID:       attachment.UserID,
+			Kind:           attachment.Kind,
+			MessageType:    attachment.MessageType,
+			URL:            attachment.URL,
+			CreatedAt:      attachment.CreatedAt,
+		})
 	}
-	// send message to websocket
-	err = c.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, wsMessage)
+	return attachmentResponses, nil
+}
+
+// UpdateConversation implements ConversationUseCase.
+func (c *conversationUseCase) UpdateConversation(ctx context.Context, request *presenter.UpdateConversationRequest) (*presenter.GetListConversationResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.UpdateConversation")
+	defer span()
+	logger := c.obs.Logger.WithContext(ctx)
+
+	member, err := c.conversationRepository.GetConversationMember(ctx, request.ConversationID, request.UserID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+
+	conversation, err := c.conversationRepository.GetConversationByID(ctx, request.ConversationID)
 	if err != nil {
-		logger.Error("failed to publish message to websocket", err, message)
-		// return nil, fmt.Errorf("failed to publish message to websocket: %w", err)
+		return nil, err
 	}
-	// update last message id of conversation
-	err = c.messagePublisher.Publish(ctx, domain.SUBJECT_UPDATE_LAST_MESSAGE_ID, domain.UpdateLastMessageID{
-		ConversationID: message.ConversationID,
-		MessageID:      messageDomain.ID,
+	if conversation.Type == domain.ConversationTypeDM {
+		return nil, domain.ErrConversationNotUpdatable
+	}
+	if !member.IsAdmin() {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	changes := make(map[string]any)
+	if request.Title != nil {
+		conversation.Title = strings.TrimSpace(*request.Title)
+		changes["title"] = conversation.Title
+	}
+	if request.AvatarObjectName != "" {
+		avatar, err := getUploadedObjectURL(ctx, c.objectStorage, request.AvatarBucketName, request.AvatarObjectName)
+		if err != nil {
+			return nil, err
+		}
+		conversation.Avatar = avatar
+		changes["avatar"] = conversation.Avatar
+	}
+	if request.RequireApproval != nil {
+		conversation.RequireApproval = *request.RequireApproval
+		changes["require_approval"] = conversation.RequireApproval
+	}
+	if (request.SlowModeSeconds != nil || request.DailyMessageLimit != nil) && conversation.Type != domain.ConversationTypeGroup {
+		return nil, domain.ErrSendRateLimitNotAllowed
+	}
+	if request.SlowModeSeconds != nil {
+		conversation.SlowModeSeconds = *request.SlowModeSeconds
+		changes["slow_mode_seconds"] = conversation.SlowModeSeconds
+	}
+	if request.DailyMessageLimit != nil {
+		conversation.DailyMessageLimit = *request.DailyMessageLimit
+		changes["daily_message_limit"] = conversation.DailyMessageLimit
+	}
+	conversation.UpdatedAt = pointer.ToPtr(time.Now())
+
+	err = c.conversationRepository.UpdateConversation(ctx, conversation)
+	if err != nil {
+		logger.Error("error update conversation", err, request)
+		return nil, err
+	}
+
+	systemMessage, err := c.messageSender.sendSystemMessage(ctx, conversation.ID, &domain.SystemMessage{
+		Action:  domain.SystemActionConversationUpdated,
+		ActorID: request.UserID,
+		Data:    changes,
 	})
+	if err != nil {
+		logger.Error("error send system message", err, request)
+		return nil, err
+	}
 
+	// the payload has the same shape as an entry of the conversation list,
+	// so clients can replace the entry without fetching the list again
+	conversationResponse := &presenter.GetListConversationResponse{
+		ConversationID:    conversation.ID,
+		Type:              conversation.Type,
+		Title:             conversation.Title,
+		Avatar:            conversation.Avatar,
+		LastMessageID:     systemMessage.ID,
+		LastMessageSeq:    systemMessage.Seq,
+		CreatedAt:         conversation.CreatedAt,
+		UpdatedAt:         conversation.UpdatedAt,
+		SlowModeSeconds:   conversation.SlowModeSeconds,
+		DailyMessageLimit: conversation.DailyMessageLimit,
+		LastMessage: &presenter.MessageResponse{
+			MessageID:      systemMessage.ID,
+			Seq:            systemMessage.Seq,
+			Body:           systemMessage.Body,
+			CreatedAt:      systemMessage.CreatedAt,
+			UpdatedAt:      systemMessage.UpdatedAt,
+			Type:           systemMessage.Type,
+			ConversationID: systemMessage.ConversationID,
+		},
+	}
+	conversationMap, err := pointer.ToMap(conversationResponse)
 	if err != nil {
-		logger.Error("failed to publish message to websocket", err, message)
-		// return nil, fmt.Errorf("failed to publish message to websocket: %w", err)
+		logger.Error("error convert conversation to map", err, conversationResponse)
+		return nil, err
+	}
+	err = c.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, &domain.WebSocketMessage{
+		Type:    domain.WsConversationUpdated,
+		Payload: conversationMap,
+	})
+	if err != nil {
+		logger.Error("failed to publish conversation updated", err, request)
 	}
 
-	return &presenter.MessageResponse{
-		MessageID:      messageDomain.ID,
-		Body:           messageDomain.Body,
-		CreatedAt:      messageDomain.CreatedAt,
-		UpdatedAt:      messageDomain.UpdatedAt,
-		Type:           messageDomain.Type,
-		DeletedAt:      messageDomain.DeletedAt,
-		ReplyTo:        messageDomain.ReplyTo,
-		ConversationID: messageDomain.ConversationID,
-	}, nil
+	return conversationResponse, nil
 }
 
 var _ ConversationUseCase = &conversationUseCase{}
+
+func newNotificationSettingResponse(conversationMember *domain.ConversationMember) *presenter.NotificationSettingResponse {
+	return &presenter.NotificationSettingResponse{
+		ConversationID:    conversationMember.ConversationID,
+		NotificationLevel: conversationMember.NotificationLevel,
+		MutedUntil:        conversationMember.MutedUntil,
+		MutedForever:      conversationMember.MutedForever,
+		IsMuted:           conversationMember.IsMuted(time.Now()),
+	}
+}
+
+// GetNotificationSetting implements ConversationUseCase.
+func (c *conversationUseCase) GetNotificationSetting(ctx context.Context, userID string, conversationID string) (*presenter.NotificationSettingResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetNotificationSetting")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newNotificationSettingResponse(conversationMember), nil
+}
+
+// UpdateNotificationSetting implements ConversationUseCase.
+func (c *conversationUseCase) UpdateNotificationSetting(ctx context.Context, request *presenter.UpdateNotificationSettingRequest) (*presenter.NotificationSettingResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.UpdateNotificationSetting")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, request.ConversationID, request.UserID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conversationMember.NotificationLevel = request.NotificationLevel
+	conversationMember.MutedUntil = request.MutedUntil
+	conversationMember.MutedForever = request.MutedForever
+	conversationMember.UpdatedAt = pointer.ToPtr(time.Now())
+	err = c.conversationRepository.UpdateNotificationSetting(ctx, conversationMember)
+	if err != nil {
+		return nil, err
+	}
+	return newNotificationSettingResponse(conversationMember), nil
+}
+
+// GetUnreadCount implements ConversationUseCase.
+func (c *conversationUseCase) GetUnreadCount(ctx context.Context, userID string) (*presenter.UnreadCountResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.GetUnreadCount")
+	defer span()
+
+	unreadMessageCount, unreadConversationCount, err := c.conversationRepository.CountUnreadMessageByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &presenter.UnreadCountResponse{
+		UnreadMessageCount:      unreadMessageCount,
+		UnreadConversationCount: unreadConversationCount,
+	}, nil
+}
+
+func (c *conversationUseCase) checkMemberOfConversation(ctx context.Context, userID string, conversationID string) error {
+	_, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return domain.ErrNotFoundMemberOfConversation
+	}
+	return err
+}
+
+// ArchiveConversation implements ConversationUseCase.
+func (c *conversationUseCase) ArchiveConversation(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.ArchiveConversation")
+	defer span()
+
+	err := c.checkMemberOfConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	return c.conversationRepository.UpdateArchivedAt(ctx, conversationID, userID, pointer.ToPtr(time.Now()))
+}
+
+// UnarchiveConversation implements ConversationUseCase.
+func (c *conversationUseCase) UnarchiveConversation(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.UnarchiveConversation")
+	defer span()
+
+	err := c.checkMemberOfConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	return c.conversationRepository.UpdateArchivedAt(ctx, conversationID, userID, nil)
+}
+
+// DeleteConversationForMe implements ConversationUseCase.
+// The conversation is only hidden to the user, other members keep the whole history.
+func (c *conversationUseCase) DeleteConversationForMe(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.DeleteConversationForMe")
+	defer span()
+
+	err := c.checkMemberOfConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	return c.conversationRepository.ClearHistory(ctx, conversationID, userID, time.Now())
+}
+
+// PinConversation implements ConversationUseCase.
+func (c *conversationUseCase) PinConversation(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.PinConversation")
+	defer span()
+
+	conversationMember, err := c.conversationRepository.GetConversationMember(ctx, conversationID, userID)
+	if err == pgx.ErrNoRows {
+		return domain.ErrNotFoundMemberOfConversation
+	}
+	if err != nil {
+		return err
+	}
+	if conversationMember.PinnedAt != nil {
+		return nil
+	}
+
+	count, err := c.conversationRepository.CountPinnedConversation(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= domain.MaxPinnedConversation {
+		return domain.ErrPinnedConversationLimit
+	}
+
+	pinnedAt := pointer.ToPtr(time.Now())
+	err = c.conversationRepository.UpdatePinnedAt(ctx, conversationID, userID, pinnedAt)
+	if err != nil {
+		return err
+	}
+	c.publishPinUpdated(ctx, userID, conversationID, pinnedAt)
+	return nil
+}
+
+// UnpinConversation implements ConversationUseCase.
+func (c *conversationUseCase) UnpinConversation(ctx context.Context, userID string, conversationID string) error {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.UnpinConversation")
+	defer span()
+
+	err := c.checkMemberOfConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	err = c.conversationRepository.UpdatePinnedAt(ctx, conversationID, userID, nil)
+	if err != nil {
+		return err
+	}
+	c.publishPinUpdated(ctx, userID, conversationID, nil)
+	return nil
+}
+
+// publishPinUpdated syncs the pin to the other devices of the user.
+func (c *conversationUseCase) publishPinUpdated(ctx context.Context, userID string, conversationID string, pinnedAt *time.Time) {
+	err := publishUserEvent(ctx, c.messagePublisher, domain.WsConversationPinUpdated, map[string]any{
+		"conversation_id": conversationID,
+		"pinned_at":       pinnedAt,
+	}, userID)
+	if err != nil {
+		c.obs.Logger.WithContext(ctx).Error("error publish pin updated", err, conversationID)
+	}
+}
+
+// getMessageOfMember returns the message if the user is a member of its conversation.
+func (c *conversationUseCase) getMessageOfMember(ctx context.Context, userID string, messageID string) (*domain.Message, error) {
+	message, err := c.messageRepository.GetMessageByID(ctx, messageID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = c.checkMemberOfConversation(ctx, userID, message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// AddReaction implements ConversationUseCase.
+func (c *conversationUseCase) AddReaction(ctx context.Context, request *presenter.ReactionRequest) ([]*presenter.ReactionResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.AddReaction")
+	defer span()
+
+	message, err := c.getMessageOfMember(ctx, request.UserID, request.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	err = c.reactionRepository.AddReaction(ctx, &domain.MessageReaction{
+		ID:             id,
+		MessageID:      message.ID,
+		ConversationID: message.ConversationID,
+		UserID:         request.UserID,
+		Emoji:          request.Emoji,
+		CreatedAt:      pointer.ToPtr(time.Now()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.publishReactionUpdated(ctx, message, request)
+}
+
+// RemoveReaction implements ConversationUseCase.
+func (c *conversationUseCase) RemoveReaction(ctx context.Context, request *presenter.ReactionRequest) ([]*presenter.ReactionResponse, error) {
+	ctx, span := c.obs.StartSpan(ctx, "ConversationUsecase.RemoveReaction")
+	defer span()
+
+	message, err := c.getMessageOfMember(ctx, request.UserID, request.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.reactionRepository.RemoveReaction(ctx, message.ID, request.UserID, request.Emoji)
+	if err != nil {
+		return nil, err
+	}
+	return c.publishReactionUpdated(ctx, message, request)
+}
+
+// publishReactionUpdated sends the new reactions of the message to the conversation and returns them.
+func (c *conversationUseCase) publishReactionUpdated(ctx context.Context, message *domain.Message, request *presenter.ReactionRequest) ([]*presenter.ReactionResponse, error) {
+	mapReactions, err := c.getReactionsByMessageIDs(ctx, []string{message.ID}, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	reactions := mapReactions[message.ID]
+	if reactions == nil {
+		reactions = []*presenter.ReactionResponse{}
+	}
+
+	// reacted_by_me is relative to the caller, the other members only get the counts
+	counts := make(map[string]int, len(reactions))
+	for _, reaction := range reactions {
+		counts[reaction.Emoji] = reaction.Count
+	}
+	err = c.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, &domain.WebSocketMessage{
+		Type: domain.WsReactionUpdated,
+		Payload: map[string]any{
+			"conversation_id": message.ConversationID,
+			"message_id":      message.ID,
+			"message_seq":     message.Seq,
+			"user_id":         request.UserID,
+			"emoji":           request.Emoji,
+			"counts":          counts,
+		},
+	})
+	if err != nil {
+		c.obs.Logger.WithContext(ctx).Error("error publish reaction updated", err, message.ID)
+	}
+	return reactions, nil
+}
+
+// SubscribeChannels implements ConversationUseCase.
+// It registers a new connection to the posts of the channels of the user.
+func (c *conversationUseCase) SubscribeChannels(ctx context.Context, userID string, connectionID string) error {
+	conversationIDs, err := c.conversationRepository.GetListChannelIDByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, conversationID := range conversationIDs {
+		domain.WebSocket.Subscribe(conversationID, connectionID)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+)
+
+// messageSender is shared by the use cases that write messages into a conversation.
+type messageSender struct {
+	messageRepository domain.MessageRepository
+	userRepository    domain.UserRepository
+	messagePublisher  pubsub.Publisher
+	obs               *observability.Observability
+}
+
+func newMessageSender(messageRepository domain.MessageRepository, userRepository domain.UserRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) *messageSender {
+	return &messageSender{
+		messageRepository: messageRepository,
+		userRepository:    userRepository,
+		messagePublisher:  messagePublisher,
+		obs:               obs,
+	}
+}
+
+// publishNewMessage pushes a stored message to the websocket subscribers
+// and schedules the update of the conversation last message.
+func (m *messageSender) publishNewMessage(ctx context.Context, messageDomain *domain.Message, ignoreUserOnlines []string) error {
+	logger := m.obs.Logger.WithContext(ctx)
+	user, err := m.userRepository.GetUserByID(ctx, messageDomain.UserID)
+	if err != nil {
+		logger.Error("error get user by id", err, messageDomain)
+		return err
+	}
+	userMap, err := pointer.ToMap(user)
+	if err != nil {
+		logger.Error("error convert user to map", err, user)
+		return err
+	}
+	// the members are not all contacts of the sender
+	userMap["avatar"] = publicAvatar(user)
+
+	messageMap, err := pointer.ToMap(messageDomain)
+	if err != nil {
+		logger.Error("error convert message to map", err, messageDomain)
+		return err
+	}
+	messageMap["user"] = userMap
+	messageMap["is_bot"] = user.Type == domain.InternalUserType
+	wsMessage := &domain.WebSocketMessage{
+		Type:              domain.WsMessage,
+		Payload:           messageMap,
+		IgnoreUserOnlines: ignoreUserOnlines,
+	}
+	// send message to websocket
+	err = m.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, wsMessage)
+	if err != nil {
+		logger.Error("failed to publish message to websocket", err, messageDomain)
+		// return nil, fmt.Errorf("failed to publish message to websocket: %w", err)
+	}
+	// update last message id of conversation
+	err = m.messagePublisher.Publish(ctx, domain.SUBJECT_UPDATE_LAST_MESSAGE_ID, domain.UpdateLastMessageID{
+		ConversationID: messageDomain.ConversationID,
+		MessageID:      messageDomain.ID,
+	})
+
+	if err != nil {
+		logger.Error("failed to publish message to websocket", err, messageDomain)
+		// return nil, fmt.Errorf("failed to publish message to websocket: %w", err)
+	}
+	return nil
+}
+
+// sendSystemMessage stores a system message in the conversation and publishes it like a normal message.
+func (m *messageSender) sendSystemMessage(ctx context.Context, conversationID string, systemMessage *domain.SystemMessage) (*domain.Message, error) {
+	messageID, err := uuid.NewID()
+	if err != nil {
+		return nil, err
+	}
+	messageDomain := &domain.Message{
+		ID:             messageID,
+		ConversationID: conversationID,
+		UserID:         systemMessage.ActorID,
+		Type:           domain.MessageTypeSystem,
+		Body:           systemMessage.String(),
+		CreatedAt:      pointer.ToPtr(time.Now()),
+		UpdatedAt:      pointer.ToPtr(time.Now()),
+	}
+	messageDomain, err = m.messageRepository.CreateMessage(ctx, messageDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.publishNewMessage(ctx, messageDomain, nil)
+	if err != nil {
+		return nil, err
+	}
+	return messageDomain, nil
+}
+
+// publishMemberJoined writes the system message and notifies the members that somebody joined.
+func (m *messageSender) publishMemberJoined(ctx context.Context, conversationMember *domain.ConversationMember, data map[string]any) error {
+	_, err := m.sendSystemMessage(ctx, conversationMember.ConversationID, &domain.SystemMessage{
+		Action:  domain.SystemActionMemberJoined,
+		ActorID: conversationMember.UserID,
+		Data:    data,
+	})
+	if err != nil {
+		return err
+	}
+
+	user, err := m.userRepository.GetUserByID(ctx, conversationMember.UserID)
+	if err != nil {
+		return err
+	}
+	userMap, err := pointer.ToMap(user)
+	if err != nil {
+		return err
+	}
+	userMap["avatar"] = publicAvatar(user)
+
+	return m.messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, &domain.WebSocketMessage{
+		Type: domain.WsMemberJoined,
+		Payload: map[string]any{
+			"conversation_id": conversationMember.ConversationID,
+			"user_id":         conversationMember.UserID,
+			"role":            conversationMember.Role,
+			"user":            userMap,
+		},
+	})
+}
+
+// publishUserEvent pushes an event to every connection of the users,
+// it keeps the state owned by a user in sync between their devices.
+func publishUserEvent(ctx context.Context, messagePublisher pubsub.Publisher, messageType domain.WebSocketMessageType, payload map[string]any, userIDs ...string) error {
+	return messagePublisher.Publish(ctx, domain.SUBJECT_NEW_MESSAGE, &domain.WebSocketMessage{
+		Type:    messageType,
+		Payload: payload,
+		UserIDs: userIDs,
+	})
+}
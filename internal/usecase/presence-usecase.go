@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pubsub"
+)
+
+type PresenceUseCase interface {
+	// Connect marks the connection online.
+	Connect(ctx context.Context, userID string, connectionID string) error
+	// Refresh keeps the connection alive, it must be called more often than domain.PresenceConnectionTTL.
+	Refresh(ctx context.Context, userID string, connectionID string) error
+	UpdateStatus(ctx context.Context, userID string, connectionID string, status string) error
+	// Disconnect removes the connection, the offline event waits for domain.PresenceOfflineGracePeriod.
+	Disconnect(ctx context.Context, userID string, connectionID string) error
+	// CheckPresence publishes the presence of the users whose check is due, like the offline events of the
+	// disconnected users and of the connections that expired. It returns the number of users checked.
+	CheckPresence(ctx context.Context, now time.Time) (int, error)
+	// RunPresenceCheck calls CheckPresence every domain.PresenceCheckInterval until the context is done.
+	RunPresenceCheck(ctx context.Context)
+	GetListPresence(ctx context.Context, userID string, userIDs []string) ([]*domain.Presence, error)
+}
+
+type presenceUseCase struct {
+	presenceRepository  domain.PresenceRepository
+	userRepository      domain.UserRepository
+	userBlockRepository domain.UserBlockRepository
+	contactRepository   domain.ContactRepository
+	messagePublisher    pubsub.Publisher
+	obs                 *observability.Observability
+}
+
+func NewPresenceUseCase(presenceRepository domain.PresenceRepository, userRepository domain.UserRepository, userBlockRepository domain.UserBlockRepository, contactRepository domain.ContactRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) PresenceUseCase {
+	return &presenceUseCase{
+		presenceRepository:  presenceRepository,
+		userRepository:      userRepository,
+		userBlockRepository: userBlockRepository,
+		contactRepository:   contactRepository,
+		messagePublisher:    messagePublisher,
+		obs:                 obs,
+	}
+}
+
+// Connect implements PresenceUseCase.
+func (p *presenceUseCase) Connect(ctx context.Context, userID string, connectionID string) error {
+	ctx, span := p.obs.StartSpan(ctx, "PresenceUsecase.Connect")
+	defer span()
+
+	return p.setConnectionStatus(ctx, userID, connectionID, domain.PresenceStatusOnline)
+}
+
+// Refresh implements PresenceUseCase.
+func (p *presenceUseCase) Refresh(ctx context.Context, userID string, connectionID string) error {
+	ctx, span := p.obs.StartSpan(ctx, "PresenceUsecase.Refresh")
+	defer span()
+
+	now := time.Now()
+	err := p.presenceRepository.RefreshConnection(ctx, userID, connectionID, now.Add(domain.PresenceConnectionTTL))
+	if err != nil {
+		return err
+	}
+	return p.presenceRepository.SetLastSeenAt(ctx, userID, now)
+}
+
+// UpdateStatus implements PresenceUseCase.
+func (p *presenceUseCase) UpdateStatus(ctx context.Context, userID string, connectionID string, status string) error {
+	ctx, span := p.obs.StartSpan(ctx, "PresenceUsecase.UpdateStatus")
+	defer span()
+
+	if !domain.IsValidPresenceStatus(status) {
+		return domain.ErrPresenceStatusInvalid
+	}
+	return p.setConnectionStatus(ctx, userID, connectionID, status)
+}
+
+func (p *presenceUseCase) setConnectionStatus(ctx context.Context, userID string, connectionID string, status string) error {
+	now := time.Now()
+	err := p.presenceRepository.SetConnectionStatus(ctx, userID, connectionID, status, now.Add(domain.PresenceConnectionTTL))
+	if err != nil {
+		return err
+	}
+	err = p.presenceRepository.SetLastSeenAt(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	return p.publishPresence(ctx, userID)
+}
+
+// Disconnect implements PresenceUseCase.
+func (p *presenceUseCase) Disconnect(ctx context.Context, userID string, connectionID string) error {
+	ctx, span := p.obs.StartSpan(ctx, "PresenceUsecase.Disconnect")
+	defer span()
+
+	now := time.Now()
+	err := p.presenceRepository.DeleteConnection(ctx, userID, connectionID)
+	if err != nil {
+		return err
+	}
+	err = p.presenceRepository.SetLastSeenAt(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+
+	presences, err := p.presenceRepository.GetListPresence(ctx, []string{userID}, now)
+	if err != nil {
+		return err
+	}
+	if presences[0].Status != domain.PresenceStatusOffline {
+		return p.publishPresence(ctx, userID)
+	}
+
+	// a reconnection within the grace period finds the status already published and sends nothing
+	return p.presenceRepository.ScheduleCheck(ctx, userID, now.Add(domain.PresenceOfflineGracePeriod))
+}
+
+// CheckPresence implements PresenceUseCase.
+func (p *presenceUseCase) CheckPresence(ctx context.Context, now time.Time) (int, error) {
+	leaseUntil := now.Add(domain.PresenceCheckLease)
+	userIDs, err := p.presenceRepository.ClaimDueCheck(ctx, now, leaseUntil, domain.MaxPresenceCheckUser)
+	if err != nil {
+		return 0, err
+	}
+	for _, userID := range userIDs {
+		// the check left claimed runs again once the lease is over
+		err = p.publishPresence(ctx, userID)
+		if err != nil {
+			p.obs.Logger.WithContext(ctx).Error("error publish presence", err, userID)
+			continue
+		}
+		err = p.presenceRepository.CompleteCheck(ctx, userID, leaseUntil)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(userIDs), nil
+}
+
+// RunPresenceCheck implements PresenceUseCase.
+func (p *presenceUseCase) RunPresenceCheck(ctx context.Context) {
+	ticker := time.NewTicker(domain.PresenceCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := p.CheckPresence(ctx, time.Now())
+			if err != nil {
+				p.obs.Logger.WithContext(ctx).Error("error check presence", err, nil)
+			}
+		}
+	}
+}
+
+// publishPresence sends the presence of the user to their contacts and DM partners allowed by their
+// last seen privacy when it differs from the last one sent.
+func (p *presenceUseCase) publishPresence(ctx context.Context, userID string) error {
+	presences, err := p.presenceRepository.GetListPresence(ctx, []string{userID}, time.Now())
+	if err != nil {
+		return err
+	}
+	presence := presences[0]
+	previous, err := p.presenceRepository.SwapPublishedStatus(ctx, userID, presence.Status)
+	if err != nil {
+		return err
+	}
+	if previous == presence.Status {
+		return nil
+	}
+
+	user, err := p.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.LastSeenPrivacy == domain.PrivacyNobody {
+		return nil
+	}
+	userIDs, err := p.userRepository.GetListContactAndDMUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.LastSeenPrivacy == domain.PrivacyContacts && len(userIDs) > 0 {
+		userIDs, err = p.contactRepository.GetListFriendID(ctx, userID, userIDs)
+		if err != nil {
+			return err
+		}
+	}
+	userIDs, err = p.filterBlockedUserID(ctx, userID, userIDs)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return publishUserEvent(ctx, p.messagePublisher, domain.WsPresenceChanged, map[string]any{
+		"presence": presence,
+	}, userIDs...)
+}
+
+// filterBlockedUserID drops the users blocked by the user or blocking them.
+func (p *presenceUseCase) filterBlockedUserID(ctx context.Context, userID string, userIDs []string) ([]string, error) {
+	blockedUserIDs, err := p.userBlockRepository.GetListBlockedUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	blockingUserIDs, err := p.userBlockRepository.GetListUserIDBlocking(ctx, userID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(userIDs, func(id string) bool {
+		return slices.Contains(blockedUserIDs, id) || slices.Contains(blockingUserIDs, id)
+	}), nil
+}
+
+// GetListPresence implements PresenceUseCase.
+func (p *presenceUseCase) GetListPresence(ctx context.Context, userID string, userIDs []string) ([]*domain.Presence, error) {
+	ctx, span := p.obs.StartSpan(ctx, "PresenceUsecase.GetListPresence")
+	defer span()
+
+	slices.Sort(userIDs)
+	userIDs = slices.Compact(userIDs)
+	if len(userIDs) > domain.MaxPresenceUserIDs {
+		return nil, domain.ErrTooManyPresenceUserIDs
+	}
+	if len(userIDs) == 0 {
+		return []*domain.Presence{}, nil
+	}
+
+	presences, err := p.presenceRepository.GetListPresence(ctx, userIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	visibleUserIDs, err := p.filterBlockedUserID(ctx, userID, slices.Clone(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	users, err := p.userRepository.GetListUserByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	friendIDs, err := p.contactRepository.GetListFriendID(ctx, userID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	lastSeenPrivacies := make(map[string]string, len(users))
+	for _, user := range users {
+		lastSeenPrivacies[user.ID] = user.LastSeenPrivacy
+	}
+
+	for _, presence := range presences {
+		if presence.UserID == userID {
+			continue
+		}
+		if slices.Contains(visibleUserIDs, presence.UserID) && domain.PrivacyAllows(lastSeenPrivacies[presence.UserID], slices.Contains(friendIDs, presence.UserID)) {
+			continue
+		}
+		// the users who blocked each other, or hiding their last seen, always look offline
+		presence.Status = domain.PresenceStatusOffline
+		presence.LastSeenAt = nil
+	}
+	return presences, nil
+}
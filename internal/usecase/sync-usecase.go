@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+)
+
+type SyncUseCase interface {
+	// HandleSyncEvent logs the realtime events so that the clients offline at the time can replay them.
+	HandleSyncEvent(ctx context.Context, message *domain.WebSocketMessage) error
+	Sync(ctx context.Context, userID string, cursor string) (*presenter.SyncResponse, error)
+}
+
+type syncUseCase struct {
+	syncEventRepository domain.SyncEventRepository
+	obs                 *observability.Observability
+}
+
+func NewSyncUseCase(syncEventRepository domain.SyncEventRepository, obs *observability.Observability) SyncUseCase {
+	return &syncUseCase{
+		syncEventRepository: syncEventRepository,
+		obs:                 obs,
+	}
+}
+
+// HandleSyncEvent implements SyncUseCase.
+func (s *syncUseCase) HandleSyncEvent(ctx context.Context, message *domain.WebSocketMessage) error {
+	syncEvent := domain.NewSyncEvent(message, time.Now())
+	if syncEvent == nil {
+		return nil
+	}
+	err := s.syncEventRepository.CreateSyncEvent(ctx, syncEvent)
+	if err != nil {
+		s.obs.Logger.WithContext(ctx).Error("failed to create sync event", err, syncEvent)
+		return err
+	}
+	return nil
+}
+
+// Sync implements SyncUseCase.
+func (s *syncUseCase) Sync(ctx context.Context, userID string, cursor string) (*presenter.SyncResponse, error) {
+	ctx, span := s.obs.StartSpan(ctx, "SyncUsecase.Sync")
+	defer span()
+	logger := s.obs.Logger.WithContext(ctx)
+
+	var after domain.SyncCursor
+	if cursor != "" {
+		var err error
+		after, err = domain.ParseSyncCursor(cursor)
+		if err != nil {
+			return nil, domain.ErrSyncCursorInvalid
+		}
+	}
+
+	committed, purged, err := s.syncEventRepository.GetSyncEventBounds(ctx)
+	if err != nil {
+		logger.Error("failed to get sync event bounds", err, userID)
+		return nil, err
+	}
+	if committed.Before(after) {
+		return nil, domain.ErrSyncCursorInvalid
+	}
+	// every event before the committed position is reflected by the state the client reloads
+	resync := &presenter.SyncResponse{
+		Cursor:         committed.String(),
+		ResyncRequired: true,
+		Events:         []*presenter.SyncEventResponse{},
+	}
+	// without a cursor, or when events after it were purged, the delta is unknown
+	if cursor == "" || after.Before(purged) {
+		return resync, nil
+	}
+
+	syncEvents, err := s.syncEventRepository.GetListSyncEventByUserID(ctx, userID, after, domain.MaxSyncEvent+1)
+	if err != nil {
+		logger.Error("failed to get list sync event", err, userID, after)
+		return nil, err
+	}
+	if len(syncEvents) > domain.MaxSyncEvent {
+		return resync, nil
+	}
+
+	// the cursor never moves past the last event returned
+	next := after
+	if len(syncEvents) > 0 {
+		next = syncEvents[len(syncEvents)-1].Cursor()
+	}
+	response := &presenter.SyncResponse{
+		Cursor: next.String(),
+		Events: make([]*presenter.SyncEventResponse, 0, len(syncEvents)),
+	}
+	for _, syncEvent := range syncEvents {
+		response.Events = append(response.Events, &presenter.SyncEventResponse{
+			Type:      syncEvent.Type,
+			Payload:   syncEvent.Payload,
+			CreatedAt: syncEvent.CreatedAt,
+		})
+	}
+	return response, nil
+}
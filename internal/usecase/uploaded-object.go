@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chat-socio/backend/configuration"
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/pkg/storage"
+)
+
+// getUploadedObjectURL makes sure the object was uploaded through the upload endpoint, to one of its buckets,
+// and returns the public URL the same way UploadHandler builds it.
+func getUploadedObjectURL(ctx context.Context, objectStorage storage.ObjectStorage, bucketName string, objectName string) (string, error) {
+	// only the buckets open to uploads are checked, a server bucket is not even probed for the object
+	if !domain.IsUploadBucket(bucketName) {
+		return "", domain.ErrUploadBucketNotAllowed
+	}
+	exists, err := objectStorage.ObjectExists(ctx, bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", domain.ErrUploadedObjectNotFound
+	}
+
+	uri, err := objectStorage.GetObjectURI(ctx, bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s", configuration.ConfigInstance.Minio.PublicEndpoint, uri), nil
+}
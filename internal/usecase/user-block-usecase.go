@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chat-socio/backend/internal/domain"
+	"github.com/chat-socio/backend/internal/presenter"
+	"github.com/chat-socio/backend/pkg/observability"
+	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
+	"github.com/jackc/pgx/v5"
+)
+
+type UserBlockUseCase interface {
+	BlockUser(ctx context.Context, userID string, blockedUserID string) error
+	UnblockUser(ctx context.Context, userID string, blockedUserID string) error
+	GetListBlockedUser(ctx context.Context, userID string) ([]*presenter.BlockedUserResponse, error)
+}
+
+type userBlockUseCase struct {
+	userBlockRepository domain.UserBlockRepository
+	userRepository      domain.UserRepository
+	contactRepository   domain.ContactRepository
+	messagePublisher    pubsub.Publisher
+	obs                 *observability.Observability
+}
+
+func NewUserBlockUseCase(userBlockRepository domain.UserBlockRepository, userRepository domain.UserRepository, contactRepository domain.ContactRepository, messagePublisher pubsub.Publisher, obs *observability.Observability) UserBlockUseCase {
+	return &userBlockUseCase{
+		userBlockRepository: userBlockRepository,
+		userRepository:      userRepository,
+		contactRepository:   contactRepository,
+		messagePublisher:    messagePublisher,
+		obs:                 obs,
+	}
+}
+
+// BlockUser implements UserBlockUseCase.
+func (u *userBlockUseCase) BlockUser(ctx context.Context, userID string, blockedUserID string) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserBlockUsecase.BlockUser")
+	defer span()
+
+	if userID == blockedUserID {
+		return domain.ErrUserBlockSelf
+	}
+	_, err := u.userRepository.GetUserByID(ctx, blockedUserID)
+	if err == pgx.ErrNoRows {
+		return domain.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewID()
+	if err != nil {
+		return err
+	}
+	err = u.userBlockRepository.CreateUserBlock(ctx, &domain.UserBlock{
+		ID:            id,
+		UserID:        userID,
+		BlockedUserID: blockedUserID,
+		CreatedAt:     pointer.ToPtr(time.Now()),
+	})
+	if err != nil {
+		return err
+	}
+	// the blocked user is not told
+	err = publishUserEvent(ctx, u.messagePublisher, domain.WsUserBlocked, map[string]any{
+		"user_id": blockedUserID,
+	}, userID)
+	if err != nil {
+		u.obs.Logger.WithContext(ctx).Error("error publish user blocked", err, userID, blockedUserID)
+	}
+	return nil
+}
+
+// UnblockUser implements UserBlockUseCase.
+func (u *userBlockUseCase) UnblockUser(ctx context.Context, userID string, blockedUserID string) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserBlockUsecase.UnblockUser")
+	defer span()
+
+	unblocked, err := u.userBlockRepository.DeleteUserBlock(ctx, userID, blockedUserID)
+	if err != nil {
+		return err
+	}
+	if !unblocked {
+		return domain.ErrUserNotBlocked
+	}
+	err = publishUserEvent(ctx, u.messagePublisher, domain.WsUserUnblocked, map[string]any{
+		"user_id": blockedUserID,
+	}, userID)
+	if err != nil {
+		u.obs.Logger.WithContext(ctx).Error("error publish user unblocked", err, userID, blockedUserID)
+	}
+	return nil
+}
+
+// GetListBlockedUser implements UserBlockUseCase.
+func (u *userBlockUseCase) GetListBlockedUser(ctx context.Context, userID string) ([]*presenter.BlockedUserResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserBlockUsecase.GetListBlockedUser")
+	defer span()
+
+	userBlocks, err := u.userBlockRepository.GetListUserBlockWithUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*presenter.BlockedUserResponse, 0, len(userBlocks))
+	for _, userBlock := range userBlocks {
+		responses = append(responses, &presenter.BlockedUserResponse{
+			UserID:    userBlock.User.ID,
+			FullName:  userBlock.User.FullName,
+			Avatar:    userBlock.User.Avatar,
+			UserType:  userBlock.User.Type,
+			BlockedAt: userBlock.CreatedAt,
+		})
+	}
+	avatars := userAvatars{}
+	for _, response := range responses {
+		avatars.add(response.UserID, &response.Avatar)
+	}
+	err = hideAvatars(ctx, u.userRepository, u.contactRepository, userID, avatars)
+	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
@@ -3,13 +3,20 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/chat-socio/backend/configuration"
 	"github.com/chat-socio/backend/pkg/hash"
 	"github.com/chat-socio/backend/pkg/jwt"
+	"github.com/chat-socio/backend/pkg/mailer"
 	"github.com/chat-socio/backend/pkg/pointer"
+	"github.com/chat-socio/backend/pkg/random"
+	"github.com/chat-socio/backend/pkg/storage"
 	"github.com/chat-socio/backend/pkg/uuid"
+	"github.com/chat-socio/backend/pubsub"
 	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 
@@ -27,20 +34,628 @@ var (
 type UserUseCase interface {
 	Register(ctx context.Context, registerRequest *presenter.RegisterRequest) (*presenter.RegisterResponse, error)
 	Login(ctx context.Context, loginRequest *presenter.LoginRequest) (*presenter.LoginResponse, error)
+	// RefreshToken exchanges the refresh token for a new access token and a new refresh token.
+	// A refresh token used twice revokes its session.
+	RefreshToken(ctx context.Context, request *presenter.RefreshTokenRequest) (*presenter.LoginResponse, error)
 	GetUserInfo(ctx context.Context, userID string) (*presenter.GetUserInfoResponse, error)
 	GetMyInfo(ctx context.Context) (*presenter.GetUserInfoResponse, error)
 	GetUserInfoByEmail(ctx context.Context, email string) (*presenter.GetUserInfoResponse, error)
 	GetListUser(ctx context.Context, userID string, keyword string, limit int, lastID string) ([]*presenter.GetUserInfoResponse, error)
 	GetUserIDByAccountID(ctx context.Context, accountID string) (string, error)
+	// CheckAccountRestriction returns ErrAccountSuspended or ErrAccountBanned when an admin restricted the account.
+	CheckAccountRestriction(ctx context.Context, accountID string) error
+	// CheckSession returns ErrSessionInactive unless the session of the access token is active and belongs to the account.
+	CheckSession(ctx context.Context, accountID string, sessionToken string) error
+	UpdateProfile(ctx context.Context, request *presenter.UpdateProfileRequest) (*presenter.GetUserInfoResponse, error)
+	CheckHandleAvailability(ctx context.Context, userID string, handle string) (*presenter.HandleAvailabilityResponse, error)
+	GetPrivacySetting(ctx context.Context, userID string) (*presenter.PrivacySettingResponse, error)
+	SetStatus(ctx context.Context, request *presenter.SetUserStatusRequest) (*presenter.UserStatusResponse, error)
+	ClearStatus(ctx context.Context, userID string) error
+	// ClearExpiredStatus clears the statuses expired at now, notifies their users and returns how many were cleared.
+	ClearExpiredStatus(ctx context.Context, now time.Time) (int, error)
+	UpdatePrivacySetting(ctx context.Context, request *presenter.UpdatePrivacySettingRequest) (*presenter.PrivacySettingResponse, error)
+	ChangePassword(ctx context.Context, request *presenter.ChangePasswordRequest) (*presenter.LoginResponse, error)
+	ForgotPassword(ctx context.Context, request *presenter.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, request *presenter.ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, request *presenter.VerifyEmailRequest) error
+	ResendVerificationEmail(ctx context.Context, request *presenter.ResendVerificationEmailRequest) error
+}
+
+const (
+	passwordResetTokenLength     = 32
+	emailVerificationTokenLength = 32
+	refreshTokenLength           = 32
+)
+
+// emailVerificationPolicy returns the configured policy, the verification is disabled when it is not configured.
+func emailVerificationPolicy() configuration.EmailVerificationConfig {
+	if configuration.ConfigInstance.EmailVerification == nil {
+		return configuration.EmailVerificationConfig{}
+	}
+	return *configuration.ConfigInstance.EmailVerification
+}
+
+// accessTokenTTL returns the configured lifetime of the access tokens.
+func accessTokenTTL() time.Duration {
+	if configuration.ConfigInstance.JWT.Expiration <= 0 {
+		return domain.DefaultAccessTokenTTL
+	}
+	return time.Duration(configuration.ConfigInstance.JWT.Expiration) * time.Second
+}
+
+func mailerConfig() configuration.MailerConfig {
+	if configuration.ConfigInstance.Mailer == nil {
+		return configuration.MailerConfig{}
+	}
+	return *configuration.ConfigInstance.Mailer
 }
 
 type userUseCase struct {
-	accountRepository      domain.AccountRepository
-	userRepository         domain.UserRepository
-	sessionRepository      domain.SessionRepository
-	sessionCacheRepository domain.SessionCacheRepository
-	userCacheRepository    domain.UserCacheRepository
-	obs                    *observability.Observability
+	accountRepository                domain.AccountRepository
+	userRepository                   domain.UserRepository
+	contactRepository                domain.ContactRepository
+	sessionRepository                domain.SessionRepository
+	sessionCacheRepository           domain.SessionCacheRepository
+	userCacheRepository              domain.UserCacheRepository
+	passwordResetTokenRepository     domain.PasswordResetTokenRepository
+	emailVerificationTokenRepository domain.EmailVerificationTokenRepository
+	requestRateLimitRepository       domain.RequestRateLimitRepository
+	objectStorage                    storage.ObjectStorage
+	messagePublisher                 pubsub.Publisher
+	mailer                           mailer.Mailer
+	obs                              *observability.Observability
+}
+
+// ChangePassword implements UserUseCase.
+func (u *userUseCase) ChangePassword(ctx context.Context, request *presenter.ChangePasswordRequest) (*presenter.LoginResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ChangePassword")
+	defer span()
+
+	accountID := ctx.Value(utils.AccountIDKey).(string)
+	account, err := u.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFoundAccount
+	}
+
+	if !hash.CheckPasswordHash(request.OldPassword, account.Password) {
+		return nil, ErrWrongPassword
+	}
+
+	hashedPassword, err := hash.HashPassword(request.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	err = u.accountRepository.UpdatePassword(ctx, account.ID, hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// every session is revoked, the caller keeps going with a new one
+	err = u.revokeAllSession(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return u.createSession(ctx, account.ID)
+}
+
+// ForgotPassword implements UserUseCase.
+func (u *userUseCase) ForgotPassword(ctx context.Context, request *presenter.ForgotPasswordRequest) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ForgotPassword")
+	defer span()
+	logger := u.obs.Logger.WithContext(ctx)
+
+	// the limit of the client does not depend on the email, it tells nothing about the accounts
+	ipAddress, _ := ctx.Value(utils.IpAddressKey).(string)
+	allowed, err := u.requestRateLimitRepository.AllowRequest(ctx, "password_reset:"+ipAddress, domain.MaxPasswordResetPerIPPerHour, time.Hour)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrPasswordResetRateLimited
+	}
+
+	// the caller is not told whether the email has an account
+	account, err := u.accountRepository.GetAccountByUsername(ctx, request.Email)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows {
+		logger.Info("Password reset requested for an unknown email")
+		return nil
+	}
+
+	// the limit of the account is not reported either, the response is the same as for an unknown email
+	now := time.Now()
+	recentCount, err := u.passwordResetTokenRepository.CountPasswordResetTokenSince(ctx, account.ID, now.Add(-domain.PasswordResetResendInterval))
+	if err != nil {
+		return err
+	}
+	hourCount, err := u.passwordResetTokenRepository.CountPasswordResetTokenSince(ctx, account.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if recentCount > 0 || hourCount >= domain.MaxPasswordResetPerHour {
+		logger.Info("Password reset rate limited for an account")
+		return nil
+	}
+
+	token, err := random.NewToken(passwordResetTokenLength)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewID()
+	if err != nil {
+		return err
+	}
+	err = u.passwordResetTokenRepository.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		ID:        id,
+		AccountID: account.ID,
+		TokenHash: domain.HashToken(token),
+		ExpiredAt: pointer.ToPtr(now.Add(domain.PasswordResetTokenTTL)),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	// a mailer failure is only logged, an error would tell the caller that the email has an account
+	err = u.mailer.Send(ctx, &mailer.Message{
+		To:      []string{account.Username},
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account.\n\n"+
+			"Open the link below within %d minutes to choose a new password:\n%s?token=%s\n\n"+
+			"If it was not you, ignore this email, your password stays the same.",
+			int(domain.PasswordResetTokenTTL.Minutes()), mailerConfig().ResetPasswordURL, token),
+	})
+	if err != nil {
+		logger.Error("failed to send password reset email", err, account.ID)
+	}
+	return nil
+}
+
+// ResetPassword implements UserUseCase.
+func (u *userUseCase) ResetPassword(ctx context.Context, request *presenter.ResetPasswordRequest) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ResetPassword")
+	defer span()
+
+	hashedPassword, err := hash.HashPassword(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// the token is only used up along with the password update
+	accountID, err := u.passwordResetTokenRepository.ResetPassword(ctx, domain.HashToken(request.Token), hashedPassword, time.Now())
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows {
+		return domain.ErrPasswordResetTokenInvalid
+	}
+
+	return u.revokeAllSession(ctx, accountID)
+}
+
+// VerifyEmail implements UserUseCase.
+func (u *userUseCase) VerifyEmail(ctx context.Context, request *presenter.VerifyEmailRequest) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.VerifyEmail")
+	defer span()
+
+	_, err := u.emailVerificationTokenRepository.UseEmailVerificationToken(ctx, domain.HashToken(request.Token), time.Now())
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows {
+		return domain.ErrEmailVerificationTokenInvalid
+	}
+	return nil
+}
+
+// ResendVerificationEmail implements UserUseCase.
+func (u *userUseCase) ResendVerificationEmail(ctx context.Context, request *presenter.ResendVerificationEmailRequest) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ResendVerificationEmail")
+	defer span()
+
+	// the caller is not told whether the email has an account or is verified already
+	account, err := u.accountRepository.GetAccountByUsername(ctx, request.Email)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if err == pgx.ErrNoRows || account.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	count, err := u.emailVerificationTokenRepository.CountEmailVerificationTokenSince(ctx, account.ID, now.Add(-domain.EmailVerificationResendInterval))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrEmailVerificationRateLimited
+	}
+	count, err = u.emailVerificationTokenRepository.CountEmailVerificationTokenSince(ctx, account.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= domain.MaxEmailVerificationPerHour {
+		return domain.ErrEmailVerificationRateLimited
+	}
+
+	return u.sendVerificationEmail(ctx, account)
+}
+
+// sendVerificationEmail creates a verification token for the account and mails its link.
+func (u *userUseCase) sendVerificationEmail(ctx context.Context, account *domain.Account) error {
+	token, err := random.NewToken(emailVerificationTokenLength)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewID()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = u.emailVerificationTokenRepository.CreateEmailVerificationToken(ctx, &domain.EmailVerificationToken{
+		ID:        id,
+		AccountID: account.ID,
+		TokenHash: domain.HashToken(token),
+		ExpiredAt: pointer.ToPtr(now.Add(domain.EmailVerificationTokenTTL)),
+		CreatedAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, &mailer.Message{
+		To:      []string{account.Username},
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Welcome! Open the link below within %d hours to verify your email:\n%s?token=%s\n\n"+
+			"If you did not create an account, ignore this email.",
+			int(domain.EmailVerificationTokenTTL.Hours()), mailerConfig().VerifyEmailURL, token),
+	})
+}
+
+// revokeAllSession deactivates the sessions of the account and drops them from the cache the middleware reads first.
+func (u *userUseCase) revokeAllSession(ctx context.Context, accountID string) error {
+	sessions, err := u.sessionRepository.GetListSessionByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	err = u.sessionRepository.DeactiveAllSessionByAccountID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		err = u.sessionCacheRepository.DeleteSession(ctx, session.SessionToken)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newUserStatusResponse returns nil when the user has no status or it expired, the job clearing
+// the expired statuses only runs periodically.
+func newUserStatusResponse(emoji string, text string, expiresAt *time.Time) *presenter.UserStatusResponse {
+	if emoji == "" && text == "" {
+		return nil
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil
+	}
+	return &presenter.UserStatusResponse{
+		Emoji:     emoji,
+		Text:      text,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func newGetUserInfoResponse(user *domain.UserInfo) *presenter.GetUserInfoResponse {
+	return &presenter.GetUserInfoResponse{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		Avatar:    user.Avatar,
+		Handle:    pointer.FromPtr(user.Handle),
+		Bio:       user.Bio,
+		Status:    newUserStatusResponse(user.StatusEmoji, user.StatusText, user.StatusExpiresAt),
+		Type:      user.Type,
+		AccountID: user.AccountID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// UpdateProfile implements UserUseCase.
+func (u *userUseCase) UpdateProfile(ctx context.Context, request *presenter.UpdateProfileRequest) (*presenter.GetUserInfoResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.UpdateProfile")
+	defer span()
+	logger := u.obs.Logger.WithContext(ctx)
+
+	user, err := u.userRepository.GetUserByID(ctx, request.UserID)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if request.FullName != nil {
+		user.FullName = strings.TrimSpace(*request.FullName)
+	}
+	if request.Bio != nil {
+		user.Bio = strings.TrimSpace(*request.Bio)
+	}
+	if request.Handle != nil {
+		handle := domain.NormalizeHandle(*request.Handle)
+		if handle == "" {
+			user.Handle = nil
+		} else if !strings.EqualFold(pointer.FromPtr(user.Handle), handle) {
+			err = domain.ValidateHandle(handle)
+			if err != nil {
+				return nil, err
+			}
+			user.Handle = &handle
+		}
+	}
+	if request.AvatarObjectName != "" {
+		avatar, err := getUploadedObjectURL(ctx, u.objectStorage, request.AvatarBucketName, request.AvatarObjectName)
+		if err != nil {
+			return nil, err
+		}
+		user.Avatar = avatar
+	}
+	user.UpdatedAt = pointer.ToPtr(time.Now())
+
+	// the unique index decides when two users take the same handle at once
+	err = u.userRepository.UpdateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newGetUserInfoResponse(user)
+
+	// contacts and members of the shared conversations refresh the user they cached
+	relatedUserIDs, err := u.userRepository.GetListRelatedUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error("error get list related user id", err, user.ID)
+		return response, nil
+	}
+	// the avatar is only sent to the users its privacy lets see it
+	avatarUserIDs := relatedUserIDs
+	if user.AvatarPrivacy == domain.PrivacyContacts {
+		avatarUserIDs, err = u.contactRepository.GetListFriendID(ctx, user.ID, relatedUserIDs)
+		if err != nil {
+			logger.Error("error get list friend id", err, user.ID)
+			return response, nil
+		}
+	} else if !domain.PrivacyAllows(user.AvatarPrivacy, false) {
+		avatarUserIDs = nil
+	}
+	otherUserIDs := slices.DeleteFunc(slices.Clone(relatedUserIDs), func(relatedUserID string) bool {
+		return slices.Contains(avatarUserIDs, relatedUserID)
+	})
+	u.publishProfileUpdated(ctx, user, response.Handle, user.Avatar, append(avatarUserIDs, user.ID)...)
+	if len(otherUserIDs) > 0 {
+		u.publishProfileUpdated(ctx, user, response.Handle, "", otherUserIDs...)
+	}
+
+	return response, nil
+}
+
+// publishProfileUpdated tells the users to refresh the profile they cached, with the avatar they may see.
+func (u *userUseCase) publishProfileUpdated(ctx context.Context, user *domain.UserInfo, handle string, avatar string, userIDs ...string) {
+	err := publishUserEvent(ctx, u.messagePublisher, domain.WsUserProfileUpdated, map[string]any{
+		"user_id":    user.ID,
+		"full_name":  user.FullName,
+		"avatar":     avatar,
+		"handle":     handle,
+		"bio":        user.Bio,
+		"updated_at": user.UpdatedAt,
+	}, userIDs...)
+	if err != nil {
+		u.obs.Logger.WithContext(ctx).Error("error publish profile updated", err, user.ID)
+	}
+}
+
+func newPrivacySettingResponse(user *domain.UserInfo) *presenter.PrivacySettingResponse {
+	return &presenter.PrivacySettingResponse{
+		LastSeen:      user.LastSeenPrivacy,
+		Avatar:        user.AvatarPrivacy,
+		DirectMessage: user.DirectMessagePrivacy,
+		GroupAdd:      user.GroupAddPrivacy,
+	}
+}
+
+// GetPrivacySetting implements UserUseCase.
+func (u *userUseCase) GetPrivacySetting(ctx context.Context, userID string) (*presenter.PrivacySettingResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.GetPrivacySetting")
+	defer span()
+
+	user, err := u.userRepository.GetUserByID(ctx, userID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newPrivacySettingResponse(user), nil
+}
+
+// UpdatePrivacySetting implements UserUseCase.
+func (u *userUseCase) UpdatePrivacySetting(ctx context.Context, request *presenter.UpdatePrivacySettingRequest) (*presenter.PrivacySettingResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.UpdatePrivacySetting")
+	defer span()
+
+	user, err := u.userRepository.GetUserByID(ctx, request.UserID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if request.LastSeen != nil {
+		user.LastSeenPrivacy = *request.LastSeen
+	}
+	if request.Avatar != nil {
+		user.AvatarPrivacy = *request.Avatar
+	}
+	if request.DirectMessage != nil {
+		user.DirectMessagePrivacy = *request.DirectMessage
+	}
+	if request.GroupAdd != nil {
+		user.GroupAddPrivacy = *request.GroupAdd
+	}
+	err = u.userRepository.UpdatePrivacySetting(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivacySettingResponse(user), nil
+}
+
+// SetStatus implements UserUseCase.
+func (u *userUseCase) SetStatus(ctx context.Context, request *presenter.SetUserStatusRequest) (*presenter.UserStatusResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.SetStatus")
+	defer span()
+
+	user, err := u.userRepository.GetUserByID(ctx, request.UserID)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.StatusEmoji = request.Emoji
+	user.StatusText = request.Text
+	user.StatusExpiresAt = request.ExpiresAt
+	err = u.userRepository.UpdateUserStatus(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newUserStatusResponse(user.StatusEmoji, user.StatusText, user.StatusExpiresAt)
+	u.publishUserStatus(ctx, user.ID, response)
+	return response, nil
+}
+
+// ClearStatus implements UserUseCase.
+func (u *userUseCase) ClearStatus(ctx context.Context, userID string) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ClearStatus")
+	defer span()
+
+	err := u.userRepository.UpdateUserStatus(ctx, &domain.UserInfo{ID: userID})
+	if err != nil {
+		return err
+	}
+	u.publishUserStatus(ctx, userID, nil)
+	return nil
+}
+
+// ClearExpiredStatus implements UserUseCase.
+func (u *userUseCase) ClearExpiredStatus(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.ClearExpiredStatus")
+	defer span()
+
+	userIDs, err := u.userRepository.ClearExpiredUserStatus(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, userID := range userIDs {
+		u.publishUserStatus(ctx, userID, nil)
+	}
+	return len(userIDs), nil
+}
+
+// publishUserStatus sends the status to the devices of the user, their contacts and the members of
+// the shared conversations, a nil status clears it. The status is saved already, so a failure is only logged.
+func (u *userUseCase) publishUserStatus(ctx context.Context, userID string, status *presenter.UserStatusResponse) {
+	logger := u.obs.Logger.WithContext(ctx)
+
+	relatedUserIDs, err := u.userRepository.GetListRelatedUserID(ctx, userID)
+	if err != nil {
+		logger.Error("error get list related user id", err, userID)
+		return
+	}
+	err = publishUserEvent(ctx, u.messagePublisher, domain.WsUserStatusUpdated, map[string]any{
+		"user_id": userID,
+		"status":  status,
+	}, append(relatedUserIDs, userID)...)
+	if err != nil {
+		logger.Error("error publish user status updated", err, userID)
+	}
+}
+
+// CheckHandleAvailability implements UserUseCase.
+func (u *userUseCase) CheckHandleAvailability(ctx context.Context, userID string, handle string) (*presenter.HandleAvailabilityResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.CheckHandleAvailability")
+	defer span()
+
+	handle = domain.NormalizeHandle(handle)
+	response := &presenter.HandleAvailabilityResponse{Handle: handle}
+	err := domain.ValidateHandle(handle)
+	if err != nil {
+		response.Reason = err.Error()
+		return response, nil
+	}
+
+	user, err := u.userRepository.GetUserByHandle(ctx, handle)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && user.ID != userID {
+		response.Reason = domain.ErrHandleTaken.Error()
+		return response, nil
+	}
+
+	response.Available = true
+	return response, nil
+}
+
+// CheckAccountRestriction implements UserUseCase.
+func (u *userUseCase) CheckAccountRestriction(ctx context.Context, accountID string) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.CheckAccountRestriction")
+	defer span()
+
+	account, err := u.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	return account.RestrictionError(time.Now())
+}
+
+// CheckSession implements UserUseCase. It looks the session up like AuthMiddleware, in the cache then in the database.
+func (u *userUseCase) CheckSession(ctx context.Context, accountID string, sessionToken string) error {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.CheckSession")
+	defer span()
+
+	session, err := u.sessionCacheRepository.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		session, err = u.sessionRepository.GetSessionByToken(ctx, sessionToken)
+		if err == pgx.ErrNoRows {
+			return domain.ErrSessionInactive
+		}
+		if err != nil {
+			return err
+		}
+		if (session.ExpiredAt != nil && session.ExpiredAt.Before(time.Now())) || (session.IsActive != nil && !*session.IsActive) {
+			return domain.ErrSessionInactive
+		}
+		err = u.sessionCacheRepository.CreateSessionWithExpireTime(ctx, session)
+		if err != nil {
+			return err
+		}
+	}
+	if (session.IsActive != nil && !*session.IsActive) || session.AccountID != accountID {
+		return domain.ErrSessionInactive
+	}
+	return nil
 }
 
 // GetUserIDByAccountID implements UserUseCase.
@@ -82,17 +697,13 @@ func (u *userUseCase) GetListUser(ctx context.Context, userID string, keyword st
 
 	userResponses := make([]*presenter.GetUserInfoResponse, 0)
 	for _, user := range users {
-		userResponses = append(userResponses, &presenter.GetUserInfoResponse{
-			UserID:         user.ID,
-			Email:          user.Email,
-			FullName:       user.FullName,
-			Avatar:         user.Avatar,
-			Type:           user.Type,
-			AccountID:      user.AccountID,
-			CreatedAt:      user.CreatedAt,
-			UpdatedAt:      user.UpdatedAt,
-			ConversationID: user.ConversationID,
-		})
+		userResponse := newGetUserInfoResponse(user)
+		userResponse.ConversationID = user.ConversationID
+		userResponse.ContactState = user.ContactState
+		if !domain.PrivacyAllows(user.AvatarPrivacy, user.ContactState == domain.ContactStateFriend) {
+			userResponse.Avatar = ""
+		}
+		userResponses = append(userResponses, userResponse)
 	}
 
 	return userResponses, nil
@@ -108,16 +719,7 @@ func (u *userUseCase) GetUserInfoByEmail(ctx context.Context, email string) (*pr
 		return nil, err
 	}
 
-	return &presenter.GetUserInfoResponse{
-		UserID:    user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Avatar:    user.Avatar,
-		Type:      user.Type,
-		AccountID: user.AccountID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, nil
+	return newGetUserInfoResponse(user), nil
 }
 
 // GetMyInfo implements UserUseCase.
@@ -135,16 +737,15 @@ func (u *userUseCase) GetMyInfo(ctx context.Context) (*presenter.GetUserInfoResp
 		return nil, ErrNotFoundAccount
 	}
 
-	return &presenter.GetUserInfoResponse{
-		UserID:    user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Avatar:    user.Avatar,
-		Type:      user.Type,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		AccountID: user.AccountID,
-	}, nil
+	account, err := u.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newGetUserInfoResponse(user)
+	response.EmailVerified = pointer.ToPtr(account.IsEmailVerified())
+	response.DeletionScheduledAt = account.DeletionScheduledAt
+	return response, nil
 }
 
 // GetUserInfo implements UserUseCase.
@@ -157,16 +758,7 @@ func (u *userUseCase) GetUserInfo(ctx context.Context, userID string) (*presente
 		return nil, err
 	}
 
-	return &presenter.GetUserInfoResponse{
-		UserID:    user.ID,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Avatar:    user.Avatar,
-		Type:      user.Type,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		AccountID: user.AccountID,
-	}, nil
+	return newGetUserInfoResponse(user), nil
 }
 
 // Login implements UserUseCase.
@@ -174,9 +766,6 @@ func (u *userUseCase) Login(ctx context.Context, loginRequest *presenter.LoginRe
 	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.Login")
 	defer span()
 
-	userAgent := ctx.Value(utils.UserAgentKey).(string)
-	ipAddress := ctx.Value(utils.IpAddressKey).(string)
-
 	account, err := u.accountRepository.GetAccountByUsername(ctx, loginRequest.Email)
 	if err != nil && err.Error() != pgx.ErrNoRows.Error() {
 		return nil, err
@@ -189,19 +778,35 @@ func (u *userUseCase) Login(ctx context.Context, loginRequest *presenter.LoginRe
 	if !hash.CheckPasswordHash(loginRequest.Password, account.Password) {
 		return nil, ErrWrongPassword
 	}
+	if err := account.RestrictionError(time.Now()); err != nil {
+		return nil, err
+	}
+
+	policy := emailVerificationPolicy()
+	if policy.Enabled && !policy.AllowLogin && !account.IsEmailVerified() {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	return u.createSession(ctx, account.ID)
+}
+
+// createSession opens a session for the user agent and the ip address of the context and returns its tokens.
+func (u *userUseCase) createSession(ctx context.Context, accountID string) (*presenter.LoginResponse, error) {
+	userAgent := ctx.Value(utils.UserAgentKey).(string)
+	ipAddress := ctx.Value(utils.IpAddressKey).(string)
 
 	sessionToken, err := uuid.NewID()
 	if err != nil {
 		return nil, err
 	}
 
-	// Create session
+	// Create session, it can be refreshed until it expires
 	now := time.Now()
 	active := true
-	expiredAt := now.Add(24 * time.Hour * 30 * 6) // 180 days
+	expiredAt := now.Add(domain.SessionTTL)
 	session := &domain.Session{
 		SessionToken: sessionToken,
-		AccountID:    account.ID,
+		AccountID:    accountID,
 		UserAgent:    userAgent,
 		IPAddress:    ipAddress,
 		IsActive:     &active,
@@ -215,31 +820,91 @@ func (u *userUseCase) Login(ctx context.Context, loginRequest *presenter.LoginRe
 		return nil, err
 	}
 
-	// Create access token from session
-	var claims = jwt.JWTClaims{
-		Jit: sessionToken,
-		Sub: account.ID,
-		Iat: now.Unix(),
-		Exp: expiredAt.Unix(),
-	}
-
 	// Cache the session
 	err = u.sessionCacheRepository.CreateSessionWithExpireTime(ctx, session)
 	if err != nil {
 		return nil, err
 	}
 
-	// return the access token
+	refreshToken, token, err := newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken.SessionToken = sessionToken
+	err = u.sessionRepository.CreateRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLoginResponse(session, token, now)
+}
+
+// newRefreshToken returns a refresh token to store and the token to give to the client.
+func newRefreshToken(now time.Time) (*domain.RefreshToken, string, error) {
+	token, err := random.NewToken(refreshTokenLength)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := uuid.NewID()
+	if err != nil {
+		return nil, "", err
+	}
+	return &domain.RefreshToken{
+		ID:        id,
+		TokenHash: domain.HashToken(token),
+		CreatedAt: &now,
+	}, token, nil
+}
+
+// newLoginResponse signs a short-lived access token for the session.
+func newLoginResponse(session *domain.Session, refreshToken string, now time.Time) (*presenter.LoginResponse, error) {
+	ttl := accessTokenTTL()
+	var claims = jwt.JWTClaims{
+		Jit: session.SessionToken,
+		Sub: session.AccountID,
+		Iat: now.Unix(),
+		Exp: now.Add(ttl).Unix(),
+	}
+
 	jwtToken, err := jwt.GenerateHS256JWT(claims, configuration.ConfigInstance.JWT.SecretKey)
 	if err != nil {
 		return nil, err
 	}
 
-	loginResponse := &presenter.LoginResponse{
-		AccessToken: jwtToken,
+	return &presenter.LoginResponse{
+		AccessToken:  jwtToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(ttl.Seconds()),
+	}, nil
+}
+
+// RefreshToken implements UserUseCase.
+func (u *userUseCase) RefreshToken(ctx context.Context, request *presenter.RefreshTokenRequest) (*presenter.LoginResponse, error) {
+	ctx, span := u.obs.StartSpan(ctx, "UserUsecase.RefreshToken")
+	defer span()
+
+	now := time.Now()
+	refreshToken, token, err := newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+	session, err := u.sessionRepository.RotateRefreshToken(ctx, domain.HashToken(request.RefreshToken), refreshToken, now)
+	if err == pgx.ErrNoRows || err == domain.ErrRefreshTokenInvalid {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	if err == domain.ErrRefreshTokenReused {
+		// the token leaked, the access tokens of the session stop working right away
+		u.obs.Logger.WithContext(ctx).Warn("Refresh token reused, the session is revoked")
+		if err := u.sessionCacheRepository.DeleteSession(ctx, session.SessionToken); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return loginResponse, nil
+	return newLoginResponse(session, token, now)
 }
 
 // Register implements UserUseCase.
@@ -269,6 +934,10 @@ func (u *userUseCase) Register(ctx context.Context, registerRequest *presenter.R
 		CreatedAt: pointer.ToPtr(time.Now()),
 		UpdatedAt: pointer.ToPtr(time.Now()),
 	}
+	verificationEnabled := emailVerificationPolicy().Enabled
+	if !verificationEnabled {
+		account.EmailVerifiedAt = account.CreatedAt
+	}
 
 	userID, err := uuid.NewID()
 	if err != nil {
@@ -282,8 +951,13 @@ func (u *userUseCase) Register(ctx context.Context, registerRequest *presenter.R
 		Email:     registerRequest.Email,
 		FullName:  registerRequest.FullName,
 		Avatar:    registerRequest.Avatar,
-		CreatedAt: pointer.ToPtr(time.Now()),
-		UpdatedAt: pointer.ToPtr(time.Now()),
+		// everybody can see and reach a new user until they change it
+		LastSeenPrivacy:      domain.PrivacyEveryone,
+		AvatarPrivacy:        domain.PrivacyEveryone,
+		DirectMessagePrivacy: domain.PrivacyEveryone,
+		GroupAddPrivacy:      domain.PrivacyEveryone,
+		CreatedAt:            pointer.ToPtr(time.Now()),
+		UpdatedAt:            pointer.ToPtr(time.Now()),
 	}
 
 	err = u.accountRepository.CreateAccountUser(ctx, account, user)
@@ -298,18 +972,34 @@ func (u *userUseCase) Register(ctx context.Context, registerRequest *presenter.R
 		Message: "Register success",
 	}
 
+	if verificationEnabled {
+		// the account is created anyway, the user can ask for another email
+		err = u.sendVerificationEmail(ctx, account)
+		if err != nil {
+			logger.WithError(err).Error("Failed to send verification email")
+		}
+		registerResponse.Message = "Register success, check your email to verify your account"
+	}
+
 	return registerResponse, nil
 }
 
 var _ UserUseCase = (*userUseCase)(nil)
 
-func NewUserUseCase(accountRepository domain.AccountRepository, userRepository domain.UserRepository, sessionRepository domain.SessionRepository, sessionCacheRepository domain.SessionCacheRepository, userCacheRepository domain.UserCacheRepository, obs *observability.Observability) UserUseCase {
+func NewUserUseCase(accountRepository domain.AccountRepository, userRepository domain.UserRepository, contactRepository domain.ContactRepository, sessionRepository domain.SessionRepository, sessionCacheRepository domain.SessionCacheRepository, userCacheRepository domain.UserCacheRepository, passwordResetTokenRepository domain.PasswordResetTokenRepository, emailVerificationTokenRepository domain.EmailVerificationTokenRepository, requestRateLimitRepository domain.RequestRateLimitRepository, objectStorage storage.ObjectStorage, messagePublisher pubsub.Publisher, mailer mailer.Mailer, obs *observability.Observability) UserUseCase {
 	return &userUseCase{
-		accountRepository:      accountRepository,
-		userRepository:         userRepository,
-		sessionRepository:      sessionRepository,
-		sessionCacheRepository: sessionCacheRepository,
-		userCacheRepository:    userCacheRepository,
-		obs:                    obs,
+		accountRepository:                accountRepository,
+		userRepository:                   userRepository,
+		contactRepository:                contactRepository,
+		sessionRepository:                sessionRepository,
+		sessionCacheRepository:           sessionCacheRepository,
+		userCacheRepository:              userCacheRepository,
+		passwordResetTokenRepository:     passwordResetTokenRepository,
+		emailVerificationTokenRepository: emailVerificationTokenRepository,
+		requestRateLimitRepository:       requestRateLimitRepository,
+		objectStorage:                    objectStorage,
+		messagePublisher:                 messagePublisher,
+		mailer:                           mailer,
+		obs:                              obs,
 	}
 }
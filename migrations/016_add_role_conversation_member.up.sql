@@ -0,0 +1,11 @@
+alter table conversation_member add column role text not null default 'MEMBER';
+
+-- the creator of a conversation is not recorded, the earliest member of each existing group owns it
+update conversation_member set role = 'OWNER'
+where id in (
+    select distinct on (cm.conversation_id) cm.id
+    from conversation_member cm
+    inner join conversation c on c.id = cm.conversation_id
+    where c.type <> 'DM' and cm.deleted_at is null
+    order by cm.conversation_id, cm.created_at, cm.id
+);
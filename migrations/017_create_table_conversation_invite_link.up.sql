@@ -0,0 +1,15 @@
+create table if not exists conversation_invite_link (
+    id text primary key,
+    conversation_id text not null,
+    token text not null unique,
+    created_by text not null,
+    expired_at timestamptz,
+    max_uses integer not null default 0,
+    used_count integer not null default 0,
+    require_approval boolean not null default false,
+    revoked_at timestamptz,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+create index if not exists idx_conversation_invite_link_conversation_id on conversation_invite_link(conversation_id);
@@ -0,0 +1,3 @@
+-- dm_key is the sorted pair of member ids of a DM, existing DMs get it from the merge-dm command
+alter table conversation add column dm_key text;
+create unique index if not exists idx_conversation_dm_key on conversation(dm_key);
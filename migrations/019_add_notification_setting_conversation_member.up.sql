@@ -0,0 +1,3 @@
+alter table conversation_member add column notification_level text not null default 'ALL';
+alter table conversation_member add column muted_until timestamptz;
+alter table conversation_member add column muted_forever boolean not null default false;
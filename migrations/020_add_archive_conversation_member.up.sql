@@ -0,0 +1,2 @@
+alter table conversation_member add column archived_at timestamptz;
+alter table conversation_member add column history_cleared_at timestamptz;
@@ -0,0 +1,22 @@
+alter table conversation_member add column pinned_at timestamptz;
+
+create table if not exists conversation_folder (
+    id text primary key,
+    user_id text not null,
+    name text not null,
+    conversation_types text[] not null default '{}',
+    position int not null default 0,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+create index if not exists idx_user_id_conversation_folder on conversation_folder(user_id);
+
+create table if not exists conversation_folder_item (
+    folder_id text not null,
+    conversation_id text not null,
+    created_at timestamptz default current_timestamp,
+    primary key (folder_id, conversation_id)
+);
+
+create index if not exists idx_conversation_id_conversation_folder_item on conversation_folder_item(conversation_id);
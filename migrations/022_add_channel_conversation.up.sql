@@ -0,0 +1,16 @@
+alter table conversation add column is_public boolean not null default false;
+alter table conversation add column description text not null default '';
+
+create index if not exists idx_type_is_public_conversation on conversation(type, is_public) where deleted_at is null;
+
+create table if not exists message_reaction (
+    id text primary key,
+    message_id text not null,
+    conversation_id text not null,
+    user_id text not null,
+    emoji text not null,
+    created_at timestamptz default current_timestamp,
+    unique (message_id, user_id, emoji)
+);
+
+create index if not exists idx_message_id_message_reaction on message_reaction(message_id);
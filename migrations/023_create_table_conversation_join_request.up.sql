@@ -0,0 +1,17 @@
+alter table conversation add column require_approval boolean not null default false;
+
+create table if not exists conversation_join_request (
+    id text primary key,
+    conversation_id text not null,
+    user_id text not null,
+    invite_link_id text,
+    status text not null default 'PENDING',
+    reviewed_by text,
+    reviewed_at timestamptz,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+-- a user has at most one pending request per conversation
+create unique index if not exists idx_pending_conversation_join_request on conversation_join_request(conversation_id, user_id) where status = 'PENDING';
+create index if not exists idx_conversation_id_conversation_join_request on conversation_join_request(conversation_id, status);
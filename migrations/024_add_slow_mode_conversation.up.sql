@@ -0,0 +1,3 @@
+-- 0 disables the slow mode and the daily cap
+alter table conversation add column slow_mode_seconds integer not null default 0;
+alter table conversation add column daily_message_limit integer not null default 0;
@@ -0,0 +1,2 @@
+-- members are paged by user id inside a conversation
+create index if not exists idx_conversation_id_user_id_conversation_member on conversation_member(conversation_id, user_id);
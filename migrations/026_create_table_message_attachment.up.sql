@@ -0,0 +1,34 @@
+-- index of the media, files and links shared in the messages, filled when a message is sent
+create table if not exists message_attachment (
+    id text primary key, -- <message_id>:<position>, sorts like the message ids
+    message_id text not null,
+    conversation_id text not null,
+    user_id text not null,
+    kind text not null,
+    message_type text not null,
+    url text not null,
+    created_at timestamptz default current_timestamp
+);
+
+create index if not exists idx_conversation_id_kind_message_attachment on message_attachment(conversation_id, kind, id);
+create index if not exists idx_conversation_id_kind_user_id_message_attachment on message_attachment(conversation_id, kind, user_id, id);
+
+-- index the messages sent before the table existed
+insert into message_attachment (id, message_id, conversation_id, user_id, kind, message_type, url, created_at)
+select m.id || ':00', m.id, m.conversation_id, m.user_id,
+    case when m.type in ('image', 'video') then 'MEDIA' else 'FILE' end,
+    m.type, m.body, m.created_at
+from message m
+where m.type in ('image', 'video', 'file', 'audio')
+on conflict (id) do nothing;
+
+insert into message_attachment (id, message_id, conversation_id, user_id, kind, message_type, url, created_at)
+select l.id || ':' || lpad((l.position - 1)::text, 2, '0'), l.id, l.conversation_id, l.user_id, 'LINK', l.type, l.url, l.created_at
+from (
+    select m.id, m.conversation_id, m.user_id, m.type, m.created_at, rtrim(link.match[1], '.,;:!?)') as url, link.position
+    from message m
+    cross join lateral regexp_matches(m.body, '(https?://[^\s<>"]+)', 'g') with ordinality as link(match, position)
+    where m.type = 'text'
+) l
+where l.position <= 20
+on conflict (id) do nothing;
@@ -0,0 +1,13 @@
+-- log of the realtime events, replayed by the clients that were offline
+create table if not exists sync_event (
+    id bigserial primary key,
+    conversation_id text, -- events of a conversation go to its members
+    user_ids text[],      -- events of the users themselves
+    type text not null,
+    payload jsonb not null default '{}',
+    created_at timestamptz default current_timestamp
+);
+
+create index if not exists idx_conversation_id_sync_event on sync_event(conversation_id, id);
+create index if not exists idx_user_ids_sync_event on sync_event using gin(user_ids);
+create index if not exists idx_created_at_sync_event on sync_event(created_at);
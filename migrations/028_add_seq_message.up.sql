@@ -0,0 +1,19 @@
+-- gap-free sequence of the messages of every conversation, taken from conversation.last_message_seq when a message is inserted
+alter table conversation add column last_message_seq bigint not null default 0;
+alter table message add column seq bigint;
+alter table seen_message add column message_seq bigint not null default 0;
+
+-- number the messages sent before, in the order they were listed until now
+update message m set seq = n.seq
+from (select id, row_number() over (partition by conversation_id order by id) as seq from message) n
+where m.id = n.id;
+
+update conversation c set last_message_seq = coalesce((select max(m.seq) from message m where m.conversation_id = c.id), 0);
+
+update seen_message sm set message_seq = m.seq
+from message m
+where m.id = sm.message_id;
+
+alter table message alter column seq set not null;
+
+create unique index if not exists idx_conversation_id_seq_message on message(conversation_id, seq);
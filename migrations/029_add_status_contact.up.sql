@@ -0,0 +1,5 @@
+-- a contact starts as a friend request from user_id to friend_id, the accepted contacts have a row in both directions
+alter table contact add column status text not null default 'ACCEPTED';
+
+create unique index if not exists idx_user_id_friend_id_contact on contact(user_id, friend_id);
+create index if not exists idx_friend_id_status_contact on contact(friend_id, status);
@@ -0,0 +1,11 @@
+create table if not exists user_block (
+    id text primary key,
+    user_id text not null,
+    blocked_user_id text not null,
+    created_at timestamptz default current_timestamp,
+    foreign key (user_id) references user_info(id),
+    foreign key (blocked_user_id) references user_info(id)
+);
+
+create unique index if not exists idx_user_id_blocked_user_id_user_block on user_block(user_id, blocked_user_id);
+create index if not exists idx_blocked_user_id_user_block on user_block(blocked_user_id);
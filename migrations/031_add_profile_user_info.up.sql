@@ -0,0 +1,5 @@
+-- the handle is optional until the user picks one, it is unique regardless of the case
+alter table user_info add column handle text;
+alter table user_info add column bio text not null default '';
+
+create unique index if not exists idx_handle_user_info on user_info(lower(handle)) where handle is not null;
@@ -0,0 +1,13 @@
+-- only the sha256 of the token is stored, the token itself is only in the mail
+create table if not exists password_reset_token (
+    id text primary key,
+    account_id text not null,
+    token_hash text not null,
+    expired_at timestamptz not null,
+    used_at timestamptz,
+    created_at timestamptz default current_timestamp,
+    foreign key (account_id) references account(id)
+);
+
+create unique index if not exists idx_token_hash_password_reset_token on password_reset_token(token_hash);
+create index if not exists idx_account_id_password_reset_token on password_reset_token(account_id);
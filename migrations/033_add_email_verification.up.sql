@@ -0,0 +1,17 @@
+-- the accounts created before the verification are considered verified
+alter table account add column email_verified_at timestamptz;
+update account set email_verified_at = created_at where email_verified_at is null;
+
+-- only the sha256 of the token is stored, the token itself is only in the mail
+create table if not exists email_verification_token (
+    id text primary key,
+    account_id text not null,
+    token_hash text not null,
+    expired_at timestamptz not null,
+    used_at timestamptz,
+    created_at timestamptz default current_timestamp,
+    foreign key (account_id) references account(id)
+);
+
+create unique index if not exists idx_token_hash_email_verification_token on email_verification_token(token_hash);
+create index if not exists idx_account_id_created_at_email_verification_token on email_verification_token(account_id, created_at);
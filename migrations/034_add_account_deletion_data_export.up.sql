@@ -0,0 +1,20 @@
+-- an account scheduled for deletion is anonymized once deletion_scheduled_at is passed
+alter table account add column deletion_scheduled_at timestamptz;
+alter table account add column deleted_at timestamptz;
+alter table user_info add column deleted_at timestamptz;
+
+create index if not exists idx_deletion_scheduled_at_account on account(deletion_scheduled_at) where deletion_scheduled_at is not null and deleted_at is null;
+
+create table if not exists data_export (
+    id text primary key,
+    account_id text not null,
+    status text not null,
+    bucket_name text not null default '',
+    object_name text not null default '',
+    error text not null default '',
+    created_at timestamptz default current_timestamp,
+    completed_at timestamptz,
+    foreign key (account_id) references account(id)
+);
+
+create index if not exists idx_account_id_data_export on data_export(account_id);
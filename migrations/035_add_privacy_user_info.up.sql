@@ -0,0 +1,5 @@
+-- who can see the last seen and the avatar of the user, start a DM with them or add them to a group
+alter table user_info add column last_seen_privacy text not null default 'everyone';
+alter table user_info add column avatar_privacy text not null default 'everyone';
+alter table user_info add column direct_message_privacy text not null default 'everyone';
+alter table user_info add column group_add_privacy text not null default 'everyone';
@@ -0,0 +1,6 @@
+-- the custom status of the user, cleared by the clear-user-status job once status_expires_at is passed
+alter table user_info add column status_emoji text not null default '';
+alter table user_info add column status_text text not null default '';
+alter table user_info add column status_expires_at timestamptz;
+
+create index if not exists idx_status_expires_at_user_info on user_info(status_expires_at) where status_expires_at is not null;
@@ -0,0 +1,29 @@
+-- a bot is an INTERNAL user with its own account, managed by the user owning it
+create table if not exists bot (
+    id text primary key,
+    user_id text not null unique,
+    owner_id text not null,
+    webhook_url text not null default '',
+    webhook_secret text not null,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp,
+    deleted_at timestamptz,
+    foreign key (user_id) references user_info(id),
+    foreign key (owner_id) references user_info(id)
+);
+
+create index if not exists idx_owner_id_bot on bot(owner_id) where deleted_at is null;
+
+-- only the hash of the token is stored, the bot authenticates with "Authorization: Bot <token>"
+create table if not exists bot_token (
+    id text primary key,
+    bot_id text not null,
+    name text not null default '',
+    token_hash text not null unique,
+    created_at timestamptz default current_timestamp,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    foreign key (bot_id) references bot(id)
+);
+
+create index if not exists idx_bot_id_bot_token on bot_token(bot_id);
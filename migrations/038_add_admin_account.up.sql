@@ -0,0 +1,22 @@
+-- an admin can use the /admin routes, the first one is granted in the database:
+-- update account set role = 'admin' where username = '<email>';
+alter table account add column role text not null default 'user';
+-- a suspended account can log in again once suspended_until is passed, a banned one can not
+alter table account add column suspended_until timestamptz;
+alter table account add column banned_at timestamptz;
+alter table account add column restriction_reason text not null default '';
+
+-- every action of the admins is recorded, the rows are never updated or deleted
+create table if not exists admin_audit_log (
+    id text primary key,
+    admin_account_id text not null,
+    action text not null,
+    target_type text not null,
+    target_id text not null default '',
+    detail jsonb not null default '{}',
+    created_at timestamptz default current_timestamp,
+    foreign key (admin_account_id) references account(id)
+);
+
+create index if not exists idx_target_admin_audit_log on admin_audit_log(target_type, target_id);
+create index if not exists idx_admin_account_id_admin_audit_log on admin_audit_log(admin_account_id);
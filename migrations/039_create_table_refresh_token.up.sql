@@ -0,0 +1,12 @@
+-- the refresh tokens of a session, each one is exchanged once for a new access token and a new refresh token.
+-- a used token presented again has leaked, the whole session is revoked
+create table if not exists refresh_token (
+    id text primary key,
+    session_token text not null,
+    token_hash text not null unique,
+    created_at timestamptz default current_timestamp,
+    used_at timestamptz,
+    foreign key (session_token) references session(session_token)
+);
+
+create index if not exists idx_session_token_refresh_token on refresh_token(session_token);
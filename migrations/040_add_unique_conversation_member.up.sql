@@ -0,0 +1,9 @@
+-- concurrent joins could add the same user twice, the earliest membership is kept
+delete from conversation_member cm
+using conversation_member other
+where other.conversation_id = cm.conversation_id
+    and other.user_id = cm.user_id
+    and other.id < cm.id;
+
+drop index if exists idx_conversation_id_user_id_conversation_member;
+create unique index if not exists idx_conversation_id_user_id_conversation_member on conversation_member(conversation_id, user_id);
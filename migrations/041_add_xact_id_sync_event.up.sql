@@ -0,0 +1,14 @@
+-- the events are ordered by the transaction that inserted them, a sync only reads the events of the
+-- transactions older than every running one so that a late commit never lands behind a cursor
+alter table sync_event add column xact_id bigint not null default pg_current_xact_id()::text::bigint;
+
+drop index if exists idx_conversation_id_sync_event;
+create index if not exists idx_conversation_id_xact_id_sync_event on sync_event(conversation_id, xact_id, id);
+
+-- position of the latest purged event, a cursor behind it may have missed events
+create table if not exists sync_event_purge (
+    id boolean primary key default true check (id),
+    xact_id bigint not null,
+    event_id bigint not null,
+    updated_at timestamptz default current_timestamp
+);
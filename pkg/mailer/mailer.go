@@ -0,0 +1,16 @@
+package mailer
+
+import "context"
+
+// Mailer defines interface for sending emails
+type Mailer interface {
+	// Send delivers the message to its recipients
+	Send(ctx context.Context, message *Message) error
+}
+
+// Message is a plain text email
+type Message struct {
+	To      []string // Recipient addresses
+	Subject string   // Subject line
+	Body    string   // Plain text body
+}
@@ -0,0 +1,16 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// NewToken returns a URL safe random token built from byteLength random bytes.
+func NewToken(byteLength int) (string, error) {
+	b := make([]byte, byteLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
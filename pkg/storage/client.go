@@ -17,6 +17,9 @@ type ObjectStorage interface {
 	// DeleteObject removes an object from storage
 	DeleteObject(ctx context.Context, bucketName string, objectName string) error
 
+	// ObjectExists checks if an object exists in a bucket
+	ObjectExists(ctx context.Context, bucketName string, objectName string) (bool, error)
+
 	// ListObjects lists objects in a bucket with optional prefix
 	ListObjects(ctx context.Context, bucketName string, prefix string) ([]ObjectInfo, error)
 
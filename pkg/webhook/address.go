@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ErrForbiddenAddress is returned for the webhooks pointing inside the network of the server
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// IsForbiddenIP reports whether the IP is a loopback, private, link-local, unspecified or multicast address
+func IsForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// CheckHost resolves the host and fails with ErrForbiddenAddress when any of its addresses is forbidden
+func CheckHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if IsForbiddenIP(addr.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
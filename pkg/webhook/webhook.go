@@ -0,0 +1,17 @@
+package webhook
+
+import "context"
+
+// Sender defines interface for delivering events to webhooks
+type Sender interface {
+	// Send posts the event to the webhook, signed with its secret
+	Send(ctx context.Context, request *Request) error
+}
+
+// Request is a JSON event posted to a webhook
+type Request struct {
+	URL    string // Webhook address
+	Secret string // Key of the HMAC signature of the body
+	Event  string // Type of the event
+	Body   []byte // JSON body
+}